@@ -2,6 +2,8 @@ package db
 
 import (
 	"database/sql"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -27,8 +29,8 @@ func (m *Manager) UpsertAsset(ip string, os string, alive bool) (int64, error) {
 			return err
 		}
 
-		// Update
-		_, err = db.Exec("UPDATE assets SET os = ?, alive = ?, last_scan_time = ? WHERE id = ?", os, alive, time.Now(), id)
+		// Update, keep the known OS when the caller doesn't provide one
+		_, err = db.Exec("UPDATE assets SET os = COALESCE(NULLIF(?, ''), os), alive = ?, last_scan_time = ? WHERE id = ?", os, alive, time.Now(), id)
 		return err
 	})
 	return id, err
@@ -46,14 +48,37 @@ func (m *Manager) GetAssetIDByIP(ip string) (int64, error) {
 func (m *Manager) GetAsset(id int64) (*Asset, error) {
 	db := m.GetDB()
 	var a Asset
-	err := db.QueryRow("SELECT id, ip, os, alive, last_scan_time, created_at FROM assets WHERE id = ?", id).Scan(
-		&a.ID, &a.IP, &a.OS, &a.Alive, &a.LastScanTime, &a.CreatedAt)
+	err := db.QueryRow("SELECT id, ip, COALESCE(os, ''), alive, COALESCE(cdn, ''), COALESCE(hostname, ''), COALESCE(domain, ''), COALESCE(dns_domain, ''), COALESCE(os_build, ''), COALESCE(smb_signing, ''), last_scan_time, created_at FROM assets WHERE id = ?", id).Scan(
+		&a.ID, &a.IP, &a.OS, &a.Alive, &a.CDN, &a.Hostname, &a.Domain, &a.DNSDomain, &a.OSBuild, &a.SMBSigning, &a.LastScanTime, &a.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return &a, nil
 }
 
+// UpdateAssetCDN marks an asset as a CDN edge node (empty string clears the mark).
+func (m *Manager) UpdateAssetCDN(assetID int64, cdn string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec("UPDATE assets SET cdn = ? WHERE id = ?", cdn, assetID)
+		return err
+	})
+}
+
+// UpdateAssetHostInfo records host details gathered from SMB/NetBIOS. Empty values keep the existing data.
+func (m *Manager) UpdateAssetHostInfo(assetID int64, hostname, domain, dnsDomain, osBuild, smbSigning string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec(`UPDATE assets SET
+			hostname = COALESCE(NULLIF(?, ''), hostname),
+			domain = COALESCE(NULLIF(?, ''), domain),
+			dns_domain = COALESCE(NULLIF(?, ''), dns_domain),
+			os_build = COALESCE(NULLIF(?, ''), os_build),
+			smb_signing = COALESCE(NULLIF(?, ''), smb_signing)
+			WHERE id = ?`,
+			hostname, domain, dnsDomain, osBuild, smbSigning, assetID)
+		return err
+	})
+}
+
 // --- Ports ---
 
 // UpsertAssetPort inserts or updates a port for an asset.
@@ -140,7 +165,7 @@ func (m *Manager) GetWebServiceIDByURL(url string) (int64, error) {
 // GetWebServices retrieves web services for an asset.
 func (m *Manager) GetWebServices(assetID int64) ([]WebService, error) {
 	db := m.GetDB()
-	rows, err := db.Query("SELECT id, asset_id, port_id, url, title, server, fingerprints, updated_at FROM web_services WHERE asset_id = ?", assetID)
+	rows, err := db.Query("SELECT id, asset_id, port_id, url, title, server, fingerprints, COALESCE(cdn, ''), COALESCE(waf, ''), updated_at FROM web_services WHERE asset_id = ?", assetID)
 	if err != nil {
 		return nil, err
 	}
@@ -149,7 +174,7 @@ func (m *Manager) GetWebServices(assetID int64) ([]WebService, error) {
 	var services []WebService
 	for rows.Next() {
 		var s WebService
-		if err := rows.Scan(&s.ID, &s.AssetID, &s.PortID, &s.URL, &s.Title, &s.Server, &s.Fingerprints, &s.UpdatedAt); err != nil {
+		if err := rows.Scan(&s.ID, &s.AssetID, &s.PortID, &s.URL, &s.Title, &s.Server, &s.Fingerprints, &s.CDN, &s.WAF, &s.UpdatedAt); err != nil {
 			continue
 		}
 		services = append(services, s)
@@ -157,42 +182,131 @@ func (m *Manager) GetWebServices(assetID int64) ([]WebService, error) {
 	return services, nil
 }
 
-// --- Directories ---
-
-// AddWebDirectory adds a discovered directory.
-func (m *Manager) AddWebDirectory(webServiceID int64, path string, status, length int, title, contentType, redirect string) error {
-	return m.ExecTask(func(db *sql.DB) error {
-		_, err := db.Exec("INSERT INTO web_directories (web_service_id, path, status_code, content_length, title, content_type, redirect_url) VALUES (?, ?, ?, ?, ?, ?, ?)",
-			webServiceID, path, status, length, title, contentType, redirect)
-		return err
-	})
+// WebServiceFilter selects web services for batch tasks. Empty fields are ignored;
+// all non-empty fields are combined with AND.
+type WebServiceFilter struct {
+	IDs         []int64 `json:"ids"`
+	AssetIDs    []int64 `json:"asset_ids"`
+	Fingerprint string  `json:"fingerprint"` // Substring of fingerprints, server or title
+	Keyword     string  `json:"keyword"`     // Substring of the URL
 }
 
-// GetWebDirectories retrieves directories for a web service.
-func (m *Manager) GetWebDirectories(webServiceID int64) ([]WebDirectory, error) {
+// FindWebServices returns web services matching the filter.
+func (m *Manager) FindWebServices(filter WebServiceFilter) ([]WebService, error) {
 	db := m.GetDB()
-	rows, err := db.Query("SELECT id, web_service_id, path, status_code, content_length, title, content_type, redirect_url, created_at FROM web_directories WHERE web_service_id = ? ORDER BY path ASC", webServiceID)
+	query := "SELECT id, asset_id, port_id, url, title, server, fingerprints, COALESCE(cdn, ''), COALESCE(waf, ''), updated_at FROM web_services WHERE 1=1"
+	var args []interface{}
+	inClause := func(column string, ids []int64) {
+		placeholders := make([]string, len(ids))
+		for i, id := range ids {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		query += " AND " + column + " IN (" + strings.Join(placeholders, ",") + ")"
+	}
+	if len(filter.IDs) > 0 {
+		inClause("id", filter.IDs)
+	}
+	if len(filter.AssetIDs) > 0 {
+		inClause("asset_id", filter.AssetIDs)
+	}
+	if filter.Fingerprint != "" {
+		like := "%" + filter.Fingerprint + "%"
+		query += " AND (fingerprints LIKE ? OR server LIKE ? OR title LIKE ?)"
+		args = append(args, like, like, like)
+	}
+	if filter.Keyword != "" {
+		query += " AND url LIKE ?"
+		args = append(args, "%"+filter.Keyword+"%")
+	}
+	query += " ORDER BY asset_id, id"
+
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	var services []WebService
+	for rows.Next() {
+		var s WebService
+		if err := rows.Scan(&s.ID, &s.AssetID, &s.PortID, &s.URL, &s.Title, &s.Server, &s.Fingerprints, &s.CDN, &s.WAF, &s.UpdatedAt); err != nil {
+			continue
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+// UpdateWebServiceCDNWAF records the CDN/WAF detected in front of a web service.
+func (m *Manager) UpdateWebServiceCDNWAF(webServiceID int64, cdn, waf string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec("UPDATE web_services SET cdn = ?, waf = ?, updated_at = ? WHERE id = ?", cdn, waf, time.Now(), webServiceID)
+		return err
+	})
+}
+
+// --- Directories ---
+
+// UpsertWebDirectory records a discovered directory, keyed by (web_service_id, path, method).
+// Re-scans refresh last_seen; changed is set when the status code or size differs from the previous scan.
+func (m *Manager) UpsertWebDirectory(webServiceID int64, method, path string, status, length int, title, contentType, redirect, contentHash string) (bool, error) {
+	var changed bool
+	err := m.ExecTask(func(db *sql.DB) error {
+		now := time.Now()
+		var id int64
+		var oldStatus, oldLength int
+		err := db.QueryRow("SELECT id, COALESCE(status_code, 0), COALESCE(content_length, 0) FROM web_directories WHERE web_service_id = ? AND path = ? AND method = ?",
+			webServiceID, path, method).Scan(&id, &oldStatus, &oldLength)
+		if err == sql.ErrNoRows {
+			_, err = db.Exec(`INSERT INTO web_directories (web_service_id, path, method, status_code, content_length, title, content_type, redirect_url, content_hash, changed, first_seen, last_seen)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, 0, ?, ?)`,
+				webServiceID, path, method, status, length, title, contentType, redirect, contentHash, now, now)
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		changed = oldStatus != status || oldLength != length
+		_, err = db.Exec(`UPDATE web_directories SET status_code = ?, content_length = ?, title = ?, content_type = ?, redirect_url = ?, content_hash = ?, changed = ?, last_seen = ? WHERE id = ?`,
+			status, length, title, contentType, redirect, contentHash, changed, now, id)
+		return err
+	})
+	return changed, err
+}
+
+const webDirectoryColumns = `wd.id, wd.web_service_id, wd.path, COALESCE(wd.method, 'GET'), wd.status_code, wd.content_length, COALESCE(wd.title, ''), COALESCE(wd.content_type, ''), COALESCE(wd.redirect_url, ''),
+	COALESCE(wd.content_hash, ''), COALESCE(wd.changed, 0), wd.first_seen, wd.last_seen, wd.created_at`
+
+func scanWebDirectories(rows *sql.Rows) []WebDirectory {
 	var dirs []WebDirectory
 	for rows.Next() {
 		var d WebDirectory
-		if err := rows.Scan(&d.ID, &d.WebServiceID, &d.Path, &d.StatusCode, &d.ContentLength, &d.Title, &d.ContentType, &d.RedirectURL, &d.CreatedAt); err != nil {
+		if err := rows.Scan(&d.ID, &d.WebServiceID, &d.Path, &d.Method, &d.StatusCode, &d.ContentLength, &d.Title, &d.ContentType, &d.RedirectURL,
+			&d.ContentHash, &d.Changed, &d.FirstSeen, &d.LastSeen, &d.CreatedAt); err != nil {
 			continue
 		}
 		dirs = append(dirs, d)
 	}
-	return dirs, nil
+	return dirs
+}
+
+// GetWebDirectories retrieves directories for a web service.
+func (m *Manager) GetWebDirectories(webServiceID int64) ([]WebDirectory, error) {
+	db := m.GetDB()
+	rows, err := db.Query("SELECT "+webDirectoryColumns+" FROM web_directories wd WHERE wd.web_service_id = ? ORDER BY wd.path ASC", webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanWebDirectories(rows), nil
 }
 
 // GetAssetWebDirectories retrieves all directories for an asset (across all web services).
 func (m *Manager) GetAssetWebDirectories(assetID int64) ([]WebDirectory, error) {
 	db := m.GetDB()
 	query := `
-		SELECT wd.id, wd.web_service_id, wd.path, wd.status_code, wd.content_length, wd.title, wd.content_type, wd.redirect_url, wd.created_at 
+		SELECT ` + webDirectoryColumns + `
 		FROM web_directories wd
 		JOIN web_services ws ON wd.web_service_id = ws.id
 		WHERE ws.asset_id = ?
@@ -203,16 +317,385 @@ func (m *Manager) GetAssetWebDirectories(assetID int64) ([]WebDirectory, error)
 		return nil, err
 	}
 	defer rows.Close()
+	return scanWebDirectories(rows), nil
+}
 
-	var dirs []WebDirectory
+// GetWebDirectoryURL returns the method and absolute URL of a stored directory.
+func (m *Manager) GetWebDirectoryURL(id int64) (string, string, error) {
+	db := m.GetDB()
+	var method, base, path string
+	err := db.QueryRow(`SELECT COALESCE(wd.method, 'GET'), ws.url, wd.path FROM web_directories wd
+		JOIN web_services ws ON wd.web_service_id = ws.id WHERE wd.id = ?`, id).Scan(&method, &base, &path)
+	if err != nil {
+		return "", "", err
+	}
+	return method, strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/"), nil
+}
+
+// GetWebDirectoryGroups groups the directories of a web service by content hash,
+// largest groups first, so that identical pages (e.g. soft 404s) can be spotted at a glance.
+func (m *Manager) GetWebDirectoryGroups(webServiceID int64) ([]WebDirectoryGroup, error) {
+	db := m.GetDB()
+	rows, err := db.Query(`
+		SELECT content_hash, MAX(status_code), MAX(content_length), COUNT(*), GROUP_CONCAT(path, char(10))
+		FROM web_directories
+		WHERE web_service_id = ? AND content_hash != ''
+		GROUP BY content_hash
+		ORDER BY COUNT(*) DESC`, webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []WebDirectoryGroup
 	for rows.Next() {
-		var d WebDirectory
-		if err := rows.Scan(&d.ID, &d.WebServiceID, &d.Path, &d.StatusCode, &d.ContentLength, &d.Title, &d.ContentType, &d.RedirectURL, &d.CreatedAt); err != nil {
+		g := WebDirectoryGroup{WebServiceID: webServiceID}
+		var paths string
+		if err := rows.Scan(&g.ContentHash, &g.StatusCode, &g.ContentLength, &g.Count, &paths); err != nil {
 			continue
 		}
-		dirs = append(dirs, d)
+		g.Paths = strings.Split(paths, "\n")
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// --- URLs & Forms ---
+
+// UpsertWebURL records a discovered URL. A non-zero status or content type overwrites the stored one.
+func (m *Manager) UpsertWebURL(webServiceID int64, rawURL, source string, status int, contentType string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec(`INSERT INTO web_urls (web_service_id, url, source, status_code, content_type, first_seen, last_seen) VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(web_service_id, url) DO UPDATE SET
+				status_code = CASE WHEN excluded.status_code != 0 THEN excluded.status_code ELSE status_code END,
+				content_type = COALESCE(NULLIF(excluded.content_type, ''), content_type),
+				last_seen = excluded.last_seen`,
+			webServiceID, rawURL, source, status, contentType, time.Now(), time.Now())
+		return err
+	})
+}
+
+// GetWebURLs retrieves discovered URLs for a web service.
+func (m *Manager) GetWebURLs(webServiceID int64) ([]WebURL, error) {
+	db := m.GetDB()
+	rows, err := db.Query("SELECT id, web_service_id, url, COALESCE(source, ''), COALESCE(status_code, 0), COALESCE(content_type, ''), first_seen, last_seen FROM web_urls WHERE web_service_id = ? ORDER BY url ASC", webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []WebURL
+	for rows.Next() {
+		var u WebURL
+		if err := rows.Scan(&u.ID, &u.WebServiceID, &u.URL, &u.Source, &u.StatusCode, &u.ContentType, &u.FirstSeen, &u.LastSeen); err != nil {
+			continue
+		}
+		urls = append(urls, u)
 	}
-	return dirs, nil
+	return urls, nil
+}
+
+// GetWebURL returns a stored URL by ID.
+func (m *Manager) GetWebURL(id int64) (string, error) {
+	db := m.GetDB()
+	var u string
+	err := db.QueryRow("SELECT url FROM web_urls WHERE id = ?", id).Scan(&u)
+	return u, err
+}
+
+// UpsertWebForm records an HTML form, keyed by (web_service_id, page_url, action, method).
+func (m *Manager) UpsertWebForm(webServiceID int64, pageURL, action, method, enctype, fields string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec(`INSERT INTO web_forms (web_service_id, page_url, action, method, enctype, fields) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(web_service_id, page_url, action, method) DO UPDATE SET enctype = excluded.enctype, fields = excluded.fields`,
+			webServiceID, pageURL, action, method, enctype, fields)
+		return err
+	})
+}
+
+// GetWebForms retrieves forms for a web service.
+func (m *Manager) GetWebForms(webServiceID int64) ([]WebForm, error) {
+	db := m.GetDB()
+	rows, err := db.Query("SELECT id, web_service_id, page_url, action, COALESCE(method, 'GET'), COALESCE(enctype, ''), COALESCE(fields, ''), created_at FROM web_forms WHERE web_service_id = ? ORDER BY page_url ASC", webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var forms []WebForm
+	for rows.Next() {
+		var f WebForm
+		if err := rows.Scan(&f.ID, &f.WebServiceID, &f.PageURL, &f.Action, &f.Method, &f.Enctype, &f.Fields, &f.CreatedAt); err != nil {
+			continue
+		}
+		forms = append(forms, f)
+	}
+	return forms, nil
+}
+
+// --- Parameters ---
+
+// UpsertWebParam records a parameter name, keyed by (web_service_id, url, name, location).
+func (m *Manager) UpsertWebParam(webServiceID int64, rawURL, name, location, reason string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec(`INSERT INTO web_params (web_service_id, url, name, location, reason, first_seen, last_seen) VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(web_service_id, url, name, location) DO UPDATE SET
+				reason = COALESCE(NULLIF(excluded.reason, ''), reason),
+				last_seen = excluded.last_seen`,
+			webServiceID, rawURL, name, location, reason, time.Now(), time.Now())
+		return err
+	})
+}
+
+// GetWebParams retrieves harvested and mined parameters for a web service.
+func (m *Manager) GetWebParams(webServiceID int64) ([]WebParam, error) {
+	db := m.GetDB()
+	rows, err := db.Query("SELECT id, web_service_id, url, name, location, COALESCE(reason, ''), first_seen, last_seen FROM web_params WHERE web_service_id = ? ORDER BY location ASC, url ASC, name ASC", webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var params []WebParam
+	for rows.Next() {
+		var p WebParam
+		if err := rows.Scan(&p.ID, &p.WebServiceID, &p.URL, &p.Name, &p.Location, &p.Reason, &p.FirstSeen, &p.LastSeen); err != nil {
+			continue
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+// GetWebParamNames returns the distinct parameter names known for a web service.
+func (m *Manager) GetWebParamNames(webServiceID int64) ([]string, error) {
+	db := m.GetDB()
+	rows, err := db.Query("SELECT DISTINCT name FROM web_params WHERE web_service_id = ? ORDER BY name ASC", webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// --- API Endpoints ---
+
+// UpsertAPIEndpoint records an API endpoint, keyed by (web_service_id, path, method).
+// Parameter names are merged with the stored ones; a non-zero status overwrites the last
+// observed status, content type, auth flag and response fingerprint.
+func (m *Manager) UpsertAPIEndpoint(e APIEndpoint) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		var stored string
+		err := db.QueryRow("SELECT COALESCE(params, '') FROM api_endpoints WHERE web_service_id = ? AND path = ? AND method = ?",
+			e.WebServiceID, e.Path, e.Method).Scan(&stored)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		params := mergeNames(stored, e.Params)
+
+		_, err = db.Exec(`INSERT INTO api_endpoints (web_service_id, path, method, params, source, source_file, status_code, content_type, auth_required, auth_scheme,
+				response_kind, response_length, response_hash, access, allow_methods, first_seen, last_seen)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(web_service_id, path, method) DO UPDATE SET
+				params = excluded.params,
+				source = COALESCE(NULLIF(source, ''), excluded.source),
+				source_file = COALESCE(NULLIF(source_file, ''), excluded.source_file),
+				status_code = CASE WHEN excluded.status_code != 0 THEN excluded.status_code ELSE status_code END,
+				content_type = CASE WHEN excluded.status_code != 0 THEN excluded.content_type ELSE content_type END,
+				auth_required = CASE WHEN excluded.status_code != 0 THEN excluded.auth_required ELSE auth_required END,
+				auth_scheme = COALESCE(NULLIF(excluded.auth_scheme, ''), auth_scheme),
+				response_kind = CASE WHEN excluded.status_code != 0 THEN excluded.response_kind ELSE response_kind END,
+				response_length = CASE WHEN excluded.status_code != 0 THEN excluded.response_length ELSE response_length END,
+				response_hash = CASE WHEN excluded.status_code != 0 THEN excluded.response_hash ELSE response_hash END,
+				access = CASE WHEN excluded.status_code != 0 THEN excluded.access ELSE access END,
+				allow_methods = COALESCE(NULLIF(excluded.allow_methods, ''), allow_methods),
+				last_seen = excluded.last_seen`,
+			e.WebServiceID, e.Path, e.Method, params, e.Source, e.SourceFile, e.StatusCode, e.ContentType, e.AuthRequired, e.AuthScheme,
+			e.ResponseKind, e.ResponseLength, e.ResponseHash, e.Access, e.AllowMethods, time.Now(), time.Now())
+		return err
+	})
+}
+
+// mergeNames merges names into a comma separated list, keeping the stored order.
+func mergeNames(stored string, names []string) string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, n := range append(strings.Split(stored, ","), names...) {
+		n = strings.TrimSpace(n)
+		if n != "" && !seen[n] {
+			seen[n] = true
+			list = append(list, n)
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+// GetAPIEndpoints retrieves the API inventory of a web service.
+func (m *Manager) GetAPIEndpoints(webServiceID int64) ([]APIEndpoint, error) {
+	return m.FindAPIEndpoints(APIEndpointFilter{WebServiceIDs: []int64{webServiceID}})
+}
+
+// APIEndpointFilter selects API endpoints. Empty fields are ignored; all non-empty fields are combined with AND.
+type APIEndpointFilter struct {
+	WebServiceIDs []int64  `json:"web_service_ids"`
+	ResponseKinds []string `json:"response_kinds"` // e.g. json-data, error-stack, login
+	Access        []string `json:"access"`         // e.g. unauthenticated, open
+	Source        string   `json:"source"`
+	Keyword       string   `json:"keyword"` // Substring of the path
+}
+
+// FindAPIEndpoints returns API endpoints matching the filter, e.g. every unauthenticated endpoint returning JSON data.
+func (m *Manager) FindAPIEndpoints(filter APIEndpointFilter) ([]APIEndpoint, error) {
+	db := m.GetDB()
+	query := `SELECT id, web_service_id, path, method, COALESCE(params, ''), COALESCE(source, ''), COALESCE(source_file, ''),
+		COALESCE(status_code, 0), COALESCE(content_type, ''), COALESCE(auth_required, 0), COALESCE(auth_scheme, ''),
+		COALESCE(response_kind, ''), COALESCE(response_length, 0), COALESCE(response_hash, ''), COALESCE(access, ''), COALESCE(allow_methods, ''),
+		first_seen, last_seen
+		FROM api_endpoints WHERE 1=1`
+	var args []interface{}
+	in := func(column string, values []interface{}) {
+		placeholders := make([]string, len(values))
+		for i := range values {
+			placeholders[i] = "?"
+		}
+		query += " AND " + column + " IN (" + strings.Join(placeholders, ",") + ")"
+		args = append(args, values...)
+	}
+	if len(filter.WebServiceIDs) > 0 {
+		values := make([]interface{}, len(filter.WebServiceIDs))
+		for i, id := range filter.WebServiceIDs {
+			values[i] = id
+		}
+		in("web_service_id", values)
+	}
+	for column, list := range map[string][]string{"response_kind": filter.ResponseKinds, "access": filter.Access} {
+		if len(list) > 0 {
+			values := make([]interface{}, len(list))
+			for i, v := range list {
+				values[i] = v
+			}
+			in(column, values)
+		}
+	}
+	if filter.Source != "" {
+		query += " AND source = ?"
+		args = append(args, filter.Source)
+	}
+	if filter.Keyword != "" {
+		query += " AND path LIKE ?"
+		args = append(args, "%"+filter.Keyword+"%")
+	}
+	query += " ORDER BY web_service_id ASC, path ASC, method ASC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []APIEndpoint
+	for rows.Next() {
+		var e APIEndpoint
+		var params string
+		if err := rows.Scan(&e.ID, &e.WebServiceID, &e.Path, &e.Method, &params, &e.Source, &e.SourceFile,
+			&e.StatusCode, &e.ContentType, &e.AuthRequired, &e.AuthScheme,
+			&e.ResponseKind, &e.ResponseLength, &e.ResponseHash, &e.Access, &e.AllowMethods,
+			&e.FirstSeen, &e.LastSeen); err != nil {
+			continue
+		}
+		if params != "" {
+			e.Params = strings.Split(params, ",")
+		}
+		endpoints = append(endpoints, e)
+	}
+	return endpoints, nil
+}
+
+// SaveAPIBasePaths stores the base paths tried for a web service. When one of them is verified,
+// previously verified prefixes of the service are reset so a single prefix stays selected.
+func (m *Manager) SaveAPIBasePaths(webServiceID int64, paths []APIBasePath) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, p := range paths {
+			if p.Verified {
+				if _, err := tx.Exec("UPDATE api_base_paths SET verified = 0 WHERE web_service_id = ?", webServiceID); err != nil {
+					return err
+				}
+				break
+			}
+		}
+		now := time.Now()
+		for _, p := range paths {
+			_, err := tx.Exec(`INSERT INTO api_base_paths (web_service_id, base_path, source_file, hits, samples, verified, first_seen, last_seen)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+				ON CONFLICT(web_service_id, base_path) DO UPDATE SET
+					source_file = COALESCE(NULLIF(source_file, ''), excluded.source_file),
+					hits = excluded.hits,
+					samples = excluded.samples,
+					verified = excluded.verified,
+					last_seen = excluded.last_seen`,
+				webServiceID, p.BasePath, p.SourceFile, p.Hits, p.Samples, p.Verified, now, now)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// GetAPIBasePaths retrieves the base paths of a web service, the verified one first.
+func (m *Manager) GetAPIBasePaths(webServiceID int64) ([]APIBasePath, error) {
+	db := m.GetDB()
+	rows, err := db.Query(`SELECT id, web_service_id, base_path, COALESCE(source_file, ''), COALESCE(hits, 0), COALESCE(samples, 0), COALESCE(verified, 0), first_seen, last_seen
+		FROM api_base_paths WHERE web_service_id = ? ORDER BY verified DESC, hits DESC, base_path ASC`, webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []APIBasePath
+	for rows.Next() {
+		var p APIBasePath
+		if err := rows.Scan(&p.ID, &p.WebServiceID, &p.BasePath, &p.SourceFile, &p.Hits, &p.Samples, &p.Verified, &p.FirstSeen, &p.LastSeen); err != nil {
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// GetAPIEndpointURL returns the method, absolute URL and parameter names of a stored endpoint.
+func (m *Manager) GetAPIEndpointURL(id int64) (string, string, []string, error) {
+	db := m.GetDB()
+	var method, base, path, params string
+	err := db.QueryRow(`SELECT ae.method, ws.url, ae.path, COALESCE(ae.params, '') FROM api_endpoints ae
+		JOIN web_services ws ON ae.web_service_id = ws.id WHERE ae.id = ?`, id).Scan(&method, &base, &path, &params)
+	if err != nil {
+		return "", "", nil, err
+	}
+	var names []string
+	if params != "" {
+		names = strings.Split(params, ",")
+	}
+	// web_services.url may carry the path of the page a scan started from, endpoints hang off its origin
+	if u, err := url.Parse(base); err == nil && u.Host != "" {
+		base = u.Scheme + "://" + u.Host
+	}
+	return method, strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/"), names, nil
 }
 
 // --- JS Files ---
@@ -274,25 +757,66 @@ func (m *Manager) GetAssetWebJSFiles(assetID int64) ([]WebJSFile, error) {
 
 // --- Sensitive Info ---
 
-// AddSensitiveResult adds a sensitive info finding.
-func (m *Manager) AddSensitiveResult(webServiceID int64, sourceFile, infoType, content, context string) error {
+// AddSensitiveResult adds a sensitive info finding, deduplicated by (source, type, value).
+// Re-running a scan refreshes the position and context; an earlier validation result is kept
+// unless the finding was validated again.
+func (m *Manager) AddSensitiveResult(r SensitiveResult) error {
+	var validatedAt interface{}
+	if r.ValidationStatus == "" || r.ValidationStatus == "unknown" {
+		r.ValidationStatus = "unknown"
+	} else {
+		validatedAt = time.Now()
+	}
 	return m.ExecTask(func(db *sql.DB) error {
-		_, err := db.Exec("INSERT INTO sensitive_results (web_service_id, source_file, info_type, content, context) VALUES (?, ?, ?, ?, ?)",
-			webServiceID, sourceFile, infoType, content, context)
+		_, err := db.Exec(`INSERT INTO sensitive_results (web_service_id, source_file, rule_id, info_type, content, context, byte_offset, line_number, column_number,
+				severity, entropy, validation_status, validation_detail, validated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(web_service_id, source_file, info_type, content) DO UPDATE SET
+				rule_id = excluded.rule_id,
+				context = excluded.context,
+				byte_offset = excluded.byte_offset,
+				line_number = excluded.line_number,
+				column_number = excluded.column_number,
+				severity = excluded.severity,
+				entropy = excluded.entropy,
+				validation_status = CASE WHEN excluded.validated_at IS NULL THEN sensitive_results.validation_status ELSE excluded.validation_status END,
+				validation_detail = CASE WHEN excluded.validated_at IS NULL THEN sensitive_results.validation_detail ELSE excluded.validation_detail END,
+				validated_at = COALESCE(excluded.validated_at, sensitive_results.validated_at)`,
+			r.WebServiceID, r.SourceFile, r.RuleID, r.InfoType, r.Content, r.Context, r.Offset, r.Line, r.Column,
+			r.Severity, r.Entropy, r.ValidationStatus, r.ValidationDetail, validatedAt)
 		return err
 	})
 }
 
+// UpdateSensitiveValidation records the live validation result of a finding ('valid', 'invalid', 'unknown').
+func (m *Manager) UpdateSensitiveValidation(id int64, status, detail string) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		_, err := db.Exec("UPDATE sensitive_results SET validation_status = ?, validation_detail = ?, validated_at = ? WHERE id = ?",
+			status, detail, time.Now(), id)
+		return err
+	})
+}
+
+const sensitiveResultColumns = `sr.id, sr.web_service_id, COALESCE(sr.source_file, ''), COALESCE(sr.rule_id, ''), sr.info_type, COALESCE(sr.content, ''), COALESCE(sr.context, ''),
+	COALESCE(sr.byte_offset, 0), COALESCE(sr.line_number, 0), COALESCE(sr.column_number, 0),
+	COALESCE(sr.severity, ''), COALESCE(sr.entropy, 0), COALESCE(sr.validation_status, 'unknown'), COALESCE(sr.validation_detail, ''), sr.validated_at, sr.created_at`
+
 // GetAssetSensitiveResults retrieves sensitive info for an asset.
 func (m *Manager) GetAssetSensitiveResults(assetID int64) ([]SensitiveResult, error) {
-	db := m.GetDB()
-	query := `
-		SELECT sr.id, sr.web_service_id, sr.source_file, sr.info_type, sr.content, sr.context, sr.created_at
+	return m.querySensitiveResults(`SELECT `+sensitiveResultColumns+`
 		FROM sensitive_results sr
 		JOIN web_services ws ON sr.web_service_id = ws.id
-		WHERE ws.asset_id = ?
-	`
-	rows, err := db.Query(query, assetID)
+		WHERE ws.asset_id = ?`, assetID)
+}
+
+// GetWebSensitiveResults retrieves sensitive info found on a web service.
+func (m *Manager) GetWebSensitiveResults(webServiceID int64) ([]SensitiveResult, error) {
+	return m.querySensitiveResults(`SELECT `+sensitiveResultColumns+` FROM sensitive_results sr WHERE sr.web_service_id = ?`, webServiceID)
+}
+
+func (m *Manager) querySensitiveResults(query string, args ...interface{}) ([]SensitiveResult, error) {
+	db := m.GetDB()
+	rows, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -301,9 +825,14 @@ func (m *Manager) GetAssetSensitiveResults(assetID int64) ([]SensitiveResult, er
 	var results []SensitiveResult
 	for rows.Next() {
 		var r SensitiveResult
-		if err := rows.Scan(&r.ID, &r.WebServiceID, &r.SourceFile, &r.InfoType, &r.Content, &r.Context, &r.CreatedAt); err != nil {
+		var validatedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.WebServiceID, &r.SourceFile, &r.RuleID, &r.InfoType, &r.Content, &r.Context, &r.Offset, &r.Line, &r.Column,
+			&r.Severity, &r.Entropy, &r.ValidationStatus, &r.ValidationDetail, &validatedAt, &r.CreatedAt); err != nil {
 			continue
 		}
+		if validatedAt.Valid {
+			r.ValidatedAt = &validatedAt.Time
+		}
 		results = append(results, r)
 	}
 	return results, nil
@@ -344,7 +873,7 @@ func (m *Manager) GetAssetAuthResults(assetID int64) ([]AuthResult, error) {
 
 func (m *Manager) GetAllAssets() ([]Asset, error) {
 	db := m.GetDB()
-	rows, err := db.Query("SELECT id, ip, os, alive, last_scan_time, created_at FROM assets ORDER BY last_scan_time DESC")
+	rows, err := db.Query("SELECT id, ip, COALESCE(os, ''), alive, COALESCE(cdn, ''), COALESCE(hostname, ''), COALESCE(domain, ''), COALESCE(dns_domain, ''), COALESCE(os_build, ''), COALESCE(smb_signing, ''), last_scan_time, created_at FROM assets ORDER BY last_scan_time DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +882,7 @@ func (m *Manager) GetAllAssets() ([]Asset, error) {
 	var assets []Asset
 	for rows.Next() {
 		var a Asset
-		if err := rows.Scan(&a.ID, &a.IP, &a.OS, &a.Alive, &a.LastScanTime, &a.CreatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.IP, &a.OS, &a.Alive, &a.CDN, &a.Hostname, &a.Domain, &a.DNSDomain, &a.OSBuild, &a.SMBSigning, &a.LastScanTime, &a.CreatedAt); err != nil {
 			continue
 		}
 		assets = append(assets, a)
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	_ "embed"
 	"fmt"
+	"strings"
 
 	_ "modernc.org/sqlite"
 )
@@ -29,6 +30,11 @@ func InitDB(path string) (*sql.DB, error) {
 		return nil, fmt.Errorf("执行数据库 Schema 失败: %w", err)
 	}
 
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("数据库迁移失败: %w", err)
+	}
+
 	// Enable WAL mode for better concurrency
 	if _, err := db.Exec("PRAGMA journal_mode=WAL;"); err != nil {
 		fmt.Printf("Warning: Failed to enable WAL mode: %v\n", err)
@@ -40,3 +46,61 @@ func InitDB(path string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// migrations 为旧版本数据库补齐新增的字段和索引
+// 新建数据库时 schema.sql 已包含这些字段，重复添加产生的 "duplicate column name" 错误会被忽略
+var migrations = []string{
+	"ALTER TABLE assets ADD COLUMN cdn TEXT DEFAULT ''",
+	"ALTER TABLE web_services ADD COLUMN cdn TEXT DEFAULT ''",
+	"ALTER TABLE web_services ADD COLUMN waf TEXT DEFAULT ''",
+	"ALTER TABLE assets ADD COLUMN hostname TEXT DEFAULT ''",
+	"ALTER TABLE assets ADD COLUMN domain TEXT DEFAULT ''",
+	"ALTER TABLE assets ADD COLUMN dns_domain TEXT DEFAULT ''",
+	"ALTER TABLE assets ADD COLUMN os_build TEXT DEFAULT ''",
+	"ALTER TABLE assets ADD COLUMN smb_signing TEXT DEFAULT ''",
+	"ALTER TABLE web_directories ADD COLUMN method TEXT DEFAULT 'GET'",
+	"ALTER TABLE web_directories ADD COLUMN content_hash TEXT DEFAULT ''",
+	"ALTER TABLE web_directories ADD COLUMN changed INTEGER DEFAULT 0",
+	"ALTER TABLE web_directories ADD COLUMN first_seen DATETIME",
+	"ALTER TABLE web_directories ADD COLUMN last_seen DATETIME",
+	"UPDATE web_directories SET first_seen = COALESCE(first_seen, created_at), last_seen = COALESCE(last_seen, created_at) WHERE first_seen IS NULL OR last_seen IS NULL",
+	// 旧版本每次扫描都会重复插入，建立唯一索引前只保留最新的一条
+	`DELETE FROM web_directories WHERE id NOT IN (
+		SELECT MAX(id) FROM web_directories GROUP BY web_service_id, path, COALESCE(method, 'GET'))`,
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_web_directories_unique ON web_directories(web_service_id, path, method)",
+	"CREATE INDEX IF NOT EXISTS idx_web_directories_hash ON web_directories(web_service_id, content_hash)",
+	"ALTER TABLE sensitive_results ADD COLUMN severity TEXT DEFAULT ''",
+	"ALTER TABLE sensitive_results ADD COLUMN entropy REAL DEFAULT 0",
+	"ALTER TABLE sensitive_results ADD COLUMN rule_id TEXT DEFAULT ''",
+	"ALTER TABLE sensitive_results ADD COLUMN validation_status TEXT DEFAULT 'unknown'",
+	"ALTER TABLE sensitive_results ADD COLUMN validation_detail TEXT DEFAULT ''",
+	"ALTER TABLE sensitive_results ADD COLUMN validated_at DATETIME",
+	"ALTER TABLE sensitive_results ADD COLUMN byte_offset INTEGER DEFAULT 0",
+	"ALTER TABLE sensitive_results ADD COLUMN line_number INTEGER DEFAULT 0",
+	"ALTER TABLE sensitive_results ADD COLUMN column_number INTEGER DEFAULT 0",
+	// 旧版本每次扫描都会重复插入，建立唯一索引前只保留最新的一条
+	`DELETE FROM sensitive_results WHERE id NOT IN (
+		SELECT MAX(id) FROM sensitive_results GROUP BY web_service_id, source_file, info_type, content)`,
+	"CREATE UNIQUE INDEX IF NOT EXISTS idx_sensitive_results_unique ON sensitive_results(web_service_id, source_file, info_type, content)",
+	"ALTER TABLE api_endpoints ADD COLUMN auth_scheme TEXT DEFAULT ''",
+	"ALTER TABLE api_endpoints ADD COLUMN response_kind TEXT DEFAULT ''",
+	"ALTER TABLE api_endpoints ADD COLUMN response_length INTEGER DEFAULT 0",
+	"ALTER TABLE api_endpoints ADD COLUMN response_hash TEXT DEFAULT ''",
+	"ALTER TABLE api_endpoints ADD COLUMN access TEXT DEFAULT ''",
+	"ALTER TABLE api_endpoints ADD COLUMN allow_methods TEXT DEFAULT ''",
+	"ALTER TABLE repeater_history ADD COLUMN request TEXT DEFAULT ''",
+	// 旧版本会把不带前缀的基准结果也写入 API 前缀表
+	"DELETE FROM api_base_paths WHERE base_path = ''",
+}
+
+func migrate(db *sql.DB) error {
+	for _, stmt := range migrations {
+		if _, err := db.Exec(stmt); err != nil {
+			if strings.Contains(err.Error(), "duplicate column name") {
+				continue
+			}
+			return fmt.Errorf("%s: %w", stmt, err)
+		}
+	}
+	return nil
+}
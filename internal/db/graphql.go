@@ -0,0 +1,131 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// SaveGraphQLSchema stores the schema of a GraphQL endpoint, keyed by (web_service_id, endpoint),
+// and replaces its operations. Returns the schema ID.
+func (m *Manager) SaveGraphQLSchema(s GraphQLSchema, ops []GraphQLOperation) (int64, error) {
+	var id int64
+	err := m.ExecTask(func(db *sql.DB) error {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		now := time.Now()
+		_, err = tx.Exec(`INSERT INTO graphql_schemas (web_service_id, endpoint, transport, source, schema, queries, mutations, first_seen, last_seen)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(web_service_id, endpoint) DO UPDATE SET
+				transport = excluded.transport,
+				source = excluded.source,
+				schema = excluded.schema,
+				queries = excluded.queries,
+				mutations = excluded.mutations,
+				last_seen = excluded.last_seen`,
+			s.WebServiceID, s.Endpoint, s.Transport, s.Source, s.Schema, s.Queries, s.Mutations, now, now)
+		if err != nil {
+			return err
+		}
+		if err := tx.QueryRow("SELECT id FROM graphql_schemas WHERE web_service_id = ? AND endpoint = ?", s.WebServiceID, s.Endpoint).Scan(&id); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec("DELETE FROM graphql_operations WHERE schema_id = ?", id); err != nil {
+			return err
+		}
+		for _, op := range ops {
+			_, err := tx.Exec(`INSERT OR IGNORE INTO graphql_operations (schema_id, kind, name, args, return_type, dangerous, reason)
+				VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				id, op.Kind, op.Name, strings.Join(op.Args, ","), op.ReturnType, op.Dangerous, op.Reason)
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+	return id, err
+}
+
+// GetGraphQLSchemas retrieves the GraphQL endpoints of a web service without the schema documents.
+func (m *Manager) GetGraphQLSchemas(webServiceID int64) ([]GraphQLSchema, error) {
+	db := m.GetDB()
+	rows, err := db.Query(`SELECT id, web_service_id, endpoint, COALESCE(transport, ''), COALESCE(source, ''), COALESCE(queries, 0), COALESCE(mutations, 0), first_seen, last_seen
+		FROM graphql_schemas WHERE web_service_id = ? ORDER BY endpoint ASC`, webServiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []GraphQLSchema
+	for rows.Next() {
+		var s GraphQLSchema
+		if err := rows.Scan(&s.ID, &s.WebServiceID, &s.Endpoint, &s.Transport, &s.Source, &s.Queries, &s.Mutations, &s.FirstSeen, &s.LastSeen); err != nil {
+			continue
+		}
+		schemas = append(schemas, s)
+	}
+	return schemas, nil
+}
+
+// GetGraphQLSchema retrieves a single schema including the schema document.
+func (m *Manager) GetGraphQLSchema(id int64) (*GraphQLSchema, error) {
+	db := m.GetDB()
+	var s GraphQLSchema
+	err := db.QueryRow(`SELECT id, web_service_id, endpoint, COALESCE(transport, ''), COALESCE(source, ''), COALESCE(schema, ''), COALESCE(queries, 0), COALESCE(mutations, 0), first_seen, last_seen
+		FROM graphql_schemas WHERE id = ?`, id).Scan(
+		&s.ID, &s.WebServiceID, &s.Endpoint, &s.Transport, &s.Source, &s.Schema, &s.Queries, &s.Mutations, &s.FirstSeen, &s.LastSeen)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// GetGraphQLOperations retrieves the operations of a schema, dangerous ones first.
+func (m *Manager) GetGraphQLOperations(schemaID int64) ([]GraphQLOperation, error) {
+	db := m.GetDB()
+	rows, err := db.Query(`SELECT id, schema_id, kind, name, COALESCE(args, ''), COALESCE(return_type, ''), COALESCE(dangerous, 0), COALESCE(reason, '')
+		FROM graphql_operations WHERE schema_id = ? ORDER BY dangerous DESC, kind ASC, name ASC`, schemaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ops []GraphQLOperation
+	for rows.Next() {
+		op, err := scanGraphQLOperation(rows)
+		if err != nil {
+			continue
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// GetGraphQLOperationRequest returns an operation together with the endpoint URL and transport of its schema.
+func (m *Manager) GetGraphQLOperationRequest(id int64) (GraphQLOperation, string, string, error) {
+	db := m.GetDB()
+	var endpoint, transport string
+	row := db.QueryRow(`SELECT o.id, o.schema_id, o.kind, o.name, COALESCE(o.args, ''), COALESCE(o.return_type, ''), COALESCE(o.dangerous, 0), COALESCE(o.reason, ''),
+		s.endpoint, COALESCE(s.transport, '')
+		FROM graphql_operations o JOIN graphql_schemas s ON o.schema_id = s.id WHERE o.id = ?`, id)
+	op, err := scanGraphQLOperation(row, &endpoint, &transport)
+	return op, endpoint, transport, err
+}
+
+func scanGraphQLOperation(row interface{ Scan(...any) error }, extra ...any) (GraphQLOperation, error) {
+	var op GraphQLOperation
+	var args string
+	dest := append([]any{&op.ID, &op.SchemaID, &op.Kind, &op.Name, &args, &op.ReturnType, &op.Dangerous, &op.Reason}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return op, err
+	}
+	if args != "" {
+		op.Args = strings.Split(args, ",")
+	}
+	return op, nil
+}
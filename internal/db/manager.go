@@ -3,12 +3,15 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"sync"
 )
 
 // Manager 数据库管理器，用于管理全局数据库连接实例
 type Manager struct {
 	db        *sql.DB
+	path      string // 当前项目数据库文件路径
 	mu        sync.RWMutex
 	writeChan chan func()
 }
@@ -62,3 +65,22 @@ func (m *Manager) GetDB() *sql.DB {
 	defer m.mu.RUnlock()
 	return m.db
 }
+
+// SetPath 记录当前项目数据库文件路径
+func (m *Manager) SetPath(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.path = path
+}
+
+// DataDir 返回当前项目的附属文件目录（与数据库同级的 <名称>_data），未加载项目时返回空串
+// 例如 runtime/data/jattack.db 对应 runtime/data/jattack_data
+func (m *Manager) DataDir() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.path == "" {
+		return ""
+	}
+	base := filepath.Base(m.path)
+	return filepath.Join(filepath.Dir(m.path), strings.TrimSuffix(base, filepath.Ext(base))+"_data")
+}
@@ -8,6 +8,12 @@ type Asset struct {
 	IP           string    `json:"ip"`
 	OS           string    `json:"os"`
 	Alive        bool      `json:"alive"`
+	CDN          string    `json:"cdn"`
+	Hostname     string    `json:"hostname"`
+	Domain       string    `json:"domain"`
+	DNSDomain    string    `json:"dns_domain"`
+	OSBuild      string    `json:"os_build"`
+	SMBSigning   string    `json:"smb_signing"`
 	LastScanTime time.Time `json:"last_scan_time"`
 	CreatedAt    time.Time `json:"created_at"`
 }
@@ -36,6 +42,8 @@ type WebService struct {
 	Server         string    `json:"server"`
 	Fingerprints   string    `json:"fingerprints"` // JSON string
 	ScreenshotPath string    `json:"screenshot_path"`
+	CDN            string    `json:"cdn"`
+	WAF            string    `json:"waf"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
@@ -44,14 +52,29 @@ type WebDirectory struct {
 	ID            int64     `json:"id"`
 	WebServiceID  int64     `json:"web_service_id"`
 	Path          string    `json:"path"`
+	Method        string    `json:"method"`
 	StatusCode    int       `json:"status_code"`
 	ContentLength int       `json:"content_length"`
 	Title         string    `json:"title"`
 	ContentType   string    `json:"content_type"`
 	RedirectURL   string    `json:"redirect_url"`
+	ContentHash   string    `json:"content_hash"`
+	Changed       bool      `json:"changed"` // Status or size differs from the previous scan
+	FirstSeen     time.Time `json:"first_seen"`
+	LastSeen      time.Time `json:"last_seen"`
 	CreatedAt     time.Time `json:"created_at"`
 }
 
+// WebDirectoryGroup groups directories of a web service that return identical content
+type WebDirectoryGroup struct {
+	WebServiceID  int64    `json:"web_service_id"`
+	ContentHash   string   `json:"content_hash"`
+	StatusCode    int      `json:"status_code"`
+	ContentLength int      `json:"content_length"`
+	Count         int      `json:"count"`
+	Paths         []string `json:"paths"`
+}
+
 // WebJSFile represents a JS file found on a web service
 type WebJSFile struct {
 	ID           int64     `json:"id"`
@@ -61,17 +84,52 @@ type WebJSFile struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
-// SensitiveResult represents sensitive info found
-type SensitiveResult struct {
+// WebURL represents a URL discovered by the crawler or metadata files
+type WebURL struct {
 	ID           int64     `json:"id"`
 	WebServiceID int64     `json:"web_service_id"`
-	SourceFile   string    `json:"source_file"`
-	InfoType     string    `json:"info_type"`
-	Content      string    `json:"content"`
-	Context      string    `json:"context"`
+	URL          string    `json:"url"`
+	Source       string    `json:"source"`
+	StatusCode   int       `json:"status_code"`
+	ContentType  string    `json:"content_type"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// WebForm represents an HTML form found on a page
+type WebForm struct {
+	ID           int64     `json:"id"`
+	WebServiceID int64     `json:"web_service_id"`
+	PageURL      string    `json:"page_url"`
+	Action       string    `json:"action"`
+	Method       string    `json:"method"`
+	Enctype      string    `json:"enctype"`
+	Fields       string    `json:"fields"` // JSON string
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+// SensitiveResult represents sensitive info found
+type SensitiveResult struct {
+	ID           int64   `json:"id"`
+	WebServiceID int64   `json:"web_service_id"`
+	SourceFile   string  `json:"source_file"`
+	RuleID       string  `json:"rule_id"`
+	InfoType     string  `json:"info_type"`
+	Content      string  `json:"content"`
+	Context      string  `json:"context"`
+	Offset       int     `json:"offset"` // byte offset of the value in the source
+	Line         int     `json:"line"`
+	Column       int     `json:"column"`
+	Severity     string  `json:"severity"`
+	Entropy      float64 `json:"entropy"`
+
+	ValidationStatus string     `json:"validation_status"` // valid / invalid / unknown
+	ValidationDetail string     `json:"validation_detail"`
+	ValidatedAt      *time.Time `json:"validated_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
 // AuthResult represents brute force results
 type AuthResult struct {
 	ID          int64     `json:"id"`
@@ -83,3 +141,91 @@ type AuthResult struct {
 	Success     bool      `json:"success"`
 	CreatedAt   time.Time `json:"created_at"`
 }
+
+// WebParam represents a parameter name harvested from JS or confirmed by the parameter miner
+type WebParam struct {
+	ID           int64     `json:"id"`
+	WebServiceID int64     `json:"web_service_id"`
+	URL          string    `json:"url"`
+	Name         string    `json:"name"`
+	Location     string    `json:"location"` // js, query, form, json
+	Reason       string    `json:"reason"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// APIEndpoint represents an entry of the API inventory fed by JSFinder, the crawler, dirscan and OpenAPI import
+type APIEndpoint struct {
+	ID             int64     `json:"id"`
+	WebServiceID   int64     `json:"web_service_id"`
+	Path           string    `json:"path"`   // path without query, '{name}' placeholders kept
+	Method         string    `json:"method"` // empty when unknown
+	Params         []string  `json:"params"`
+	Source         string    `json:"source"`      // jsfinder, crawler, dirscan, openapi
+	SourceFile     string    `json:"source_file"` // script, page or document it was found in
+	StatusCode     int       `json:"status_code"` // last observed status, 0 if never requested
+	ContentType    string    `json:"content_type"`
+	AuthRequired   bool      `json:"auth_required"`
+	AuthScheme     string    `json:"auth_scheme"`   // security scheme declared by API documentation, e.g. "bearer"
+	ResponseKind   string    `json:"response_kind"` // fingerprint of the last response: json-data, json-error, error-stack, login, html, xml, text, empty
+	ResponseLength int       `json:"response_length"`
+	ResponseHash   string    `json:"response_hash"`
+	Access         string    `json:"access"`        // unauthenticated, auth-required, open, different
+	AllowMethods   string    `json:"allow_methods"` // Allow header of an OPTIONS probe
+	FirstSeen      time.Time `json:"first_seen"`
+	LastSeen       time.Time `json:"last_seen"`
+}
+
+// APIBasePath represents a candidate API prefix of a web service and how many sample endpoints it resolved
+type APIBasePath struct {
+	ID           int64     `json:"id"`
+	WebServiceID int64     `json:"web_service_id"`
+	BasePath     string    `json:"base_path"`
+	SourceFile   string    `json:"source_file"`
+	Hits         int       `json:"hits"`
+	Samples      int       `json:"samples"`
+	Verified     bool      `json:"verified"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// GraphQLSchema represents a GraphQL endpoint and the schema obtained from it
+type GraphQLSchema struct {
+	ID           int64     `json:"id"`
+	WebServiceID int64     `json:"web_service_id"`
+	Endpoint     string    `json:"endpoint"`
+	Transport    string    `json:"transport"` // POST or GET
+	Source       string    `json:"source"`    // introspection or suggestion
+	Schema       string    `json:"schema"`    // JSON, empty in listings
+	Queries      int       `json:"queries"`
+	Mutations    int       `json:"mutations"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+}
+
+// GraphQLOperation represents a root field of a GraphQL schema
+type GraphQLOperation struct {
+	ID         int64    `json:"id"`
+	SchemaID   int64    `json:"schema_id"`
+	Kind       string   `json:"kind"` // query, mutation, subscription
+	Name       string   `json:"name"`
+	Args       []string `json:"args"`
+	ReturnType string   `json:"return_type"`
+	Dangerous  bool     `json:"dangerous"`
+	Reason     string   `json:"reason"`
+}
+
+// RepeaterHistory represents a request sent through the repeater
+type RepeaterHistory struct {
+	ID          int64     `json:"id"`
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	RawRequest  string    `json:"raw_request"`
+	RawResponse string    `json:"raw_response"`
+	StatusCode  int       `json:"status_code"`
+	DurationMs  int64     `json:"duration_ms"`
+	Error       string    `json:"error"`
+	Note        string    `json:"note"`
+	Request     string    `json:"request"` // JSON of the original request and its send settings, used for replay
+	CreatedAt   time.Time `json:"created_at"`
+}
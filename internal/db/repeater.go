@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+)
+
+// AddRepeaterHistory stores a request sent through the repeater. Returns the ID.
+func (m *Manager) AddRepeaterHistory(h RepeaterHistory) (int64, error) {
+	var id int64
+	err := m.ExecTask(func(db *sql.DB) error {
+		res, err := db.Exec(`INSERT INTO repeater_history (method, url, raw_request, raw_response, status_code, duration_ms, error, note, request, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			h.Method, h.URL, h.RawRequest, h.RawResponse, h.StatusCode, h.DurationMs, h.Error, h.Note, h.Request, time.Now())
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	return id, err
+}
+
+// GetRepeaterHistory retrieves the latest history entries without response bodies.
+func (m *Manager) GetRepeaterHistory(limit int) ([]RepeaterHistory, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	db := m.GetDB()
+	rows, err := db.Query(`SELECT id, COALESCE(method, ''), COALESCE(url, ''), COALESCE(raw_request, ''), COALESCE(status_code, 0), COALESCE(duration_ms, 0), COALESCE(error, ''), COALESCE(note, ''), created_at
+		FROM repeater_history ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []RepeaterHistory
+	for rows.Next() {
+		var h RepeaterHistory
+		if err := rows.Scan(&h.ID, &h.Method, &h.URL, &h.RawRequest, &h.StatusCode, &h.DurationMs, &h.Error, &h.Note, &h.CreatedAt); err != nil {
+			continue
+		}
+		history = append(history, h)
+	}
+	return history, nil
+}
+
+// GetRepeaterHistoryItem retrieves a single history entry including the raw response.
+func (m *Manager) GetRepeaterHistoryItem(id int64) (*RepeaterHistory, error) {
+	db := m.GetDB()
+	var h RepeaterHistory
+	err := db.QueryRow(`SELECT id, COALESCE(method, ''), COALESCE(url, ''), COALESCE(raw_request, ''), COALESCE(raw_response, ''), COALESCE(status_code, 0), COALESCE(duration_ms, 0), COALESCE(error, ''), COALESCE(note, ''), COALESCE(request, ''), created_at
+		FROM repeater_history WHERE id = ?`, id).Scan(
+		&h.ID, &h.Method, &h.URL, &h.RawRequest, &h.RawResponse, &h.StatusCode, &h.DurationMs, &h.Error, &h.Note, &h.Request, &h.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// DeleteRepeaterHistory removes a history entry; id <= 0 clears the whole history.
+func (m *Manager) DeleteRepeaterHistory(id int64) error {
+	return m.ExecTask(func(db *sql.DB) error {
+		if id <= 0 {
+			_, err := db.Exec("DELETE FROM repeater_history")
+			return err
+		}
+		_, err := db.Exec("DELETE FROM repeater_history WHERE id = ?", id)
+		return err
+	})
+}
@@ -65,6 +65,12 @@ CREATE TABLE IF NOT EXISTS assets (
     ip TEXT NOT NULL UNIQUE,
     os TEXT,
     alive BOOLEAN DEFAULT FALSE,
+    cdn TEXT DEFAULT '', -- CDN vendor if the IP is a CDN edge node
+    hostname TEXT DEFAULT '', -- From NTLM challenge / NetBIOS
+    domain TEXT DEFAULT '', -- NetBIOS domain or workgroup
+    dns_domain TEXT DEFAULT '',
+    os_build TEXT DEFAULT '', -- e.g. 10.0.19041
+    smb_signing TEXT DEFAULT '', -- 'required', 'enabled', 'disabled'
     last_scan_time DATETIME,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
@@ -95,6 +101,8 @@ CREATE TABLE IF NOT EXISTS web_services (
     server TEXT,
     fingerprints TEXT, -- JSON array of detected technologies
     screenshot_path TEXT,
+    cdn TEXT DEFAULT '', -- Detected CDN vendor
+    waf TEXT DEFAULT '', -- Detected WAF vendor
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY(asset_id) REFERENCES assets(id) ON DELETE CASCADE,
     FOREIGN KEY(port_id) REFERENCES asset_ports(id) ON DELETE CASCADE,
@@ -106,11 +114,16 @@ CREATE TABLE IF NOT EXISTS web_directories (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     web_service_id INTEGER NOT NULL,
     path TEXT NOT NULL,
+    method TEXT DEFAULT 'GET',
     status_code INTEGER,
     content_length INTEGER,
     title TEXT,
     content_type TEXT,
     redirect_url TEXT,
+    content_hash TEXT DEFAULT '',
+    changed INTEGER DEFAULT 0,
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
 );
@@ -133,7 +146,17 @@ CREATE TABLE IF NOT EXISTS sensitive_results (
     info_type TEXT NOT NULL, -- 'AWS', 'API Key', 'Email'
     content TEXT,
     context TEXT, -- Surrounding text
+    byte_offset INTEGER DEFAULT 0, -- position of the value in the source
+    line_number INTEGER DEFAULT 0,
+    column_number INTEGER DEFAULT 0,
+    severity TEXT DEFAULT '', -- 'critical', 'high', 'medium', 'low', 'info'
+    entropy REAL DEFAULT 0, -- Shannon entropy of the matched value
+    rule_id TEXT DEFAULT '', -- id of the detection rule
+    validation_status TEXT DEFAULT 'unknown', -- 'valid', 'invalid', 'unknown'
+    validation_detail TEXT DEFAULT '',
+    validated_at DATETIME,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, source_file, info_type, content),
     FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
 );
 
@@ -150,3 +173,129 @@ CREATE TABLE IF NOT EXISTS auth_results (
     FOREIGN KEY(asset_id) REFERENCES assets(id) ON DELETE CASCADE,
     FOREIGN KEY(port_id) REFERENCES asset_ports(id) ON DELETE CASCADE
 );
+
+-- 8. Discovered URLs (Crawler / robots.txt / sitemap ...)
+CREATE TABLE IF NOT EXISTS web_urls (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    web_service_id INTEGER NOT NULL,
+    url TEXT NOT NULL,
+    source TEXT, -- 'crawler', 'robots', 'sitemap' ...
+    status_code INTEGER DEFAULT 0,
+    content_type TEXT DEFAULT '',
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, url),
+    FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
+);
+
+-- 9. HTML Forms
+CREATE TABLE IF NOT EXISTS web_forms (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    web_service_id INTEGER NOT NULL,
+    page_url TEXT NOT NULL,
+    action TEXT NOT NULL,
+    method TEXT DEFAULT 'GET',
+    enctype TEXT DEFAULT '',
+    fields TEXT, -- JSON array of {name, type, value}
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, page_url, action, method),
+    FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
+);
+
+-- 10. Repeater History (per project database)
+CREATE TABLE IF NOT EXISTS repeater_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    method TEXT,
+    url TEXT,
+    raw_request TEXT,
+    raw_response TEXT,
+    status_code INTEGER DEFAULT 0,
+    duration_ms INTEGER DEFAULT 0,
+    error TEXT DEFAULT '',
+    note TEXT DEFAULT '',
+    request TEXT DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 11. Parameters (names harvested from JS and parameters confirmed by the miner)
+CREATE TABLE IF NOT EXISTS web_params (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    web_service_id INTEGER NOT NULL,
+    url TEXT NOT NULL DEFAULT '', -- endpoint for mined params, script URL for harvested names
+    name TEXT NOT NULL,
+    location TEXT NOT NULL, -- 'js', 'query', 'form', 'json'
+    reason TEXT DEFAULT '', -- why the miner kept it, e.g. 'status 200 -> 500', 'reflected'
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, url, name, location),
+    FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
+);
+
+-- 12. API Endpoints (inventory fed by JSFinder, crawler, dirscan and OpenAPI import)
+CREATE TABLE IF NOT EXISTS api_endpoints (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    web_service_id INTEGER NOT NULL,
+    path TEXT NOT NULL, -- path without query, '{name}' placeholders kept
+    method TEXT NOT NULL DEFAULT '', -- '' when unknown
+    params TEXT DEFAULT '', -- comma separated parameter names
+    source TEXT DEFAULT '', -- 'jsfinder', 'crawler', 'dirscan', 'openapi'
+    source_file TEXT DEFAULT '', -- script, page or document it was found in
+    status_code INTEGER DEFAULT 0, -- last observed status
+    content_type TEXT DEFAULT '',
+    auth_required INTEGER DEFAULT 0, -- last observed status was 401 / 403
+    auth_scheme TEXT DEFAULT '', -- security scheme declared by API documentation
+    response_kind TEXT DEFAULT '', -- fingerprint of the last response: 'json-data', 'json-error', 'error-stack', 'login', 'html', ...
+    response_length INTEGER DEFAULT 0,
+    response_hash TEXT DEFAULT '', -- SHA-1 of the last response body
+    access TEXT DEFAULT '', -- 'unauthenticated', 'auth-required', 'open', 'different'
+    allow_methods TEXT DEFAULT '', -- Allow header of an OPTIONS probe
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, path, method),
+    FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
+);
+
+-- 13. GraphQL Schemas (introspection result or schema recovered from field suggestions)
+CREATE TABLE IF NOT EXISTS graphql_schemas (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    web_service_id INTEGER NOT NULL,
+    endpoint TEXT NOT NULL, -- full endpoint URL
+    transport TEXT DEFAULT 'POST', -- 'POST' (JSON body) or 'GET' (query string)
+    source TEXT DEFAULT '', -- 'introspection' or 'suggestion'
+    schema TEXT DEFAULT '', -- JSON
+    queries INTEGER DEFAULT 0,
+    mutations INTEGER DEFAULT 0,
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, endpoint),
+    FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
+);
+
+-- 14. GraphQL Operations (root fields of the query / mutation / subscription types)
+CREATE TABLE IF NOT EXISTS graphql_operations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    schema_id INTEGER NOT NULL,
+    kind TEXT NOT NULL, -- 'query', 'mutation', 'subscription'
+    name TEXT NOT NULL,
+    args TEXT DEFAULT '', -- comma separated argument names
+    return_type TEXT DEFAULT '',
+    dangerous INTEGER DEFAULT 0,
+    reason TEXT DEFAULT '', -- why the operation is flagged, e.g. 'destructive'
+    UNIQUE(schema_id, kind, name),
+    FOREIGN KEY(schema_id) REFERENCES graphql_schemas(id) ON DELETE CASCADE
+);
+
+-- 15. API Base Paths (prefixes such as /prod-api declared in JS and verified by JSFinder)
+CREATE TABLE IF NOT EXISTS api_base_paths (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    web_service_id INTEGER NOT NULL,
+    base_path TEXT NOT NULL, -- declared prefix; the page-relative baseline without prefix is not stored
+    source_file TEXT DEFAULT '', -- script declaring the prefix
+    hits INTEGER DEFAULT 0, -- sample endpoints that exist under the prefix
+    samples INTEGER DEFAULT 0,
+    verified INTEGER DEFAULT 0, -- the prefix used for the service's endpoints
+    first_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    last_seen DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(web_service_id, base_path),
+    FOREIGN KEY(web_service_id) REFERENCES web_services(id) ON DELETE CASCADE
+);
@@ -0,0 +1,802 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"gopkg.in/yaml.v3"
+)
+
+// apiDocPaths 常见的 Swagger / OpenAPI 文档路径
+var apiDocPaths = []string{
+	"/swagger-resources",          // springfox 文档分组列表
+	"/v3/api-docs/swagger-config", // springdoc 文档分组列表
+	"/v2/api-docs",
+	"/v3/api-docs",
+	"/v1/api-docs",
+	"/api/v2/api-docs",
+	"/api/v3/api-docs",
+	"/api-docs",
+	"/api-docs.json",
+	"/api/api-docs",
+	"/swagger.json",
+	"/swagger.yaml",
+	"/swagger/v1/swagger.json", // ASP.NET Core Swashbuckle
+	"/swagger/v2/swagger.json",
+	"/swagger/docs/v1",  // ASP.NET Swashbuckle 5
+	"/swagger/doc.json", // Go swaggo
+	"/api/swagger.json",
+	"/api/swagger/v1/swagger.json",
+	"/docs/swagger.json",
+	"/openapi.json", // FastAPI 等
+	"/openapi.yaml",
+	"/api/openapi.json",
+	"/q/openapi",      // Quarkus
+	"/apispec_1.json", // Flasgger
+}
+
+const maxAPIDocGroups = 50 // 单个站点最多额外请求的分组文档数
+
+// APIDocConfig API 文档探测配置
+type APIDocConfig struct {
+	Targets     []string             `json:"targets"`      // 站点地址，可带应用上下文路径，例如 http://host/app
+	Services    *db.WebServiceFilter `json:"services"`     // 不为空时同时探测资产库中符合条件的 Web 服务
+	Paths       []string             `json:"paths"`        // 额外的文档路径
+	CheckAccess bool                 `json:"check_access"` // 对 GET 接口发送不带凭据的请求，检查是否存在未授权访问
+	Threads     int                  `json:"threads"`      // 并发数，默认 5
+	Timeout     int                  `json:"timeout"`      // 单次请求超时（毫秒），默认 10000
+
+	Request HTTPRequestOptions `json:"request"` // 获取文档时使用的请求头、Cookie 与认证，访问检查不携带认证
+}
+
+// APIDocOperation 文档中的单个接口
+type APIDocOperation struct {
+	Method          string   `json:"method"`
+	Path            string   `json:"path"` // 含 basePath，保留 {name} 占位符
+	URL             string   `json:"url"`
+	Params          []string `json:"params"`
+	AuthScheme      string   `json:"auth_scheme"` // 例如 "bearer"、"apiKey(header:X-Token)"，"none" 表示文档声明无需认证，为空表示未声明
+	Summary         string   `json:"summary"`
+	Deprecated      bool     `json:"deprecated"`
+	Status          int      `json:"status"`          // 访问检查的状态码，0 表示未请求
+	Unauthenticated bool     `json:"unauthenticated"` // 不带凭据请求返回 2xx
+
+	required    []string // 必填的查询参数，访问检查时填充
+	contentType string
+}
+
+// APIDocument 解析出的 Swagger / OpenAPI 文档，随 apiDoc:document 事件推送
+type APIDocument struct {
+	URL        string            `json:"url"`
+	Version    string            `json:"version"` // swagger / openapi 字段的版本号
+	Title      string            `json:"title"`
+	BaseURL    string            `json:"base_url"` // 接口基础地址，由 host + basePath 或 servers 得出
+	Operations []APIDocOperation `json:"operations"`
+}
+
+// APIDocResult 探测结果汇总，随 apiDoc:complete 事件推送
+type APIDocResult struct {
+	Targets         int           `json:"targets"`
+	Documents       []APIDocument `json:"documents"`
+	Operations      int           `json:"operations"`
+	Unauthenticated int           `json:"unauthenticated"`
+}
+
+type APIDocService struct {
+	ctx       context.Context
+	dbManager *db.Manager
+
+	mu         sync.Mutex // 保护 scanCancel
+	scanCancel context.CancelFunc
+}
+
+func NewAPIDocService(dbManager *db.Manager) *APIDocService {
+	return &APIDocService{
+		dbManager: dbManager,
+	}
+}
+
+func (s *APIDocService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// StartAPIDocScan 探测 API 文档并导入接口清单，结果通过 apiDoc:document / apiDoc:access / apiDoc:complete 事件推送
+func (s *APIDocService) StartAPIDocScan(config APIDocConfig) (int, error) {
+	targets, err := resolveScanTargets(s.dbManager, config.Targets, config.Services)
+	if err != nil {
+		return 0, err
+	}
+	config.Targets = targets
+
+	s.mu.Lock()
+	if s.scanCancel != nil {
+		s.scanCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.scanCancel = cancel
+	s.mu.Unlock()
+
+	go s.runScan(ctx, config)
+	return len(targets), nil
+}
+
+// StopAPIDocScan 停止当前 API 文档探测任务
+func (s *APIDocService) StopAPIDocScan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scanCancel != nil {
+		s.scanCancel()
+		s.scanCancel = nil
+		logger.Info("API 文档探测任务已停止")
+	}
+}
+
+// ImportAPIDoc 导入本地的 Swagger / OpenAPI 文档（JSON 或 YAML）
+// baseURL 为接口所在站点，为空时使用文档中声明的服务器地址
+func (s *APIDocService) ImportAPIDoc(baseURL, filePath string) (*APIDocument, error) {
+	body, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	origin := ""
+	if strings.TrimSpace(baseURL) != "" {
+		baseURL = normalizeDirTarget(strings.TrimSpace(baseURL))
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("目标地址无效: %s", baseURL)
+		}
+		origin = u.Scheme + "://" + u.Host
+	}
+
+	doc, err := parseAPIDoc(baseURL, origin, body)
+	if err != nil {
+		return nil, err
+	}
+	if u, err := url.Parse(doc.BaseURL); err != nil || u.Host == "" {
+		return nil, fmt.Errorf("文档未声明服务器地址，请提供目标地址")
+	}
+	doc.URL = filePath
+
+	s.recordDocument(newWebServiceCache(s.dbManager), doc)
+	logger.Info("已导入 API 文档", "文件", filePath, "接口数", len(doc.Operations))
+	return doc, nil
+}
+
+func (s *APIDocService) emitLog(msg string) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "apiDoc:log", msg)
+	}
+}
+
+func (s *APIDocService) runScan(ctx context.Context, config APIDocConfig) {
+	if config.Threads <= 0 {
+		config.Threads = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10000
+	}
+
+	cache := newWebServiceCache(s.dbManager)
+	result := APIDocResult{Targets: len(config.Targets), Documents: []APIDocument{}}
+	var mu sync.Mutex
+	defer func() {
+		s.emitLog(fmt.Sprintf("API 文档探测完成: %d 个站点，发现 %d 份文档、%d 个接口，%d 个接口可未授权访问",
+			result.Targets, len(result.Documents), result.Operations, result.Unauthenticated))
+		runtime.EventsEmit(s.ctx, "apiDoc:complete", result)
+	}()
+
+	logger.Info("开始探测 API 文档", "站点数", len(config.Targets), "访问检查", config.CheckAccess)
+	s.emitLog(fmt.Sprintf("开始探测 API 文档: %d 个站点", len(config.Targets)))
+
+	sem := make(chan struct{}, config.Threads)
+	var wg sync.WaitGroup
+	for _, target := range config.Targets {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(target string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			h := newAPIDocHarvester(ctx, config, target)
+			for _, doc := range h.Run() {
+				if config.CheckAccess && ctx.Err() == nil {
+					h.checkAccess(doc)
+				}
+				s.recordDocument(cache, doc)
+
+				mu.Lock()
+				result.Documents = append(result.Documents, *doc)
+				result.Operations += len(doc.Operations)
+				for _, op := range doc.Operations {
+					if op.Unauthenticated {
+						result.Unauthenticated++
+						runtime.EventsEmit(s.ctx, "apiDoc:access", op)
+					}
+				}
+				mu.Unlock()
+
+				s.emitLog(fmt.Sprintf("发现 API 文档: %s (%d 个接口)", doc.URL, len(doc.Operations)))
+				runtime.EventsEmit(s.ctx, "apiDoc:document", doc)
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// recordDocument 将文档地址写入 web_urls，接口写入接口清单
+func (s *APIDocService) recordDocument(cache *webServiceCache, doc *APIDocument) {
+	if s.dbManager == nil {
+		return
+	}
+	if docWS := cache.ID(doc.URL); docWS > 0 {
+		if err := s.dbManager.UpsertWebURL(docWS, doc.URL, "openapi", 200, ""); err != nil {
+			logger.Warn("保存 URL 失败", "url", doc.URL, "错误", err)
+		}
+	}
+
+	wsID := cache.ID(doc.BaseURL)
+	if wsID == 0 {
+		return
+	}
+	for _, op := range doc.Operations {
+		recordAPIEndpoint(s.dbManager, db.APIEndpoint{
+			WebServiceID: wsID,
+			Path:         op.Path,
+			Method:       op.Method,
+			Params:       op.Params,
+			Source:       "openapi",
+			SourceFile:   doc.URL,
+			StatusCode:   op.Status,
+			ContentType:  op.contentType,
+			AuthScheme:   op.AuthScheme,
+		})
+	}
+}
+
+// apiDocHarvester 在单个站点上探测文档路径，展开分组列表并解析文档
+type apiDocHarvester struct {
+	ctx    context.Context
+	config APIDocConfig
+	target string
+	client *http.Client
+}
+
+func newAPIDocHarvester(ctx context.Context, config APIDocConfig, target string) *apiDocHarvester {
+	return &apiDocHarvester{
+		ctx:    ctx,
+		config: config,
+		target: target,
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Millisecond,
+			Jar:     config.Request.newCookieJar(target),
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 3 {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Run 请求候选路径，返回去重后的文档
+func (h *apiDocHarvester) Run() []*APIDocument {
+	var queue []string
+	paths := append(append([]string{}, apiDocPaths...), h.config.Paths...)
+	for _, p := range paths {
+		queue = append(queue, h.target+"/"+strings.TrimLeft(p, "/"))
+	}
+	// 目标带有上下文路径时，站点根目录下的文档同样探测
+	if u, err := url.Parse(h.target); err == nil && strings.Trim(u.Path, "/") != "" {
+		for _, p := range paths {
+			queue = append(queue, u.Scheme+"://"+u.Host+"/"+strings.TrimLeft(p, "/"))
+		}
+	}
+
+	seen := make(map[string]bool)
+	hashes := make(map[string]bool)
+	var docs []*APIDocument
+	limit := len(queue) + maxAPIDocGroups
+	for fetched := 0; len(queue) > 0 && fetched < limit; {
+		if h.ctx.Err() != nil {
+			break
+		}
+		docURL := queue[0]
+		queue = queue[1:]
+		if seen[docURL] {
+			continue
+		}
+		seen[docURL] = true
+		fetched++
+
+		status, body, err := h.get(docURL)
+		if err != nil || status != 200 || len(body) == 0 {
+			continue
+		}
+		v, err := decodeAPIDoc(body)
+		if err != nil {
+			continue
+		}
+
+		// springfox /swagger-resources: [{"name": "default", "url": "/v2/api-docs?group=default"}]
+		if list, ok := v.([]any); ok {
+			for _, item := range list {
+				m := asMap(item)
+				loc := asString(m["url"])
+				if loc == "" {
+					loc = asString(m["location"])
+				}
+				if loc == "" {
+					continue
+				}
+				if !strings.HasPrefix(loc, "http://") && !strings.HasPrefix(loc, "https://") {
+					// 分组地址相对于应用上下文路径
+					loc = h.target + "/" + strings.TrimLeft(loc, "/")
+				}
+				queue = append(queue, loc)
+			}
+			continue
+		}
+
+		root := asMap(v)
+		if root == nil {
+			continue
+		}
+		// springdoc /v3/api-docs/swagger-config: {"urls": [{"url": "/v3/api-docs/group", "name": "group"}]}
+		if _, isDoc := root["paths"]; !isDoc {
+			for _, item := range asList(root["urls"]) {
+				if loc := asString(asMap(item)["url"]); loc != "" {
+					if full, err := resolveURL(docURL, loc); err == nil {
+						queue = append(queue, full)
+					}
+				}
+			}
+			if loc := asString(root["url"]); loc != "" {
+				if full, err := resolveURL(docURL, loc); err == nil {
+					queue = append(queue, full)
+				}
+			}
+			continue
+		}
+
+		// 同一份文档常同时挂在多个路径上
+		hash := contentHash(body)
+		if hashes[hash] {
+			continue
+		}
+		hashes[hash] = true
+
+		doc, err := parseAPIDoc(docURL, "", body)
+		if err != nil {
+			logger.Warn("解析 API 文档失败", "url", docURL, "错误", err)
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func (h *apiDocHarvester) get(docURL string) (int, []byte, error) {
+	req, err := h.config.Request.newRequest(h.ctx, "GET", docURL, "")
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Accept", "application/json, application/yaml, */*")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 20*1024*1024))
+	return resp.StatusCode, body, nil
+}
+
+var pathParamRegex = regexp.MustCompile(`\{[^{}/]+\}`)
+
+// checkAccess 对 GET 接口发送不带凭据的请求，路径参数与必填查询参数填充为 1
+func (h *apiDocHarvester) checkAccess(doc *APIDocument) {
+	client := &http.Client{
+		Timeout: time.Duration(h.config.Timeout) * time.Millisecond,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	sem := make(chan struct{}, h.config.Threads)
+	var wg sync.WaitGroup
+	for i := range doc.Operations {
+		op := &doc.Operations[i]
+		if op.Method != "GET" {
+			continue
+		}
+		if h.ctx.Err() != nil {
+			break
+		}
+		u, err := url.Parse(pathParamRegex.ReplaceAllString(op.URL, "1"))
+		if err != nil || u.Host == "" {
+			continue
+		}
+		q := u.Query()
+		for _, name := range op.required {
+			q.Set(name, "1")
+		}
+		u.RawQuery = q.Encode()
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(op *APIDocOperation, reqURL string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			req, err := http.NewRequestWithContext(h.ctx, "GET", reqURL, nil)
+			if err != nil {
+				return
+			}
+			req.Header.Set("User-Agent", defaultUserAgent)
+			req.Header.Set("Accept", "application/json, */*")
+			resp, err := client.Do(req)
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 1024*1024))
+			resp.Body.Close()
+			op.Status = resp.StatusCode
+			op.contentType = resp.Header.Get("Content-Type")
+			op.Unauthenticated = resp.StatusCode >= 200 && resp.StatusCode < 300
+		}(op, u.String())
+	}
+	wg.Wait()
+}
+
+// decodeAPIDoc 解析 JSON 或 YAML 文档
+func decodeAPIDoc(body []byte) (any, error) {
+	body = bytes.TrimSpace(body)
+	var v any
+	if len(body) > 0 && (body[0] == '{' || body[0] == '[') {
+		if err := json.Unmarshal(body, &v); err == nil {
+			return v, nil
+		}
+	}
+	if err := yaml.Unmarshal(body, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// apiDocParser 解析 OpenAPI 2 / 3 文档，$ref 只解析文档内部引用
+type apiDocParser struct {
+	root    map[string]any
+	schemes map[string]string // 安全方案名 -> 描述
+}
+
+var apiDocMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// parseAPIDoc 解析文档，docURL 用于解析相对的服务器地址；origin 不为空时覆盖文档声明的 scheme://host
+func parseAPIDoc(docURL, origin string, body []byte) (*APIDocument, error) {
+	v, err := decodeAPIDoc(body)
+	if err != nil {
+		return nil, err
+	}
+	root := asMap(v)
+	version := ""
+	if root != nil {
+		if root["swagger"] != nil {
+			version = fmt.Sprint(root["swagger"])
+		} else if root["openapi"] != nil {
+			version = fmt.Sprint(root["openapi"])
+		}
+	}
+	if version == "" || root["paths"] == nil {
+		return nil, fmt.Errorf("不是 Swagger / OpenAPI 文档")
+	}
+
+	p := &apiDocParser{root: root, schemes: make(map[string]string)}
+	defs := asMap(root["securityDefinitions"])
+	if comps := asMap(root["components"]); comps != nil {
+		defs = asMap(comps["securitySchemes"])
+	}
+	for name, def := range defs {
+		p.schemes[name] = describeSecurityScheme(p.deref(def))
+	}
+
+	doc := &APIDocument{
+		URL:        docURL,
+		Version:    version,
+		Title:      asString(asMap(root["info"])["title"]),
+		BaseURL:    p.baseURL(docURL, strings.HasPrefix(version, "2")),
+		Operations: []APIDocOperation{},
+	}
+	if origin != "" {
+		if u, err := url.Parse(doc.BaseURL); err == nil {
+			doc.BaseURL = origin + u.Path
+		}
+	}
+	doc.BaseURL = strings.TrimRight(doc.BaseURL, "/")
+	prefix := ""
+	if u, err := url.Parse(doc.BaseURL); err == nil {
+		prefix = strings.TrimRight(u.Path, "/")
+	}
+
+	paths := asMap(root["paths"])
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, path := range keys {
+		item := p.deref(paths[path])
+		if item == nil {
+			continue
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		for _, method := range apiDocMethods {
+			op := asMap(item[method])
+			if op == nil {
+				continue
+			}
+			o := APIDocOperation{
+				Method:     strings.ToUpper(method),
+				Path:       prefix + path,
+				URL:        doc.BaseURL + path,
+				Summary:    asString(op["summary"]),
+				Deprecated: op["deprecated"] == true,
+				AuthScheme: p.authScheme(op),
+			}
+			if o.Summary == "" {
+				o.Summary = asString(op["operationId"])
+			}
+			for _, m := range pathParamRegex.FindAllString(path, -1) {
+				o.Params = append(o.Params, strings.Trim(m, "{}"))
+			}
+			p.collectParams(&o, append(asList(item["parameters"]), asList(op["parameters"])...))
+			if rb := p.deref(op["requestBody"]); rb != nil {
+				content := asMap(rb["content"])
+				media := make([]string, 0, len(content))
+				for k := range content {
+					media = append(media, k)
+				}
+				sort.Strings(media)
+				for _, k := range media {
+					o.Params = append(o.Params, p.schemaProps(asMap(content[k])["schema"], 0)...)
+				}
+			}
+			o.Params = unique(o.Params)
+			doc.Operations = append(doc.Operations, o)
+		}
+	}
+	return doc, nil
+}
+
+// baseURL 计算接口基础地址：OpenAPI 2 使用 schemes / host / basePath，OpenAPI 3 使用第一个 servers
+// 服务器地址指向 localhost 时（常见于开发环境生成的文档）改用文档所在站点
+func (p *apiDocParser) baseURL(docURL string, v2 bool) string {
+	doc, _ := url.Parse(docURL)
+	if doc == nil {
+		doc = &url.URL{}
+	}
+	origin := ""
+	if doc.Host != "" {
+		origin = doc.Scheme + "://" + doc.Host
+	}
+
+	if v2 {
+		scheme := doc.Scheme
+		schemes := asList(p.root["schemes"])
+		if len(schemes) > 0 {
+			scheme = asString(schemes[0])
+			for _, sc := range schemes {
+				if asString(sc) == doc.Scheme && doc.Scheme != "" {
+					scheme = doc.Scheme
+				}
+			}
+		}
+		host := asString(p.root["host"])
+		if host == "" || isLoopbackHost(host) {
+			host = doc.Host
+		}
+		basePath := asString(p.root["basePath"])
+		if host == "" {
+			return basePath
+		}
+		if scheme == "" {
+			scheme = "http"
+		}
+		return scheme + "://" + host + "/" + strings.TrimLeft(basePath, "/")
+	}
+
+	servers := asList(p.root["servers"])
+	if len(servers) == 0 {
+		return origin
+	}
+	srv := asMap(servers[0])
+	raw := asString(srv["url"])
+	for name, v := range asMap(srv["variables"]) {
+		raw = strings.ReplaceAll(raw, "{"+name+"}", fmt.Sprint(asMap(v)["default"]))
+	}
+	if strings.Contains(raw, "{") {
+		return origin
+	}
+	full, err := resolveURL(docURL, raw)
+	if err != nil {
+		return origin
+	}
+	if u, err := url.Parse(full); err == nil && u.Host != "" && isLoopbackHost(u.Host) && origin != "" {
+		return origin + u.Path
+	}
+	return full
+}
+
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// collectParams 收集参数名：query / path / formData 直接取名称，body 参数展开 schema 属性，header 与 cookie 参数忽略
+func (p *apiDocParser) collectParams(o *APIDocOperation, params []any) {
+	for _, raw := range params {
+		prm := p.deref(raw)
+		name := asString(prm["name"])
+		switch asString(prm["in"]) {
+		case "body":
+			o.Params = append(o.Params, p.schemaProps(prm["schema"], 0)...)
+		case "query":
+			o.Params = append(o.Params, name)
+			if prm["required"] == true {
+				o.required = append(o.required, name)
+			}
+		case "path", "formData":
+			o.Params = append(o.Params, name)
+		}
+	}
+}
+
+// schemaProps 返回 schema 的属性名，展开 allOf / oneOf / anyOf 与数组元素
+func (p *apiDocParser) schemaProps(v any, depth int) []string {
+	s := p.deref(v)
+	if s == nil || depth > 3 {
+		return nil
+	}
+	var names []string
+	for name := range asMap(s["properties"]) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		for _, sub := range asList(s[key]) {
+			names = append(names, p.schemaProps(sub, depth+1)...)
+		}
+	}
+	if s["items"] != nil {
+		names = append(names, p.schemaProps(s["items"], depth+1)...)
+	}
+	return names
+}
+
+// authScheme 描述接口的安全要求，接口未声明时继承文档级声明
+func (p *apiDocParser) authScheme(op map[string]any) string {
+	reqs, ok := op["security"]
+	if !ok {
+		reqs, ok = p.root["security"]
+	}
+	if !ok {
+		return ""
+	}
+	var list []string
+	for _, req := range asList(reqs) {
+		m := asMap(req)
+		if len(m) == 0 {
+			list = append(list, "none") // {} 表示认证可选
+			continue
+		}
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			if d := p.schemes[name]; d != "" {
+				names[i] = d
+			}
+		}
+		list = append(list, strings.Join(names, "+"))
+	}
+	if len(list) == 0 {
+		return "none"
+	}
+	return strings.Join(unique(list), ", ")
+}
+
+func describeSecurityScheme(def map[string]any) string {
+	switch t := asString(def["type"]); t {
+	case "http":
+		if scheme := strings.ToLower(asString(def["scheme"])); scheme != "" {
+			return scheme
+		}
+		return "http"
+	case "apiKey":
+		return fmt.Sprintf("apiKey(%s:%s)", asString(def["in"]), asString(def["name"]))
+	default:
+		return t // basic、oauth2、openIdConnect、mutualTLS
+	}
+}
+
+// deref 解析文档内部的 $ref（#/definitions/...、#/components/...），最多跟随 10 层
+func (p *apiDocParser) deref(v any) map[string]any {
+	m := asMap(v)
+	for i := 0; i < 10 && m != nil; i++ {
+		ref := asString(m["$ref"])
+		if !strings.HasPrefix(ref, "#/") {
+			return m
+		}
+		var cur any = p.root
+		for _, part := range strings.Split(ref[2:], "/") {
+			part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+			cur = asMap(cur)[part]
+		}
+		m = asMap(cur)
+	}
+	return m
+}
+
+// asMap 兼容 JSON 与 YAML 解码出的对象类型
+func asMap(v any) map[string]any {
+	switch m := v.(type) {
+	case map[string]any:
+		return m
+	case map[any]any:
+		out := make(map[string]any, len(m))
+		for k, val := range m {
+			out[fmt.Sprint(k)] = val
+		}
+		return out
+	}
+	return nil
+}
+
+func asList(v any) []any {
+	list, _ := v.([]any)
+	return list
+}
+
+func asString(v any) string {
+	s, _ := v.(string)
+	return s
+}
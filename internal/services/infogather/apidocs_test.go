@@ -0,0 +1,203 @@
+package infogather
+
+import (
+	"reflect"
+	"testing"
+)
+
+const swagger2Doc = `{
+  "swagger": "2.0",
+  "info": {"title": "Pet Store"},
+  "host": "localhost:8080",
+  "basePath": "/api",
+  "schemes": ["http", "https"],
+  "securityDefinitions": {
+    "token": {"type": "apiKey", "in": "header", "name": "X-Token"}
+  },
+  "security": [{"token": []}],
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true}],
+      "get": {
+        "operationId": "getPet",
+        "parameters": [
+          {"name": "fields", "in": "query", "required": true},
+          {"name": "X-Trace", "in": "header"}
+        ]
+      },
+      "delete": {"summary": "Delete pet", "deprecated": true, "security": []}
+    },
+    "/pets": {
+      "post": {
+        "security": [{}],
+        "parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}}]
+      }
+    }
+  },
+  "definitions": {
+    "Pet": {
+      "allOf": [{"$ref": "#/definitions/Named"}],
+      "properties": {"tag": {"type": "string"}, "age": {"type": "integer"}}
+    },
+    "Named": {"properties": {"name": {"type": "string"}}}
+  }
+}`
+
+const openAPI3Doc = `openapi: 3.0.1
+info:
+  title: Users
+servers:
+  - url: "{scheme}://api.example.com/{version}"
+    variables:
+      scheme: {default: https}
+      version: {default: v2}
+paths:
+  /users:
+    get:
+      summary: List users
+      security:
+        - bearerAuth: []
+        - oauth: []
+      parameters:
+        - {name: page, in: query}
+        - {$ref: "#/components/parameters/Size"}
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: array
+              items: {$ref: "#/components/schemas/User"}
+  users/{userId}/avatar:
+    get: {}
+components:
+  securitySchemes:
+    bearerAuth: {type: http, scheme: Bearer}
+    oauth: {type: oauth2}
+  parameters:
+    Size: {name: size, in: query}
+  schemas:
+    User:
+      properties:
+        email: {type: string}
+        role: {type: string}
+`
+
+func TestParseAPIDoc(t *testing.T) {
+	tests := []struct {
+		name    string
+		docURL  string
+		origin  string
+		body    string
+		version string
+		baseURL string
+		ops     []APIDocOperation // 只比较方法、路径、地址、参数、认证、摘要与弃用标记
+	}{
+		{
+			name:    "Swagger 2 使用文档所在站点替换 localhost",
+			docURL:  "https://petstore.example.com/v2/api-docs",
+			body:    swagger2Doc,
+			version: "2.0",
+			baseURL: "https://petstore.example.com/api",
+			ops: []APIDocOperation{
+				{Method: "POST", Path: "/api/pets", URL: "https://petstore.example.com/api/pets", Params: []string{"age", "tag", "name"}, AuthScheme: "none"},
+				{Method: "GET", Path: "/api/pets/{id}", URL: "https://petstore.example.com/api/pets/{id}", Params: []string{"id", "fields"}, AuthScheme: "apiKey(header:X-Token)", Summary: "getPet"},
+				{Method: "DELETE", Path: "/api/pets/{id}", URL: "https://petstore.example.com/api/pets/{id}", Params: []string{"id"}, AuthScheme: "none", Summary: "Delete pet", Deprecated: true},
+			},
+		},
+		{
+			name:    "origin 覆盖文档声明的主机",
+			docURL:  "https://petstore.example.com/v2/api-docs",
+			origin:  "http://10.0.0.5:8080",
+			body:    swagger2Doc,
+			version: "2.0",
+			baseURL: "http://10.0.0.5:8080/api",
+		},
+		{
+			name:    "OpenAPI 3 YAML 服务器变量与引用参数",
+			docURL:  "https://docs.example.com/openapi.yaml",
+			body:    openAPI3Doc,
+			version: "3.0.1",
+			baseURL: "https://api.example.com/v2",
+			ops: []APIDocOperation{
+				{Method: "GET", Path: "/v2/users", URL: "https://api.example.com/v2/users", Params: []string{"page", "size"}, AuthScheme: "bearer, oauth2", Summary: "List users"},
+				{Method: "PUT", Path: "/v2/users", URL: "https://api.example.com/v2/users", Params: []string{"email", "role"}},
+				{Method: "GET", Path: "/v2/users/{userId}/avatar", URL: "https://api.example.com/v2/users/{userId}/avatar", Params: []string{"userId"}},
+			},
+		},
+		{
+			name:    "OpenAPI 3 没有 servers 时使用文档站点",
+			docURL:  "http://app.example.com/openapi.json",
+			body:    `{"openapi": "3.1.0", "paths": {"/ping": {"head": {}}}}`,
+			version: "3.1.0",
+			baseURL: "http://app.example.com",
+			ops:     []APIDocOperation{{Method: "HEAD", Path: "/ping", URL: "http://app.example.com/ping"}},
+		},
+		{
+			name:    "OpenAPI 3 相对服务器地址",
+			docURL:  "http://app.example.com/docs/openapi.json",
+			body:    `{"openapi": "3.0.0", "servers": [{"url": "/gateway/"}], "paths": {}}`,
+			version: "3.0.0",
+			baseURL: "http://app.example.com/gateway",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseAPIDoc(tt.docURL, tt.origin, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("parseAPIDoc() error = %v", err)
+			}
+			if doc.Version != tt.version || doc.BaseURL != tt.baseURL {
+				t.Errorf("version, base = %q, %q, want %q, %q", doc.Version, doc.BaseURL, tt.version, tt.baseURL)
+			}
+			if tt.ops == nil {
+				return
+			}
+			var got []APIDocOperation
+			for _, o := range doc.Operations {
+				if len(o.Params) == 0 {
+					o.Params = nil
+				}
+				got = append(got, APIDocOperation{
+					Method: o.Method, Path: o.Path, URL: o.URL, Params: o.Params,
+					AuthScheme: o.AuthScheme, Summary: o.Summary, Deprecated: o.Deprecated,
+				})
+			}
+			if !reflect.DeepEqual(got, tt.ops) {
+				t.Errorf("operations =\n%+v\nwant\n%+v", got, tt.ops)
+			}
+		})
+	}
+}
+
+func TestParseAPIDocRequiredQuery(t *testing.T) {
+	doc, err := parseAPIDoc("https://petstore.example.com/v2/api-docs", "", []byte(swagger2Doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range doc.Operations {
+		if o.Method == "GET" && !reflect.DeepEqual(o.required, []string{"fields"}) {
+			t.Errorf("required = %q, want [fields]", o.required)
+		}
+	}
+}
+
+func TestParseAPIDocInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"HTML 页面", "<html><body>Swagger UI</body></html>"},
+		{"缺少版本字段", `{"paths": {"/a": {"get": {}}}}`},
+		{"缺少 paths", `{"swagger": "2.0", "info": {}}`},
+		{"springfox 分组列表", `[{"name": "default", "url": "/v2/api-docs?group=default"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseAPIDoc("http://example.com/v2/api-docs", "", []byte(tt.body)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
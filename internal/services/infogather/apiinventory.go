@@ -0,0 +1,64 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// apiPathRegex 路径中常见的接口特征
+var apiPathRegex = regexp.MustCompile(`(?i)(?:^|/)(?:api|apis|rest|graphql|gql|rpc|jsonrpc|services?|ws|openapi|swagger|v\d+)(?:/|$)|\.(?:json|do|action|ashx|asmx|svc|jsonp)$`)
+
+// looksLikeAPI 根据路径与响应类型判断 URL 是否像接口，用于从爬虫与目录扫描结果中挑出接口
+func looksLikeAPI(path, contentType string) bool {
+	ct := strings.ToLower(contentType)
+	for _, t := range []string{"json", "graphql", "protobuf", "grpc"} {
+		if strings.Contains(ct, t) {
+			return true
+		}
+	}
+	if strings.Contains(ct, "xml") && !strings.Contains(ct, "xhtml") {
+		return true
+	}
+	return apiPathRegex.MatchString(path)
+}
+
+// newAPIEndpoint 由 URL 生成接口清单记录：路径不含查询串（保留 {name} 占位符），查询参数名并入 Params
+func newAPIEndpoint(webServiceID int64, rawURL, method, source string) (db.APIEndpoint, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return db.APIEndpoint{}, false
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	} else if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	e := db.APIEndpoint{
+		WebServiceID: webServiceID,
+		Path:         path,
+		Method:       strings.ToUpper(method),
+		Source:       source,
+	}
+	for name := range u.Query() {
+		e.Params = append(e.Params, name)
+	}
+	sort.Strings(e.Params)
+	return e, true
+}
+
+// recordAPIEndpoint 写入接口清单，状态码为 401/403 或调用方已判定时标记需要认证
+func recordAPIEndpoint(dbManager *db.Manager, e db.APIEndpoint) {
+	if dbManager == nil || e.WebServiceID <= 0 {
+		return
+	}
+	e.Method = strings.ToUpper(e.Method)
+	e.AuthRequired = e.AuthRequired || e.StatusCode == 401 || e.StatusCode == 403
+	if err := dbManager.UpsertAPIEndpoint(e); err != nil {
+		logger.Warn("保存接口失败", "path", e.Path, "method", e.Method, "错误", err)
+	}
+}
@@ -2,7 +2,14 @@ package infogather
 
 import (
 	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
 	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 type AssetService struct {
@@ -44,6 +51,41 @@ func (s *AssetService) GetAssetWebDirectories(assetID int64) ([]db.WebDirectory,
 	return s.dbManager.GetAssetWebDirectories(assetID)
 }
 
+// GetWebDirectoryGroups 按响应内容哈希对目录分组，便于识别内容相同的页面
+func (s *AssetService) GetWebDirectoryGroups(webServiceID int64) ([]db.WebDirectoryGroup, error) {
+	return s.dbManager.GetWebDirectoryGroups(webServiceID)
+}
+
+// GetWebURLs 获取爬虫等模块发现的 URL
+func (s *AssetService) GetWebURLs(webServiceID int64) ([]db.WebURL, error) {
+	return s.dbManager.GetWebURLs(webServiceID)
+}
+
+// GetWebForms 获取爬虫发现的表单
+func (s *AssetService) GetWebForms(webServiceID int64) ([]db.WebForm, error) {
+	return s.dbManager.GetWebForms(webServiceID)
+}
+
+// GetWebParams 获取从 JS 中收集及参数挖掘确认的参数
+func (s *AssetService) GetWebParams(webServiceID int64) ([]db.WebParam, error) {
+	return s.dbManager.GetWebParams(webServiceID)
+}
+
+// GetAPIEndpoints 获取 JSFinder、爬虫、目录扫描等模块汇总的接口清单
+func (s *AssetService) GetAPIEndpoints(webServiceID int64) ([]db.APIEndpoint, error) {
+	return s.dbManager.GetAPIEndpoints(webServiceID)
+}
+
+// FindAPIEndpoints 按响应类型、访问控制结果等条件筛选接口，例如查找无需认证即可返回 JSON 数据的接口
+func (s *AssetService) FindAPIEndpoints(filter db.APIEndpointFilter) ([]db.APIEndpoint, error) {
+	return s.dbManager.FindAPIEndpoints(filter)
+}
+
+// GetAPIBasePaths 获取 JSFinder 推断的接口前缀，已验证的排在最前
+func (s *AssetService) GetAPIBasePaths(webServiceID int64) ([]db.APIBasePath, error) {
+	return s.dbManager.GetAPIBasePaths(webServiceID)
+}
+
 func (s *AssetService) GetWebJSFiles(webServiceID int64) ([]db.WebJSFile, error) {
 	return s.dbManager.GetWebJSFiles(webServiceID)
 }
@@ -59,3 +101,117 @@ func (s *AssetService) GetAssetSensitiveResults(assetID int64) ([]db.SensitiveRe
 func (s *AssetService) GetAssetAuthResults(assetID int64) ([]db.AuthResult, error) {
 	return s.dbManager.GetAssetAuthResults(assetID)
 }
+
+// ensureWebService 根据 URL 解析主机并依次创建 Asset / Port / WebService 记录
+// 返回 assetID 与 webServiceID，供各扫描模块关联结果
+func ensureWebService(dbManager *db.Manager, target string) (int64, int64, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	host := u.Hostname()
+	portStr := u.Port()
+	if portStr == "" {
+		if u.Scheme == "https" {
+			portStr = "443"
+		} else {
+			portStr = "80"
+		}
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	// Resolve IP, fallback to host itself if it is already an IP
+	ip := host
+	if net.ParseIP(host) == nil {
+		ips, err := net.LookupHost(host)
+		if err != nil || len(ips) == 0 {
+			return 0, 0, fmt.Errorf("resolve host %s: %v", host, err)
+		}
+		ip = ips[0]
+	}
+
+	assetID, err := dbManager.UpsertAsset(ip, "", true)
+	if err != nil {
+		return 0, 0, err
+	}
+	portID, err := dbManager.UpsertAssetPort(assetID, port, "tcp", u.Scheme, "", "", "", "open")
+	if err != nil {
+		return assetID, 0, err
+	}
+	webServiceID, err := dbManager.UpsertWebService(assetID, portID, target, "", "", "")
+	if err != nil {
+		return assetID, 0, err
+	}
+	return assetID, webServiceID, nil
+}
+
+// webServiceCache 按源站（scheme://host）缓存 ensureWebService 的结果，避免重复解析与写库
+type webServiceCache struct {
+	dbManager *db.Manager
+	mu        sync.Mutex
+	ids       map[string]int64
+}
+
+func newWebServiceCache(dbManager *db.Manager) *webServiceCache {
+	return &webServiceCache{dbManager: dbManager, ids: make(map[string]int64)}
+}
+
+// ID 返回 rawURL 所属 Web 服务的 ID，解析失败时返回 0
+func (c *webServiceCache) ID(rawURL string) int64 {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return 0
+	}
+	origin := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := c.ids[origin]; ok {
+		return id
+	}
+	_, id, err := ensureWebService(c.dbManager, origin)
+	if err != nil {
+		logger.Warn("关联 WebService 失败", "目标", origin, "错误", err)
+	}
+	c.ids[origin] = id
+	return id
+}
+
+// resolveScanTargets 合并手工输入的目标与资产库中筛选出的 Web 服务，去重并补全协议
+func resolveScanTargets(dbManager *db.Manager, inputs []string, filter *db.WebServiceFilter) ([]string, error) {
+	seen := make(map[string]bool)
+	var targets []string
+	add := func(t string) error {
+		t = normalizeDirTarget(strings.TrimSpace(t))
+		if u, err := url.Parse(t); err != nil || u.Host == "" {
+			return fmt.Errorf("目标地址无效: %s", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+		return nil
+	}
+	for _, t := range inputs {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		if err := add(t); err != nil {
+			return nil, err
+		}
+	}
+	if filter != nil && dbManager != nil {
+		services, err := dbManager.FindWebServices(*filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, ws := range services {
+			add(ws.URL)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("请至少提供一个目标")
+	}
+	return targets, nil
+}
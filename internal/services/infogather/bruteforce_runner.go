@@ -83,6 +83,10 @@ func (s *BruteForceService) runAttack(ctx context.Context, cfg BruteForceConfig)
 			continue
 		}
 
+		if rule := matchCDNByIP(t.IP); rule != nil {
+			s.emitLog(fmt.Sprintf("[警告] 目标 %s 属于 %s 节点，爆破可能无效或触发封禁", t.IP, rule.Name))
+		}
+
 		s.emitLog(fmt.Sprintf("正在爆破目标: %s:%d (%s)", t.IP, t.Port, t.Service))
 
 		foundForTarget := false
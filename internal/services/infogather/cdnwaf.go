@@ -0,0 +1,564 @@
+package infogather
+
+import (
+	"JAttack/internal/pkg/logger"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/miekg/dns"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// CDNWAFResult CDN/WAF 识别结果
+type CDNWAFResult struct {
+	Target   string   `json:"target"`
+	CDN      string   `json:"cdn"`
+	WAF      string   `json:"waf"`
+	LB       string   `json:"lb"` // 负载均衡，不影响 Behind
+	CNAMEs   []string `json:"cnames"`
+	IPs      []string `json:"ips"`
+	Evidence []string `json:"evidence"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Behind 目标是否位于 CDN 或 WAF 之后
+func (r CDNWAFResult) Behind() bool {
+	return r.CDN != "" || r.WAF != ""
+}
+
+const (
+	cdnWAFKindCDN = "cdn"
+	cdnWAFKindWAF = "waf"
+	cdnWAFKindLB  = "lb"
+)
+
+// cdnWAFRule 单条识别规则，任一特征命中即认为匹配
+type cdnWAFRule struct {
+	Name    string
+	Kind    string
+	Headers map[string]*regexp.Regexp // 响应头名 -> 值匹配（nil 表示只要求存在）
+	Cookies []string                  // Cookie 名前缀
+	Body    *regexp.Regexp            // 拦截页特征，只匹配被拦截的响应，需锚定到拦截页结构而非厂商名称
+	CNAMEs  []string                  // CNAME 后缀
+	CIDRs   []string                  // 厂商公开的 IP 段
+	nets    []*net.IPNet
+}
+
+var cdnWAFRules = []*cdnWAFRule{
+	// CDN
+	{
+		Name: "Cloudflare", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"Cf-Ray": nil, "Server": regexp.MustCompile(`(?i)cloudflare`)},
+		Cookies: []string{"__cfduid", "__cf_bm", "cf_clearance"},
+		CNAMEs:  []string{".cloudflare.net", ".cdn.cloudflare.net"},
+		CIDRs: []string{
+			"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22", "103.31.4.0/22", "141.101.64.0/18",
+			"108.162.192.0/18", "190.93.240.0/20", "188.114.96.0/20", "197.234.240.0/22", "198.41.128.0/17",
+			"162.158.0.0/15", "104.16.0.0/13", "104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+		},
+	},
+	{
+		Name: "CloudFront", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Amz-Cf-Id": nil, "X-Amz-Cf-Pop": nil, "Via": regexp.MustCompile(`(?i)cloudfront`)},
+		CNAMEs:  []string{".cloudfront.net"},
+	},
+	{
+		Name: "Akamai", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Akamai-Transformed": nil, "Akamai-Grn": nil, "Server": regexp.MustCompile(`(?i)AkamaiGHost|AkamaiNetStorage`)},
+		Cookies: []string{"ak_bmsc", "bm_sz", "_abck"},
+		CNAMEs:  []string{".akamai.net", ".akamaiedge.net", ".edgekey.net", ".edgesuite.net", ".akamaized.net"},
+	},
+	{
+		Name: "Fastly", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Fastly-Request-Id": nil, "Fastly-Debug-Digest": nil, "X-Served-By": regexp.MustCompile(`(?i)^cache-`)},
+		CNAMEs:  []string{".fastly.net", ".fastlylb.net"},
+		CIDRs:   []string{"151.101.0.0/16", "199.232.0.0/16"},
+	},
+	{
+		Name: "Aliyun CDN", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"Ali-Swift-Global-Savetime": nil, "Eagleid": nil, "Via": regexp.MustCompile(`(?i)cache\d+\.(l2|cn)\S*`)},
+		CNAMEs:  []string{".kunlunaq.com", ".kunlunar.com", ".kunlunca.com", ".kunlunle.com", ".alikunlun.com", ".alikunlun.net", ".cdngslb.com", ".alicdn.com", ".aliyuncs.com.w.cdngslb.com"},
+	},
+	{
+		Name: "Tencent CDN", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Nws-Log-Uuid": nil, "X-Cache-Lookup": regexp.MustCompile(`(?i)^(?:Hit From (?:Upstream|MemCache|Disktank\d*|Inner Cluster|Newfvm)|Cache Miss|Return Directly)`), "Server": regexp.MustCompile(`(?i)^(NWS|tencent)`)},
+		CNAMEs:  []string{".cdn.dnsv1.com", ".cdn.dnsv1.com.cn", ".dsa.dnsv1.com", ".tdnsv5.com", ".qcloudcdn.com", ".tcdnlive.com"},
+	},
+	{
+		Name: "Huawei CDN", Kind: cdnWAFKindCDN,
+		CNAMEs: []string{".cdnhwc1.com", ".cdnhwc2.com", ".cdnhwc3.com", ".huaweicloud-cdn.com"},
+	},
+	{
+		Name: "Baidu Yunjiasu", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)yunjiasu`), "X-Yunjiasu-Request-Id": nil},
+		CNAMEs:  []string{".yunjiasu-cdn.net", ".bdydns.com", ".jomodns.com"},
+	},
+	{
+		Name: "Wangsu", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Via": regexp.MustCompile(`(?i)wangsu|chinanetcenter`), "X-Ws-Request-Id": nil},
+		CNAMEs:  []string{".wscdns.com", ".wsglb0.com", ".lxdns.com", ".chinanetcenter.com", ".wswebcdn.com", ".wsdvs.com"},
+	},
+	{
+		Name: "Qiniu CDN", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Qnm-Cache": nil, "X-Qiniu-Zone": nil},
+		CNAMEs:  []string{".qiniudns.com", ".qbox.me", ".clouddn.com"},
+	},
+	{
+		Name: "Incapsula", Kind: cdnWAFKindCDN,
+		Headers: map[string]*regexp.Regexp{"X-Iinfo": nil, "X-Cdn": regexp.MustCompile(`(?i)incapsula`)},
+		Cookies: []string{"visid_incap_", "incap_ses_", "nlbi_"},
+		CNAMEs:  []string{".incapdns.net"},
+	},
+
+	// WAF
+	{
+		Name: "Cloudflare WAF", Kind: cdnWAFKindWAF,
+		Body: regexp.MustCompile(`(?i)<title>Attention Required! \| Cloudflare</title>|id="cf-error-details"`),
+	},
+	{
+		Name: "AWS WAF", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"X-Amzn-Waf-Action": nil},
+		Cookies: []string{"aws-waf-token"},
+		Body:    regexp.MustCompile(`(?i)<title>403 Forbidden</title>[\s\S]{0,200}Request blocked[\s\S]{0,200}cloudfront|\.token\.awswaf\.com/|awsWafCookieDomainList`),
+	},
+	{
+		Name: "Imperva Incapsula WAF", Kind: cdnWAFKindWAF,
+		Body: regexp.MustCompile(`(?i)Incapsula incident ID|_Incapsula_Resource`),
+	},
+	{
+		Name: "Sucuri", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"X-Sucuri-Id": nil, "X-Sucuri-Cache": nil, "Server": regexp.MustCompile(`(?i)Sucuri/Cloudproxy`)},
+		Body:    regexp.MustCompile(`(?i)<title>Sucuri WebSite Firewall - Access Denied</title>|sucuri\.net/privacy-policy`),
+	},
+	{
+		Name: "ModSecurity", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)mod_security|NOYB`)},
+		Body:    regexp.MustCompile(`(?i)This error was generated by Mod_Security|rules of the mod_security module`),
+	},
+	{
+		Name: "F5 BIG-IP ASM", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"X-Wa-Info": nil},
+		Cookies: []string{"F5_ST"},
+		Body:    regexp.MustCompile(`(?i)The requested URL was rejected\. Please consult with your administrator`),
+	},
+	{
+		Name: "Barracuda", Kind: cdnWAFKindWAF,
+		Cookies: []string{"barra_counter_session", "BNI__BARRACUDA_LB_COOKIE"},
+		Body:    regexp.MustCompile(`(?i)You have been blocked[\s\S]{0,100}Barracuda`),
+	},
+	{
+		Name: "FortiWeb", Kind: cdnWAFKindWAF,
+		Cookies: []string{"FORTIWAFSID"},
+		Body:    regexp.MustCompile(`(?i)\.fgd_icon|<title>Server Unavailable!</title>[\s\S]{0,500}(?:FortiWeb|Fortinet)`),
+	},
+	{
+		Name: "Wordfence", Kind: cdnWAFKindWAF,
+		Body: regexp.MustCompile(`(?i)Generated by Wordfence|This response was generated by Wordfence`),
+	},
+	{
+		Name: "Safedog", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)safedog`), "X-Powered-By": regexp.MustCompile(`(?i)WAF/2\.0`)},
+		Cookies: []string{"safedog-flow-item"},
+		Body:    regexp.MustCompile(`(?i)404\.safedog\.cn/|<title>网站防火墙</title>[\s\S]{0,1000}安全狗`),
+	},
+	{
+		Name: "Yunsuo", Kind: cdnWAFKindWAF,
+		Cookies: []string{"yunsuo_session"},
+		Body:    regexp.MustCompile(`(?i)class="yunsuologo"|<title>[^<]*云锁[^<]*</title>[\s\S]{0,1000}拦截`),
+	},
+	{
+		Name: "BT WAF", Kind: cdnWAFKindWAF,
+		Body: regexp.MustCompile(`(?i)<title>[^<]*宝塔网站防火墙[^<]*</title>|已被网站管理员设置拦截`),
+	},
+	{
+		Name: "SafeLine", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)safeline`)},
+		Body:    regexp.MustCompile(`(?i)<!-- event_id: [0-9a-f]{32} -->`),
+	},
+	{
+		Name: "Aliyun WAF", Kind: cdnWAFKindWAF,
+		Cookies: []string{"acw_tc", "acw_sc__"},
+		Body:    regexp.MustCompile(`(?i)errors\.aliyun\.com/images/|Sorry, your request has been blocked as it may cause potential threats to the server's security`),
+	},
+	{
+		Name: "Tencent Cloud WAF", Kind: cdnWAFKindWAF,
+		Body: regexp.MustCompile(`(?i)waf\.tencent-cloud\.com/|<title>[^<]*腾讯T-Sec Web应用防火墙`),
+	},
+	{
+		Name: "360 Wangzhan", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"X-Powered-By-360wzb": nil, "X-Safe-Firewall": nil, "Server": regexp.MustCompile(`(?i)360wzws`)},
+		Body:    regexp.MustCompile(`(?i)/wzws-waf-cgi/|wangzhan\.360\.cn/[\s\S]{0,200}拦截`),
+	},
+	{
+		Name: "Jiasule", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)jiasule`)},
+		Cookies: []string{"__jsluid", "jsl_tracking"},
+		Body:    regexp.MustCompile(`(?i)static\.jiasule\.com|notice-jiasule`),
+	},
+	{
+		Name: "NSFocus WAF", Kind: cdnWAFKindWAF,
+		Headers: map[string]*regexp.Regexp{"Server": regexp.MustCompile(`(?i)NSFocus`)},
+	},
+
+	// 负载均衡：会话保持 Cookie 只说明前面有负载均衡，不代表启用了 WAF
+	{
+		Name: "F5 BIG-IP LTM", Kind: cdnWAFKindLB,
+		Cookies: []string{"BIGipServer", "TS01"},
+	},
+}
+
+// cdnWAFProbeQuery 用于触发 WAF 拦截页的恶意特征参数
+const cdnWAFProbeQuery = "id=1%27%20AND%201%3D1%20UNION%20SELECT%201,2,3--&q=%3Cscript%3Ealert(1)%3C/script%3E&file=../../../../etc/passwd"
+
+func init() {
+	for _, rule := range cdnWAFRules {
+		for _, cidr := range rule.CIDRs {
+			if _, n, err := net.ParseCIDR(cidr); err == nil {
+				rule.nets = append(rule.nets, n)
+			}
+		}
+	}
+}
+
+// DetectCDNWAF 识别 Web 目标前的 CDN / WAF
+// 依次检查 CNAME 链、IP 段、正常请求的响应头/Cookie；attackProbe 为 true 时再发送携带攻击特征的请求观察拦截页，
+// 该请求容易触发告警或封禁扫描 IP，默认不发送
+func DetectCDNWAF(ctx context.Context, target string, timeout time.Duration, attackProbe bool) CDNWAFResult {
+	result := CDNWAFResult{Target: target, CNAMEs: []string{}, IPs: []string{}, Evidence: []string{}}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Hostname() == "" {
+		result.Error = fmt.Sprintf("invalid target: %s", target)
+		return result
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	setMatch := func(rule *cdnWAFRule, evidence string) {
+		if rule.Kind == cdnWAFKindCDN && result.CDN == "" {
+			result.CDN = rule.Name
+		}
+		if rule.Kind == cdnWAFKindWAF && result.WAF == "" {
+			result.WAF = rule.Name
+		}
+		if rule.Kind == cdnWAFKindLB && result.LB == "" {
+			result.LB = rule.Name
+		}
+		result.Evidence = append(result.Evidence, fmt.Sprintf("%s: %s", rule.Name, evidence))
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		result.IPs = append(result.IPs, host)
+	} else {
+		result.CNAMEs = lookupCNAMEChain(ctx, host, timeout)
+		if addrs, err := net.DefaultResolver.LookupHost(ctx, host); err == nil {
+			result.IPs = append(result.IPs, addrs...)
+		}
+	}
+
+	// 1. CNAME
+	for _, cname := range result.CNAMEs {
+		if rule := matchCDNByCNAME(cname); rule != nil {
+			setMatch(rule, "CNAME "+cname)
+		}
+	}
+
+	// 2. IP 段
+	for _, ip := range result.IPs {
+		if rule := matchCDNByIP(ip); rule != nil {
+			setMatch(rule, "IP "+ip)
+		}
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	// 3. 正常请求
+	normal, err := cdnWAFRequest(ctx, client, target)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for _, rule := range cdnWAFRules {
+		if evidence := rule.matchResponse(normal, false); evidence != "" {
+			setMatch(rule, evidence)
+		}
+		// 正常请求已被拦截（例如扫描 IP 已被封禁）时才按拦截页特征匹配
+		if isBlockStatus(normal.status) {
+			if evidence := rule.matchResponse(normal, true); evidence != "" {
+				setMatch(rule, "拦截页 "+evidence)
+			}
+		}
+	}
+
+	// 4. 携带攻击特征的请求，观察拦截页
+	if !attackProbe {
+		return result
+	}
+	probeURL := *u
+	probeURL.RawQuery = cdnWAFProbeQuery
+	blocked, err := cdnWAFRequest(ctx, client, probeURL.String())
+	if err == nil {
+		for _, rule := range cdnWAFRules {
+			if rule.Kind != cdnWAFKindWAF {
+				continue
+			}
+			if evidence := rule.matchResponse(blocked, true); evidence != "" {
+				setMatch(rule, "拦截页 "+evidence)
+			}
+		}
+		if result.WAF == "" && blocked.status != normal.status && isBlockStatus(blocked.status) {
+			result.WAF = "Unknown WAF"
+			result.Evidence = append(result.Evidence, fmt.Sprintf("攻击特征请求状态码由 %d 变为 %d", normal.status, blocked.status))
+		}
+	}
+
+	return result
+}
+
+type cdnWAFResponse struct {
+	status  int
+	headers http.Header
+	cookies []*http.Cookie
+	body    string
+}
+
+func cdnWAFRequest(ctx context.Context, client *http.Client, target string) (*cdnWAFResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256*1024))
+	return &cdnWAFResponse{
+		status:  resp.StatusCode,
+		headers: resp.Header,
+		cookies: resp.Cookies(),
+		body:    string(body),
+	}, nil
+}
+
+// matchResponse 返回命中的特征描述，未命中返回空字符串
+// blockPage 为 false 时只匹配响应头与 Cookie；为 true 时只匹配拦截页内容，
+// 正常页面可能只是提到了厂商名称，不能作为依据
+func (r *cdnWAFRule) matchResponse(resp *cdnWAFResponse, blockPage bool) string {
+	if !blockPage {
+		for name, re := range r.Headers {
+			value := resp.headers.Get(name)
+			if value == "" {
+				continue
+			}
+			if re == nil || re.MatchString(value) {
+				return fmt.Sprintf("Header %s: %s", name, value)
+			}
+		}
+		for _, c := range resp.cookies {
+			for _, prefix := range r.Cookies {
+				if strings.HasPrefix(c.Name, prefix) {
+					return "Cookie " + c.Name
+				}
+			}
+		}
+		return ""
+	}
+	if r.Body != nil {
+		if m := r.Body.FindString(resp.body); m != "" {
+			if utf8.RuneCountInString(m) > 60 {
+				m = string([]rune(m)[:60]) + "..."
+			}
+			return "Body " + m
+		}
+	}
+	return ""
+}
+
+func isBlockStatus(status int) bool {
+	switch status {
+	case 403, 405, 406, 418, 429, 493, 501, 503:
+		return true
+	}
+	return false
+}
+
+func matchCDNByCNAME(cname string) *cdnWAFRule {
+	cname = strings.ToLower(strings.TrimSuffix(cname, "."))
+	for _, rule := range cdnWAFRules {
+		for _, suffix := range rule.CNAMEs {
+			if strings.HasSuffix(cname, suffix) {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+func matchCDNByIP(ipStr string) *cdnWAFRule {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return nil
+	}
+	for _, rule := range cdnWAFRules {
+		for _, n := range rule.nets {
+			if n.Contains(ip) {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// lookupCNAMEChain 逐跳查询 CNAME，返回完整链路（标准库只返回最终结果）
+// 只使用系统配置的 DNS 服务器，内网域名不会发往公共解析器，分离解析（split-horizon）也保持一致；
+// 读取不到 resolv.conf（例如 Windows）时退回系统解析器，只能得到最终的 CNAME
+func lookupCNAMEChain(ctx context.Context, host string, timeout time.Duration) []string {
+	conf, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+	if err != nil || len(conf.Servers) == 0 {
+		return lookupCNAMESystem(ctx, host, timeout)
+	}
+	var servers []string
+	for _, server := range conf.Servers {
+		servers = append(servers, net.JoinHostPort(server, conf.Port))
+	}
+
+	client := &dns.Client{Timeout: timeout}
+	chain := []string{}
+	name := dns.Fqdn(host)
+	for i := 0; i < 10; i++ {
+		msg := new(dns.Msg)
+		msg.SetQuestion(name, dns.TypeCNAME)
+		msg.RecursionDesired = true
+
+		var resp *dns.Msg
+		var err error
+		for _, server := range servers {
+			resp, _, err = client.ExchangeContext(ctx, msg, server)
+			if err == nil || ctx.Err() != nil {
+				break
+			}
+		}
+		if err != nil || resp == nil {
+			break
+		}
+
+		next := ""
+		for _, rr := range resp.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				next = cname.Target
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		chain = append(chain, strings.TrimSuffix(next, "."))
+		name = next
+	}
+	return chain
+}
+
+// lookupCNAMESystem 通过系统解析器查询最终的 CNAME
+func lookupCNAMESystem(ctx context.Context, host string, timeout time.Duration) []string {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cname, err := net.DefaultResolver.LookupCNAME(ctx, host)
+	cname = strings.TrimSuffix(cname, ".")
+	if err != nil || cname == "" || strings.EqualFold(cname, strings.TrimSuffix(host, ".")) {
+		return []string{}
+	}
+	return []string{cname}
+}
+
+// DetectCDNWAF 检测目标是否位于 CDN / WAF 之后，并将结果保存到资产库；attackProbe 见包级 DetectCDNWAF
+// 可通过 StopScan 取消
+func (s *InfoService) DetectCDNWAF(target string, attackProbe bool) CDNWAFResult {
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+	target = strings.TrimRight(target, "/")
+
+	var assetID, webServiceID int64
+	if s.dbManager != nil && s.dbManager.GetDB() != nil {
+		var err error
+		assetID, webServiceID, err = ensureWebService(s.dbManager, target)
+		if err != nil {
+			logger.Warn("关联 WebService 失败", "目标", target, "错误", err)
+		}
+	}
+
+	s.mu.Lock()
+	if s.detectCancel != nil {
+		s.detectCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.detectCancel = cancel
+	s.mu.Unlock()
+	defer cancel()
+
+	return s.detectAndSaveCDNWAF(ctx, target, assetID, webServiceID, 5*time.Second, attackProbe)
+}
+
+func (s *InfoService) detectAndSaveCDNWAF(ctx context.Context, target string, assetID, webServiceID int64, timeout time.Duration, attackProbe bool) CDNWAFResult {
+	result := DetectCDNWAF(ctx, target, timeout, attackProbe)
+	if result.Error != "" {
+		logger.Warn("CDN/WAF 识别失败", "目标", target, "错误", result.Error)
+	}
+
+	if webServiceID > 0 {
+		if err := s.dbManager.UpdateWebServiceCDNWAF(webServiceID, result.CDN, result.WAF); err != nil {
+			logger.Error("保存 CDN/WAF 识别结果失败", "目标", target, "错误", err)
+		}
+	}
+	// 命中 CDN 时解析到的 IP 即为边缘节点，同步标记到资产
+	if assetID > 0 && result.CDN != "" {
+		if err := s.dbManager.UpdateAssetCDN(assetID, result.CDN); err != nil {
+			logger.Error("保存资产 CDN 标记失败", "目标", target, "错误", err)
+		}
+	}
+	return result
+}
+
+// warnIfBehindCDNWAF 在扫描开始前识别 CDN/WAF，命中时向前端发出警告
+func (s *InfoService) warnIfBehindCDNWAF(ctx context.Context, target string, assetID, webServiceID int64, timeout time.Duration, attackProbe bool) {
+	result := s.detectAndSaveCDNWAF(ctx, target, assetID, webServiceID, timeout, attackProbe)
+	if !result.Behind() {
+		return
+	}
+
+	parts := []string{}
+	if result.CDN != "" {
+		parts = append(parts, "CDN: "+result.CDN)
+	}
+	if result.WAF != "" {
+		parts = append(parts, "WAF: "+result.WAF)
+	}
+	msg := fmt.Sprintf("[警告] 目标 %s 位于 %s 之后，扫描可能命中边缘节点或触发封禁", target, strings.Join(parts, ", "))
+	s.emitLog(msg)
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "scan:warning", result)
+	}
+}
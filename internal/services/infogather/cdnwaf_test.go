@@ -0,0 +1,111 @@
+package infogather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectCDNWAF(t *testing.T) {
+	const vendorPage = `<html><head><title>WAF 产品对比</title></head><body>
+NSFOCUS、SafeDog 安全狗、SafeLine 雷池、FortiWeb、Mod_Security、btwaf 与云锁的对比评测</body></html>`
+	const safedogBlock = `<html><head><title>网站防火墙</title></head><body>
+<img src="http://404.safedog.cn/images/safedogsite/head.png"/>您的请求带有不合法参数，已被网站管理员设置拦截！安全狗</body></html>`
+
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		attackProbe bool
+		waf, lb     string
+	}{
+		{
+			name: "正常页面提到厂商名称",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, vendorPage)
+			},
+			attackProbe: true,
+		},
+		{
+			name: "BIG-IP 会话保持 Cookie 识别为负载均衡",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.SetCookie(w, &http.Cookie{Name: "BIGipServerpool_web", Value: "1677787402.36895.0000"})
+				http.SetCookie(w, &http.Cookie{Name: "TS01a2b3c4", Value: "01abcdef"})
+				fmt.Fprint(w, "ok")
+			},
+			lb: "F5 BIG-IP LTM",
+		},
+		{
+			name: "攻击特征请求返回拦截页",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.RawQuery != "" {
+					fmt.Fprint(w, safedogBlock)
+					return
+				}
+				fmt.Fprint(w, vendorPage)
+			},
+			attackProbe: true,
+			waf:         "Safedog",
+		},
+		{
+			name: "未开启攻击探测时不请求拦截页",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.RawQuery != "" {
+					t.Error("attack probe sent")
+				}
+				fmt.Fprint(w, vendorPage)
+			},
+		},
+		{
+			name: "正常请求已被拦截",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `<html><head><title>Attention Required! | Cloudflare</title></head></html>`)
+			},
+			waf: "Cloudflare WAF",
+		},
+		{
+			name: "响应头识别 WAF",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Server", "NSFocus")
+				fmt.Fprint(w, "ok")
+			},
+			waf: "NSFocus WAF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			got := DetectCDNWAF(context.Background(), srv.URL, time.Second, tt.attackProbe)
+			if got.Error != "" {
+				t.Fatalf("DetectCDNWAF() error = %s", got.Error)
+			}
+			if got.WAF != tt.waf || got.LB != tt.lb || got.CDN != "" {
+				t.Errorf("DetectCDNWAF() = cdn %q, waf %q, lb %q, want waf %q, lb %q (evidence %q)", got.CDN, got.WAF, got.LB, tt.waf, tt.lb, got.Evidence)
+			}
+			if got.Behind() != (tt.waf != "") {
+				t.Errorf("Behind() = %v", got.Behind())
+			}
+		})
+	}
+}
+
+func TestDetectCDNWAFCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent after cancellation")
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	got := DetectCDNWAF(ctx, srv.URL, time.Second, true)
+	if !strings.Contains(got.Error, "context canceled") {
+		t.Errorf("Error = %q, want context canceled", got.Error)
+	}
+}
@@ -0,0 +1,597 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+)
+
+// CrawlerConfig 爬虫配置
+type CrawlerConfig struct {
+	Target      string   `json:"target"`
+	MaxDepth    int      `json:"max_depth"`   // 最大深度，默认 3
+	MaxPages    int      `json:"max_pages"`   // 最多请求的页面数，默认 500
+	Concurrency int      `json:"concurrency"` // 并发数，默认 10
+	Timeout     int      `json:"timeout"`     // 单次请求超时（毫秒），默认 10000
+	Scope       string   `json:"scope"`       // "host"（默认，同源）或 "domain"（同一可注册域名及其子域）
+	Exclude     []string `json:"exclude"`     // URL 包含这些关键字时不请求，例如 logout
+
+	SkipWellKnown bool `json:"skip_well_known"` // 不使用 robots.txt / sitemap.xml 等元数据中的 URL 作为种子
+
+	Request HTTPRequestOptions `json:"request"` // 请求头、Cookie 与认证，Methods 与 Body 不生效
+
+	DirScan  *DirScanConfig   `json:"dir_scan"`  // 非空时将发现的目录作为种子交给目录扫描，排在当前扫描任务之后执行
+	JSFinder *JSFinderOptions `json:"js_finder"` // 非空时将发现的脚本交给 JSFinder 分析
+}
+
+// CrawlPage 爬虫发现的 URL，随 crawler:url 事件推送
+type CrawlPage struct {
+	URL         string `json:"url"`
+	Source      string `json:"source"` // 发现该 URL 的页面
+	Depth       int    `json:"depth"`
+	Status      int    `json:"status,omitempty"` // 未请求（超出范围 / 静态资源）时为 0
+	ContentType string `json:"content_type,omitempty"`
+	InScope     bool   `json:"in_scope"` // 超出范围的 URL 只推送事件，不写入资产库
+}
+
+// CrawlFormField 表单字段
+type CrawlFormField struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// CrawlForm 页面中的 HTML 表单
+type CrawlForm struct {
+	PageURL string           `json:"page_url"`
+	Action  string           `json:"action"`
+	Method  string           `json:"method"`
+	Enctype string           `json:"enctype"`
+	Fields  []CrawlFormField `json:"fields"`
+}
+
+// CrawlResult 爬取结果汇总，随 crawler:complete 事件推送
+type CrawlResult struct {
+	Target      string      `json:"target"`
+	Pages       int         `json:"pages"`
+	URLs        []string    `json:"urls"`
+	Scripts     []string    `json:"scripts"`
+	Directories []string    `json:"directories"`
+	Forms       []CrawlForm `json:"forms"`
+	Error       string      `json:"error,omitempty"`
+}
+
+type CrawlerService struct {
+	ctx         context.Context
+	dbManager   *db.Manager
+	infoService *InfoService
+	jsFinder    *JSFinderService
+
+	mu          sync.Mutex // 保护 crawlCancel
+	crawlCancel context.CancelFunc
+}
+
+func NewCrawlerService(dbManager *db.Manager, infoService *InfoService, jsFinder *JSFinderService) *CrawlerService {
+	return &CrawlerService{
+		dbManager:   dbManager,
+		infoService: infoService,
+		jsFinder:    jsFinder,
+	}
+}
+
+func (s *CrawlerService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// StartCrawl 启动爬虫任务，结果通过 crawler:url / crawler:form / crawler:complete 事件推送
+func (s *CrawlerService) StartCrawl(config CrawlerConfig) error {
+	config.Target = normalizeDirTarget(strings.TrimSpace(config.Target))
+	if _, err := url.Parse(config.Target); err != nil {
+		return fmt.Errorf("目标地址无效: %v", err)
+	}
+
+	s.mu.Lock()
+	if s.crawlCancel != nil {
+		s.crawlCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.crawlCancel = cancel
+	s.mu.Unlock()
+
+	go s.runCrawl(ctx, config)
+	return nil
+}
+
+// StopCrawl 停止当前爬虫任务
+func (s *CrawlerService) StopCrawl() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.crawlCancel != nil {
+		s.crawlCancel()
+		s.crawlCancel = nil
+		logger.Info("爬虫任务已停止")
+	}
+}
+
+func (s *CrawlerService) emitLog(msg string) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "crawler:log", msg)
+	}
+}
+
+func (s *CrawlerService) runCrawl(ctx context.Context, config CrawlerConfig) {
+	logger.Info("开始爬取", "目标", config.Target, "深度", config.MaxDepth, "页面上限", config.MaxPages)
+	s.emitLog(fmt.Sprintf("开始爬取: %s", config.Target))
+
+	cache := newWebServiceCache(s.dbManager)
+
+	var seeds []string
+	if !config.SkipWellKnown {
+		wellKnown := HarvestWellKnown(ctx, config.Target, &config.Request, time.Duration(config.Timeout)*time.Millisecond)
+		recordWellKnown(s.dbManager, cache.ID(config.Target), wellKnown)
+		for _, u := range wellKnown.URLs {
+			seeds = append(seeds, u.URL)
+		}
+		if len(seeds) > 0 {
+			s.emitLog(fmt.Sprintf("元数据文件提供 %d 个种子 URL", len(seeds)))
+		}
+	}
+
+	c := newCrawler(config, seeds)
+	c.onPage = func(p CrawlPage) {
+		if p.InScope {
+			s.recordURL(cache, p.URL, "crawler", p.Status, p.ContentType)
+		}
+		runtime.EventsEmit(s.ctx, "crawler:url", p)
+	}
+	c.onForm = func(f CrawlForm) {
+		s.recordForm(cache, f)
+		runtime.EventsEmit(s.ctx, "crawler:form", f)
+	}
+	result := c.Run(ctx)
+
+	s.emitLog(fmt.Sprintf("爬取完成: 请求 %d 个页面，发现 %d 个 URL、%d 个脚本、%d 个表单", result.Pages, len(result.URLs), len(result.Scripts), len(result.Forms)))
+	runtime.EventsEmit(s.ctx, "crawler:complete", result)
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if config.DirScan != nil && s.infoService != nil {
+		cfg := *config.DirScan
+		cfg.Target = config.Target
+		cfg.Seeds = append(append([]string{}, cfg.Seeds...), result.Directories...)
+		s.emitLog(fmt.Sprintf("将 %d 个目录交给目录扫描，当前扫描任务结束后开始", len(result.Directories)))
+		s.infoService.QueueDirScan(cfg)
+	}
+
+	// JSFinder 在爬虫任务内执行，停止爬虫时一并停止，不影响用户单独启动的 JSFinder 任务
+	if config.JSFinder != nil && s.jsFinder != nil && len(result.Scripts) > 0 {
+		opts := *config.JSFinder
+		opts.Scripts = append(append([]string{}, opts.Scripts...), result.Scripts...)
+		s.emitLog(fmt.Sprintf("将 %d 个脚本交给 JSFinder", len(result.Scripts)))
+		jsResult := s.jsFinder.findJS(ctx, config.Target, opts, true)
+		runtime.EventsEmit(s.ctx, "jsfinder:complete", jsResult)
+	}
+}
+
+// recordURL 将 URL 写入其所属 Web 服务的 web_urls 表
+func (s *CrawlerService) recordURL(cache *webServiceCache, rawURL, source string, status int, contentType string) {
+	if s.dbManager == nil {
+		return
+	}
+	wsID := cache.ID(rawURL)
+	if wsID == 0 {
+		return
+	}
+	if err := s.dbManager.UpsertWebURL(wsID, rawURL, source, status, contentType); err != nil {
+		logger.Warn("保存 URL 失败", "url", rawURL, "错误", err)
+	}
+
+	// 像接口的 URL 同时进入接口清单
+	if e, ok := newAPIEndpoint(wsID, rawURL, "", source); ok && looksLikeAPI(e.Path, contentType) {
+		e.StatusCode = status
+		e.ContentType = contentType
+		recordAPIEndpoint(s.dbManager, e)
+	}
+}
+
+func (s *CrawlerService) recordForm(cache *webServiceCache, f CrawlForm) {
+	if s.dbManager == nil {
+		return
+	}
+	wsID := cache.ID(f.PageURL)
+	if wsID == 0 {
+		return
+	}
+	fields, _ := json.Marshal(f.Fields)
+	if err := s.dbManager.UpsertWebForm(wsID, f.PageURL, f.Action, f.Method, f.Enctype, string(fields)); err != nil {
+		logger.Warn("保存表单失败", "page", f.PageURL, "错误", err)
+	}
+
+	// 同源的表单提交地址作为接口记录，字段名即参数
+	page, err := url.Parse(f.PageURL)
+	if err != nil {
+		return
+	}
+	if e, ok := newAPIEndpoint(wsID, f.Action, f.Method, "crawler"); ok && strings.HasPrefix(f.Action, page.Scheme+"://"+page.Host+"/") {
+		for _, field := range f.Fields {
+			if field.Name != "" {
+				e.Params = append(e.Params, field.Name)
+			}
+		}
+		e.SourceFile = f.PageURL
+		recordAPIEndpoint(s.dbManager, e)
+	}
+}
+
+// crawler 范围受限的广度优先爬虫，可被其他模块复用（例如用 robots.txt / sitemap 中的 URL 作为种子）
+type crawler struct {
+	config CrawlerConfig
+	root   *url.URL
+	seeds  []string
+	client *http.Client
+
+	onPage func(CrawlPage)
+	onForm func(CrawlForm)
+
+	mu     sync.Mutex
+	seen   map[string]bool
+	dirs   map[string]bool
+	result CrawlResult
+	pages  atomic.Int64
+}
+
+type crawlJob struct {
+	URL    string
+	Source string
+}
+
+func newCrawler(config CrawlerConfig, seeds []string) *crawler {
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = 3
+	}
+	if config.MaxPages <= 0 {
+		config.MaxPages = 500
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 10
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10000
+	}
+
+	root, _ := url.Parse(config.Target)
+	return &crawler{
+		config: config,
+		root:   root,
+		seeds:  seeds,
+		client: &http.Client{
+			Timeout: time.Duration(config.Timeout) * time.Millisecond,
+			Jar:     config.Request.newCookieJar(config.Target),
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			// 重定向作为链接处理，以便记录并检查范围
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		seen:   make(map[string]bool),
+		dirs:   make(map[string]bool),
+		result: CrawlResult{Target: config.Target, URLs: []string{}, Scripts: []string{}, Directories: []string{}, Forms: []CrawlForm{}},
+	}
+}
+
+// Run 执行爬取并返回结果
+func (c *crawler) Run(ctx context.Context) CrawlResult {
+	if c.root == nil || c.root.Host == "" {
+		c.result.Error = "目标地址无效"
+		return c.result
+	}
+
+	level := []crawlJob{}
+	for _, u := range append([]string{c.config.Target}, c.seeds...) {
+		if job, ok := c.add(u, "", 0); ok {
+			level = append(level, job)
+		}
+	}
+
+	for depth := 0; depth <= c.config.MaxDepth && len(level) > 0; depth++ {
+		var next []crawlJob
+		var nextMu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, c.config.Concurrency)
+
+		for _, job := range level {
+			if ctx.Err() != nil || c.pages.Load() >= int64(c.config.MaxPages) {
+				break
+			}
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(job crawlJob) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				if depth >= c.config.MaxDepth {
+					c.fetch(ctx, job, depth, nil)
+					return
+				}
+				c.fetch(ctx, job, depth, func(link, source string) {
+					if j, ok := c.add(link, source, depth+1); ok {
+						nextMu.Lock()
+						next = append(next, j)
+						nextMu.Unlock()
+					}
+				})
+			}(job)
+		}
+		wg.Wait()
+		level = next
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.result.Pages = int(c.pages.Load())
+	for d := range c.dirs {
+		c.result.Directories = append(c.result.Directories, d)
+	}
+	sort.Strings(c.result.Directories)
+	return c.result
+}
+
+// add 记录新发现的 URL，返回是否需要请求该 URL
+func (c *crawler) add(rawURL, source string, depth int) (crawlJob, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return crawlJob{}, false
+	}
+	u.Fragment = ""
+	normalized := u.String()
+
+	c.mu.Lock()
+	if c.seen[normalized] {
+		c.mu.Unlock()
+		return crawlJob{}, false
+	}
+	c.seen[normalized] = true
+	c.result.URLs = append(c.result.URLs, normalized)
+
+	inScope := c.inScope(u)
+	isScript := strings.HasSuffix(strings.ToLower(u.Path), ".js")
+	if inScope && isScript {
+		c.result.Scripts = append(c.result.Scripts, normalized)
+	}
+	if inScope && u.Host == c.root.Host && u.Scheme == c.root.Scheme {
+		for dir := path.Dir(u.Path); dir != "/" && dir != "." && dir != ""; dir = path.Dir(dir) {
+			c.dirs[u.Scheme+"://"+u.Host+dir] = true
+		}
+		if strings.HasSuffix(u.Path, "/") && len(u.Path) > 1 {
+			c.dirs[u.Scheme+"://"+u.Host+strings.TrimRight(u.Path, "/")] = true
+		}
+	}
+	c.mu.Unlock()
+
+	fetch := inScope && !isScript && !isStaticResource(u.Path) && !c.excluded(normalized)
+	if !fetch && c.onPage != nil {
+		c.onPage(CrawlPage{URL: normalized, Source: source, Depth: depth, InScope: inScope})
+	}
+	return crawlJob{URL: normalized, Source: source}, fetch
+}
+
+func (c *crawler) inScope(u *url.URL) bool {
+	if strings.EqualFold(c.config.Scope, "domain") {
+		rootHost := c.root.Hostname()
+		if net.ParseIP(rootHost) == nil {
+			// 按公共后缀列表取可注册域名，www.example.co.uk 的范围是 example.co.uk 而不是 co.uk
+			if domain, err := publicsuffix.EffectiveTLDPlusOne(rootHost); err == nil {
+				host := u.Hostname()
+				return host == domain || strings.HasSuffix(host, "."+domain)
+			}
+		}
+	}
+	return u.Host == c.root.Host
+}
+
+func (c *crawler) excluded(rawURL string) bool {
+	lower := strings.ToLower(rawURL)
+	for _, kw := range c.config.Exclude {
+		if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" && strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// fetch 请求页面并解析链接，emit 为 nil 时只记录页面（已达最大深度）
+func (c *crawler) fetch(ctx context.Context, job crawlJob, depth int, emit func(link, source string)) {
+	if c.pages.Add(1) > int64(c.config.MaxPages) {
+		return
+	}
+
+	req, err := c.config.Request.newRequest(ctx, "GET", job.URL, "")
+	if err != nil {
+		return
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if c.onPage != nil {
+			c.onPage(CrawlPage{URL: job.URL, Source: job.Source, Depth: depth, InScope: true})
+		}
+		return
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+	resp.Body.Close()
+
+	contentType := resp.Header.Get("Content-Type")
+	if c.onPage != nil {
+		c.onPage(CrawlPage{URL: job.URL, Source: job.Source, Depth: depth, Status: resp.StatusCode, ContentType: contentType, InScope: true})
+	}
+	if emit == nil {
+		return
+	}
+
+	if location := resp.Header.Get("Location"); location != "" {
+		if next, err := resolveURL(job.URL, location); err == nil {
+			emit(next, job.URL)
+		}
+	}
+
+	ct := strings.ToLower(contentType)
+	if ct != "" && !strings.Contains(ct, "html") {
+		return
+	}
+
+	links, forms := parseHTMLPage(job.URL, body)
+	for _, link := range links {
+		emit(link, job.URL)
+	}
+	for _, f := range forms {
+		c.mu.Lock()
+		c.result.Forms = append(c.result.Forms, f)
+		c.mu.Unlock()
+		if c.onForm != nil {
+			c.onForm(f)
+		}
+		if f.Method == "GET" {
+			emit(f.Action, job.URL)
+		}
+	}
+}
+
+// parseHTMLPage 解析 HTML 中的链接、脚本、框架、meta 跳转与表单
+func parseHTMLPage(pageURL string, body []byte) ([]string, []CrawlForm) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, nil
+	}
+
+	var links []string
+	var forms []CrawlForm
+	var form *CrawlForm
+
+	resolve := func(ref string) string {
+		ref = strings.TrimSpace(ref)
+		lower := strings.ToLower(ref)
+		if ref == "" || strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "mailto:") ||
+			strings.HasPrefix(lower, "tel:") || strings.HasPrefix(lower, "data:") || strings.HasPrefix(ref, "#") {
+			return ""
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			return ""
+		}
+		return base.ResolveReference(u).String()
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	baseSet := false
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken && tt != html.EndTagToken {
+			continue
+		}
+
+		tok := z.Token()
+		if tt == html.EndTagToken {
+			if tok.Data == "form" && form != nil {
+				forms = append(forms, *form)
+				form = nil
+			}
+			continue
+		}
+
+		attr := func(name string) string {
+			for _, a := range tok.Attr {
+				if strings.EqualFold(a.Key, name) {
+					return a.Val
+				}
+			}
+			return ""
+		}
+
+		switch tok.Data {
+		case "base":
+			if href := attr("href"); href != "" && !baseSet {
+				if u, err := url.Parse(href); err == nil {
+					base = base.ResolveReference(u)
+					baseSet = true
+				}
+			}
+		case "a", "area", "link":
+			if l := resolve(attr("href")); l != "" {
+				links = append(links, l)
+			}
+		case "script", "iframe", "frame", "embed":
+			if l := resolve(attr("src")); l != "" {
+				links = append(links, l)
+			}
+		case "meta":
+			if strings.EqualFold(attr("http-equiv"), "refresh") {
+				content := attr("content")
+				if idx := strings.Index(strings.ToLower(content), "url="); idx >= 0 {
+					if l := resolve(strings.Trim(content[idx+4:], `'" `)); l != "" {
+						links = append(links, l)
+					}
+				}
+			}
+		case "form":
+			if form != nil {
+				forms = append(forms, *form)
+			}
+			action := resolve(attr("action"))
+			if action == "" {
+				action = base.String()
+			}
+			method := strings.ToUpper(attr("method"))
+			if method == "" {
+				method = "GET"
+			}
+			form = &CrawlForm{PageURL: pageURL, Action: action, Method: method, Enctype: attr("enctype"), Fields: []CrawlFormField{}}
+		case "input", "select", "textarea", "button":
+			if form != nil && attr("name") != "" {
+				fieldType := attr("type")
+				if fieldType == "" {
+					fieldType = tok.Data
+				}
+				form.Fields = append(form.Fields, CrawlFormField{Name: attr("name"), Type: fieldType, Value: attr("value")})
+			}
+		}
+	}
+	if form != nil {
+		forms = append(forms, *form)
+	}
+	return links, forms
+}
+
+// isStaticResource 图片、样式、字体、媒体等无需请求的资源
+func isStaticResource(p string) bool {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".bmp", ".svg", ".ico", ".webp", ".css", ".woff", ".woff2", ".ttf", ".eot", ".otf",
+		".mp3", ".mp4", ".avi", ".mov", ".webm", ".flv", ".pdf", ".zip", ".rar", ".7z", ".gz", ".tar", ".exe", ".apk", ".dmg", ".iso":
+		return true
+	}
+	return false
+}
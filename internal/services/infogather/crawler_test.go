@@ -0,0 +1,34 @@
+package infogather
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestCrawlerInScope(t *testing.T) {
+	tests := []struct {
+		scope, root, target string
+		want                bool
+	}{
+		{"domain", "https://www.example.co.uk", "https://api.example.co.uk/v1", true},
+		{"domain", "https://www.example.co.uk", "https://example.co.uk/", true},
+		{"domain", "https://www.example.co.uk", "https://other.co.uk/", false},
+		{"domain", "https://www.example.com.cn", "https://evil.com.cn/", false},
+		{"domain", "https://app.example.com", "https://cdn.example.com/a.js", true},
+		{"domain", "https://app.example.com", "https://example.com.evil.net/", false},
+		{"domain", "https://alice.github.io", "https://bob.github.io/", false},
+		{"domain", "https://alice.github.io", "https://alice.github.io/repo/", true},
+		{"domain", "http://10.0.0.1:8080", "http://10.0.0.1:8080/admin", true},
+		{"domain", "http://10.0.0.1:8080", "http://10.0.0.2:8080/", false},
+		{"host", "https://www.example.com", "https://api.example.com/", false},
+		{"", "https://www.example.com", "https://www.example.com/login", true},
+	}
+	for _, tt := range tests {
+		root, _ := url.Parse(tt.root)
+		target, _ := url.Parse(tt.target)
+		c := &crawler{config: CrawlerConfig{Scope: tt.scope}, root: root}
+		if got := c.inScope(target); got != tt.want {
+			t.Errorf("inScope(%s, %s -> %s) = %v, want %v", tt.scope, tt.root, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package infogather
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// defaultBackupExtensions 默认的备份 / 压缩包扩展名
+var defaultBackupExtensions = []string{
+	"zip", "rar", "7z", "tar", "tar.gz", "tgz", "gz", "bak", "old", "sql", "sql.gz",
+}
+
+// backupBaseWords 与目标无关的常见备份文件名
+var backupBaseWords = []string{
+	"backup", "backups", "bak", "www", "wwwroot", "web", "website", "site", "htdocs", "html", "public_html",
+	"data", "db", "database", "dump", "sql", "src", "source", "code", "release", "old", "new",
+	"test", "temp", "tmp", "1", "123", "admin",
+}
+
+// backupDatedWords 常与日期组合出现的文件名，例如 db_2025.sql
+var backupDatedWords = []string{"backup", "db", "database", "data", "dump", "sql"}
+
+// backupCandidate 一条候选路径，BaseURL 为其所在目录
+type backupCandidate struct {
+	BaseURL string
+	Path    string
+}
+
+// backupWordlist 根据主机名、域名、已发现的目录名与年份生成备份 / 敏感文件候选
+// 只在目录扫描的生产者协程中使用，无需加锁
+type backupWordlist struct {
+	extensions []string
+	years      []string
+	seen       map[string]bool
+}
+
+func newBackupWordlist(extensions []string) *backupWordlist {
+	var exts []string
+	for _, ext := range extensions {
+		ext = strings.TrimPrefix(strings.TrimSpace(ext), ".")
+		if ext != "" {
+			exts = append(exts, ext)
+		}
+	}
+	if len(exts) == 0 {
+		exts = defaultBackupExtensions
+	}
+
+	year := time.Now().Year()
+	return &backupWordlist{
+		extensions: exts,
+		years:      []string{strconv.Itoa(year), strconv.Itoa(year - 1), strconv.Itoa(year - 2)},
+		seen:       make(map[string]bool),
+	}
+}
+
+// Candidates 返回 baseURL 对应的候选路径：
+// 根目录使用主机名与域名生成；递归发现的目录在其父目录下生成同名备份，例如 /admin -> /admin.zip
+func (g *backupWordlist) Candidates(baseURL string, root bool) []backupCandidate {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	if root {
+		return g.expand(baseURL, g.hostWords(u.Hostname()), true)
+	}
+
+	dir := strings.Trim(u.Path, "/")
+	if dir == "" {
+		return nil
+	}
+	parent, name := "", dir
+	if idx := strings.LastIndex(dir, "/"); idx >= 0 {
+		parent, name = "/"+dir[:idx], dir[idx+1:]
+	}
+	return g.expand(u.Scheme+"://"+u.Host+parent, []string{name}, false)
+}
+
+// hostWords 由主机名派生文件名：www.example.com -> www.example.com, example.com, www_example_com, example, www
+func (g *backupWordlist) hostWords(host string) []string {
+	if host == "" {
+		return nil
+	}
+	if net.ParseIP(host) != nil {
+		return []string{host, strings.NewReplacer(".", "_", ":", "_").Replace(host)}
+	}
+
+	words := []string{host, strings.ReplaceAll(host, ".", "_")}
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		words = append(words, domain)
+	}
+	// 公共后缀（com、co.uk 等）本身没有意义，只取其余各级标签
+	suffix, _ := publicsuffix.PublicSuffix(host)
+	for _, label := range strings.Split(strings.TrimSuffix(host, suffix), ".") {
+		if label != "" {
+			words = append(words, label)
+		}
+	}
+	return words
+}
+
+func (g *backupWordlist) expand(baseURL string, names []string, withBase bool) []backupCandidate {
+	var words []string
+	words = append(words, names...)
+	if withBase {
+		words = append(words, backupBaseWords...)
+	}
+
+	// 日期组合只用于少量常见名称与目标派生名称，避免候选数量膨胀
+	if withBase {
+		dated := append(append([]string{}, backupDatedWords...), names...)
+		for _, name := range dated {
+			for _, year := range g.years {
+				words = append(words, name+"_"+year, name+year)
+			}
+		}
+	}
+
+	var list []backupCandidate
+	for _, word := range words {
+		for _, ext := range g.extensions {
+			path := word + "." + ext
+			key := baseURL + "/" + path
+			if g.seen[key] {
+				continue
+			}
+			g.seen[key] = true
+			list = append(list, backupCandidate{BaseURL: baseURL, Path: path})
+		}
+	}
+	return list
+}
@@ -0,0 +1,26 @@
+package infogather
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackupHostWords(t *testing.T) {
+	tests := []struct {
+		host string
+		want []string
+	}{
+		{"www.example.com", []string{"www.example.com", "www_example_com", "example.com", "www", "example"}},
+		{"www.example.co.uk", []string{"www.example.co.uk", "www_example_co_uk", "example.co.uk", "www", "example"}},
+		{"shop.example.com.cn", []string{"shop.example.com.cn", "shop_example_com_cn", "example.com.cn", "shop", "example"}},
+		{"localhost", []string{"localhost", "localhost"}},
+		{"192.168.1.10", []string{"192.168.1.10", "192_168_1_10"}},
+		{"", nil},
+	}
+	g := newBackupWordlist(nil)
+	for _, tt := range tests {
+		if got := g.hostWords(tt.host); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("hostWords(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
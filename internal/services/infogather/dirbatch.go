@@ -0,0 +1,93 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"fmt"
+	"sync"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// BatchDirScanConfig 批量目录扫描配置，从资产库中选择 Web 服务作为目标
+type BatchDirScanConfig struct {
+	Services    db.WebServiceFilter `json:"services"`     // 目标筛选：Web 服务 ID / 资产 ID / 指纹 / URL 关键字
+	HostThreads int                 `json:"host_threads"` // 同时扫描的站点数
+	DirScan     DirScanConfig       `json:"dir_scan"`     // 单站点扫描参数（忽略 Target），Threads 为单站点并发
+}
+
+// GetBatchDirScanTargets 预览批量目录扫描将要覆盖的 Web 服务
+func (s *InfoService) GetBatchDirScanTargets(filter db.WebServiceFilter) ([]db.WebService, error) {
+	if s.dbManager == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	return s.dbManager.FindWebServices(filter)
+}
+
+// StartBatchDirScan 对筛选出的 Web 服务批量执行目录扫描，返回目标数量
+// 字典只加载一次，进度按全部站点汇总推送，结果直接归属到对应的 web_service_id
+func (s *InfoService) StartBatchDirScan(config BatchDirScanConfig) (int, error) {
+	services, err := s.GetBatchDirScanTargets(config.Services)
+	if err != nil {
+		return 0, err
+	}
+	if len(services) == 0 {
+		return 0, fmt.Errorf("没有符合条件的 Web 服务")
+	}
+
+	s.startScanTask(func() { s.runBatchDirScan(config, services) })
+	return len(services), nil
+}
+
+func (s *InfoService) runBatchDirScan(config BatchDirScanConfig, services []db.WebService) {
+	defer func() {
+		s.emitLog("批量目录扫描任务完成")
+		runtime.EventsEmit(s.ctx, "dirScanComplete")
+	}()
+
+	if config.HostThreads <= 0 {
+		config.HostThreads = 5
+	}
+
+	logger.Info("开始批量目录扫描", "站点数", len(services), "站点并发", config.HostThreads, "单站点并发", config.DirScan.Threads)
+	s.emitLog(fmt.Sprintf("开始批量目录扫描: %d 个站点 (站点并发: %d)", len(services), config.HostThreads))
+
+	lines, filter, ok := s.prepareDirScan(&config.DirScan)
+	if !ok {
+		return
+	}
+
+	stats := &dirScanStats{}
+	stats.hosts.Store(int64(len(services)))
+	stopReporter := stats.startReporter(func(p DirScanProgress) {
+		runtime.EventsEmit(s.ctx, "dirScanProgress", p)
+	})
+	defer stopReporter()
+
+	sem := make(chan struct{}, config.HostThreads)
+	var wg sync.WaitGroup
+
+	for _, ws := range services {
+		select {
+		case <-s.scanCtx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(ws db.WebService) {
+			defer func() {
+				<-sem
+				stats.hostsDone.Add(1)
+				wg.Done()
+			}()
+
+			cfg := config.DirScan
+			cfg.Target = normalizeDirTarget(ws.URL)
+			s.emitLog(fmt.Sprintf("[批量] 开始扫描站点: %s", cfg.Target))
+			s.scanDirTarget(cfg, dirScanTarget{URL: cfg.Target, AssetID: ws.AssetID, WebServiceID: ws.ID}, lines, filter, stats)
+		}(ws)
+	}
+	wg.Wait()
+}
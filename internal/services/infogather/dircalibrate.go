@@ -0,0 +1,162 @@
+package infogather
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+)
+
+// dirBaseline 随机（必然不存在）路径的响应指纹，用于识别软 404 / 泛解析
+type dirBaseline struct {
+	Status   int
+	Size     int64
+	Words    int
+	Lines    int
+	Title    string
+	Location string // 随机路径已替换为占位符，便于与真实请求比较
+}
+
+const calibratePlaceholder = "{PATH}"
+
+// dirCalibrator 按请求方法与 BaseURL 缓存校准结果，每个递归层级的新目录都会单独校准
+type dirCalibrator struct {
+	probe      func(method, reqURL, path string) (*dirResponse, error)
+	extensions []string
+
+	mu        sync.RWMutex
+	baselines map[string][]dirBaseline
+}
+
+func newDirCalibrator(probe func(method, reqURL, path string) (*dirResponse, error), extensions []string) *dirCalibrator {
+	return &dirCalibrator{
+		probe:      probe,
+		extensions: extensions,
+		baselines:  make(map[string][]dirBaseline),
+	}
+}
+
+// Calibrate 对 baseURL 请求若干随机路径并记录响应指纹，返回非 404 的基线
+func (c *dirCalibrator) Calibrate(ctx context.Context, method, baseURL string) []dirBaseline {
+	key := method + " " + baseURL
+	c.mu.RLock()
+	_, done := c.baselines[key]
+	c.mu.RUnlock()
+	if done {
+		return nil
+	}
+
+	token := randomToken()
+	paths := []string{
+		token,
+		token + "/",
+		"." + token,
+		token + ".html",
+		token + ".php",
+	}
+	for i, ext := range c.extensions {
+		if i >= 5 {
+			break
+		}
+		paths = append(paths, token+"."+strings.TrimPrefix(ext, "."))
+	}
+
+	var baselines []dirBaseline
+	for _, p := range paths {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		resp, err := c.probe(method, baseURL+"/"+p, p)
+		if err != nil {
+			continue
+		}
+		b := dirBaseline{
+			Status:   resp.Status,
+			Size:     resp.Size,
+			Words:    resp.Words,
+			Lines:    resp.Lines,
+			Title:    resp.Title,
+			Location: normalizeLocation(resp.Location, token),
+		}
+		if !containsBaseline(baselines, b) {
+			baselines = append(baselines, b)
+		}
+	}
+
+	c.mu.Lock()
+	c.baselines[key] = baselines
+	c.mu.Unlock()
+
+	var wildcard []dirBaseline
+	for _, b := range baselines {
+		if b.Status != 404 {
+			wildcard = append(wildcard, b)
+		}
+	}
+	return wildcard
+}
+
+// Matches 判断响应是否与 baseURL 的任一基线相同（即软 404）
+func (c *dirCalibrator) Matches(method, baseURL, path string, resp *dirResponse) bool {
+	c.mu.RLock()
+	baselines := c.baselines[method+" "+baseURL]
+	c.mu.RUnlock()
+
+	location := normalizeLocation(resp.Location, strings.Trim(path, "/"))
+	for _, b := range baselines {
+		if b.match(resp, location) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b dirBaseline) match(resp *dirResponse, location string) bool {
+	if b.Status != resp.Status {
+		return false
+	}
+	// 重定向以跳转目标为准，其他响应比较大小 / 词数行数 / 标题
+	if b.Location != "" || location != "" {
+		return b.Location == location
+	}
+	if b.Size == resp.Size {
+		return true
+	}
+	if b.Words == resp.Words && b.Lines == resp.Lines {
+		return true
+	}
+	if b.Title != "" && b.Title == resp.Title {
+		diff := b.Size - resp.Size
+		if diff < 0 {
+			diff = -diff
+		}
+		return diff*10 <= b.Size
+	}
+	return false
+}
+
+func normalizeLocation(location, path string) string {
+	if location == "" || path == "" {
+		return location
+	}
+	return strings.ReplaceAll(location, path, calibratePlaceholder)
+}
+
+func containsBaseline(list []dirBaseline, b dirBaseline) bool {
+	for _, item := range list {
+		if item == b {
+			return true
+		}
+	}
+	return false
+}
+
+func randomToken() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
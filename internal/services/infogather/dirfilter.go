@@ -0,0 +1,241 @@
+package infogather
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ResponseFilterOptions ffuf 风格的响应匹配 / 过滤选项
+// 数值类选项支持逗号分隔的单值与区间，例如 "200-299,301,403"；
+// 时间选项单位为毫秒，支持 "<500"、">1000" 或 "100-500"
+type ResponseFilterOptions struct {
+	MatchCodes  string `json:"match_codes"`
+	MatchSizes  string `json:"match_sizes"`
+	MatchWords  string `json:"match_words"`
+	MatchLines  string `json:"match_lines"`
+	MatchRegex  string `json:"match_regex"` // 同时匹配响应头与响应体
+	MatchTime   string `json:"match_time"`
+	MatchMode   string `json:"match_mode"` // "or"（默认）或 "and"
+	FilterCodes string `json:"filter_codes"`
+	FilterSizes string `json:"filter_sizes"`
+	FilterWords string `json:"filter_words"`
+	FilterLines string `json:"filter_lines"`
+	FilterRegex string `json:"filter_regex"`
+	FilterTime  string `json:"filter_time"`
+	FilterMode  string `json:"filter_mode"` // "or"（默认）或 "and"
+}
+
+// responseCheck 单个匹配条件
+type responseCheck func(r *dirResponse) bool
+
+// responseFilter 编译后的匹配器与过滤器
+type responseFilter struct {
+	matchers  []responseCheck
+	filters   []responseCheck
+	matchAll  bool
+	filterAll bool
+}
+
+// newResponseFilter 编译过滤选项，选项格式错误时返回错误
+func newResponseFilter(opts ResponseFilterOptions) (*responseFilter, error) {
+	f := &responseFilter{
+		matchAll:  strings.EqualFold(opts.MatchMode, "and"),
+		filterAll: strings.EqualFold(opts.FilterMode, "and"),
+	}
+
+	build := func(list *[]responseCheck, codes, sizes, words, lines, re, tm string) error {
+		checks := []struct {
+			spec  string
+			value func(r *dirResponse) int64
+		}{
+			{codes, func(r *dirResponse) int64 { return int64(r.Status) }},
+			{sizes, func(r *dirResponse) int64 { return r.Size }},
+			{words, func(r *dirResponse) int64 { return int64(r.Words) }},
+			{lines, func(r *dirResponse) int64 { return int64(r.Lines) }},
+		}
+		for _, c := range checks {
+			if strings.TrimSpace(c.spec) == "" {
+				continue
+			}
+			ranges, err := parseIntRanges(c.spec)
+			if err != nil {
+				return err
+			}
+			value := c.value
+			*list = append(*list, func(r *dirResponse) bool { return ranges.contains(value(r)) })
+		}
+
+		if re != "" {
+			compiled, err := regexp.Compile(re)
+			if err != nil {
+				return fmt.Errorf("正则表达式无效 %q: %v", re, err)
+			}
+			*list = append(*list, func(r *dirResponse) bool {
+				return compiled.Match(r.Body) || compiled.MatchString(r.RawHeader())
+			})
+		}
+
+		if tm != "" {
+			ranges, err := parseTimeRange(tm)
+			if err != nil {
+				return err
+			}
+			*list = append(*list, func(r *dirResponse) bool { return ranges.contains(r.Duration.Milliseconds()) })
+		}
+		return nil
+	}
+
+	if err := build(&f.matchers, opts.MatchCodes, opts.MatchSizes, opts.MatchWords, opts.MatchLines, opts.MatchRegex, opts.MatchTime); err != nil {
+		return nil, err
+	}
+	if err := build(&f.filters, opts.FilterCodes, opts.FilterSizes, opts.FilterWords, opts.FilterLines, opts.FilterRegex, opts.FilterTime); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Keep 响应命中匹配器且未命中过滤器时保留；未配置匹配器时默认全部命中
+func (f *responseFilter) Keep(r *dirResponse) bool {
+	if len(f.matchers) > 0 && !evalChecks(f.matchers, r, f.matchAll) {
+		return false
+	}
+	if len(f.filters) > 0 && evalChecks(f.filters, r, f.filterAll) {
+		return false
+	}
+	return true
+}
+
+func evalChecks(checks []responseCheck, r *dirResponse, all bool) bool {
+	for _, c := range checks {
+		ok := c(r)
+		if all && !ok {
+			return false
+		}
+		if !all && ok {
+			return true
+		}
+	}
+	return all
+}
+
+type intRange struct{ min, max int64 }
+
+type intRanges []intRange
+
+func (rs intRanges) contains(v int64) bool {
+	for _, r := range rs {
+		if v >= r.min && v <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIntRanges 解析 "200-299,301,403" 形式的区间列表
+func parseIntRanges(spec string) (intRanges, error) {
+	var ranges intRanges
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "all" {
+			ranges = append(ranges, intRange{0, 1<<62 - 1})
+			continue
+		}
+		if lo, hi, ok := strings.Cut(part, "-"); ok {
+			min, err1 := strconv.ParseInt(strings.TrimSpace(lo), 10, 64)
+			max, err2 := strconv.ParseInt(strings.TrimSpace(hi), 10, 64)
+			if err1 != nil || err2 != nil || min > max {
+				return nil, fmt.Errorf("区间格式无效: %s", part)
+			}
+			ranges = append(ranges, intRange{min, max})
+			continue
+		}
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("数值格式无效: %s", part)
+		}
+		ranges = append(ranges, intRange{v, v})
+	}
+	return ranges, nil
+}
+
+// parseTimeRange 解析响应时间条件（毫秒）
+func parseTimeRange(spec string) (intRanges, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case strings.HasPrefix(spec, "<"):
+		v, err := strconv.ParseInt(strings.TrimSpace(spec[1:]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("时间格式无效: %s", spec)
+		}
+		return intRanges{{0, v - 1}}, nil
+	case strings.HasPrefix(spec, ">"):
+		v, err := strconv.ParseInt(strings.TrimSpace(spec[1:]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("时间格式无效: %s", spec)
+		}
+		return intRanges{{v + 1, 1<<62 - 1}}, nil
+	}
+	return parseIntRanges(spec)
+}
+
+// DirScanProgress 目录扫描进度，随 dirScanProgress 事件推送
+type DirScanProgress struct {
+	Total      int64 `json:"total"`      // 已入队请求数
+	Done       int64 `json:"done"`       // 已完成请求数
+	Found      int64 `json:"found"`      // 输出的结果数
+	Filtered   int64 `json:"filtered"`   // 被匹配/过滤规则及 Exclude404 丢弃的数量
+	Calibrated int64 `json:"calibrated"` // 被自动校准识别为软 404 的数量
+	Errors     int64 `json:"errors"`     // 请求失败数
+	Hosts      int64 `json:"hosts"`      // 站点总数（批量扫描）
+	HostsDone  int64 `json:"hosts_done"` // 已完成站点数
+}
+
+// dirScanStats 并发安全的进度计数器
+type dirScanStats struct {
+	total, done, found, filtered, calibrated, errors atomic.Int64
+	hosts, hostsDone                                 atomic.Int64
+}
+
+func (st *dirScanStats) snapshot() DirScanProgress {
+	return DirScanProgress{
+		Total:      st.total.Load(),
+		Done:       st.done.Load(),
+		Found:      st.found.Load(),
+		Filtered:   st.filtered.Load(),
+		Calibrated: st.calibrated.Load(),
+		Errors:     st.errors.Load(),
+		Hosts:      st.hosts.Load(),
+		HostsDone:  st.hostsDone.Load(),
+	}
+}
+
+// startReporter 定期推送进度，返回的函数用于停止并推送最终进度
+func (st *dirScanStats) startReporter(emit func(DirScanProgress)) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				emit(st.snapshot())
+				return
+			case <-ticker.C:
+				emit(st.snapshot())
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
@@ -0,0 +1,139 @@
+package infogather
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+)
+
+const defaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
+// HTTPRequestOptions 自定义请求选项，目录扫描的每一层递归都使用同一份配置
+type HTTPRequestOptions struct {
+	Methods     []string          `json:"methods"`      // 请求方法列表，默认 GET
+	Headers     map[string]string `json:"headers"`      // 自定义请求头
+	Cookies     string            `json:"cookies"`      // 初始 Cookie，例如 "SESSION=abc; token=xyz"
+	UserAgent   string            `json:"user_agent"`   // 为空时使用默认 UA
+	AuthType    string            `json:"auth_type"`    // "basic" / "bearer"
+	AuthUser    string            `json:"auth_user"`    // Basic 认证用户名
+	AuthPass    string            `json:"auth_pass"`    // Basic 认证密码
+	AuthToken   string            `json:"auth_token"`   // Bearer Token
+	Body        string            `json:"body"`         // 请求体模板，支持 {PATH} 与 {URL} 占位符
+	ContentType string            `json:"content_type"` // 请求体类型，默认 application/x-www-form-urlencoded
+}
+
+// methods 返回去重后的大写方法列表
+func (o *HTTPRequestOptions) methods() []string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, m := range o.Methods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m != "" && !seen[m] {
+			seen[m] = true
+			list = append(list, m)
+		}
+	}
+	if len(list) == 0 {
+		list = []string{"GET"}
+	}
+	return list
+}
+
+// newCookieJar 创建 Cookie Jar，并为目标站点写入初始 Cookie
+// Jar 会同时保存扫描过程中服务端下发的 Cookie，从而保持登录态
+func (o *HTTPRequestOptions) newCookieJar(target string) http.CookieJar {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || strings.TrimSpace(o.Cookies) == "" {
+		return jar
+	}
+
+	var cookies []*http.Cookie
+	for _, part := range strings.Split(o.Cookies, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || name == "" {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value), Path: "/"})
+	}
+	jar.SetCookies(u, cookies)
+	return jar
+}
+
+// newRequest 根据配置构造请求，path 为字典中的原始路径
+func (o *HTTPRequestOptions) newRequest(ctx context.Context, method, reqURL, path string) (*http.Request, error) {
+	var body io.Reader
+	if o.Body != "" && method != "GET" && method != "HEAD" {
+		replacer := strings.NewReplacer("{PATH}", path, "{URL}", reqURL)
+		body = strings.NewReader(replacer.Replace(o.Body))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	ua := o.UserAgent
+	if ua == "" {
+		ua = defaultUserAgent
+	}
+	req.Header.Set("User-Agent", ua)
+
+	if body != nil {
+		contentType := o.ContentType
+		if contentType == "" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	switch strings.ToLower(o.AuthType) {
+	case "basic":
+		req.SetBasicAuth(o.AuthUser, o.AuthPass)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+o.AuthToken)
+	}
+
+	for k, v := range o.Headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// applyTo 将共享的 Cookie、认证与请求头补充到已构造的请求上，请求中已存在的头不会被覆盖
+// 用于重放原始请求等场景
+func (o *HTTPRequestOptions) applyTo(req *http.Request) {
+	if o.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", o.UserAgent)
+	}
+	if req.Header.Get("Authorization") == "" {
+		switch strings.ToLower(o.AuthType) {
+		case "basic":
+			req.SetBasicAuth(o.AuthUser, o.AuthPass)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+o.AuthToken)
+		}
+	}
+	if cookies := strings.TrimSpace(o.Cookies); cookies != "" {
+		if existing := req.Header.Get("Cookie"); existing != "" {
+			req.Header.Set("Cookie", existing+"; "+cookies)
+		} else {
+			req.Header.Set("Cookie", cookies)
+		}
+	}
+	for k, v := range o.Headers {
+		if !strings.EqualFold(k, "Host") && req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+}
@@ -4,17 +4,16 @@ import (
 	"JAttack/internal/config"
 	"JAttack/internal/pkg/logger"
 	"bufio"
-	"context"
+	"crypto/sha1"
 	"crypto/tls"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,16 +30,34 @@ type DirScanConfig struct {
 	Redirects      bool     `json:"redirects"`
 	CustomDict     string   `json:"custom_dict"`
 	RecursionDepth int      `json:"recursion_depth"`
+	SkipCalibrate  bool     `json:"skip_calibrate"` // 不做泛解析 / 软 404 响应的自动校准，默认开启校准
+	WAFProbe       bool     `json:"waf_probe"`      // 扫描前发送携带 SQL 注入 / XSS 特征的请求识别 WAF 拦截页，默认关闭
+
+	Seeds         []string `json:"seeds"`           // 额外的起始目录（完整 URL 或路径），例如爬虫发现的目录，仅保留与 Target 同源的条目
+	SkipWellKnown bool     `json:"skip_well_known"` // 不收集 robots.txt / sitemap.xml 等元数据作为种子
+
+	BackupScan       bool     `json:"backup_scan"`       // 根据主机名、域名、已发现目录与年份生成备份 / 敏感文件候选
+	BackupExtensions []string `json:"backup_extensions"` // 备份文件扩展名，为空时使用默认集合
+
+	Filter  ResponseFilterOptions `json:"filter"`  // 响应匹配 / 过滤规则
+	Request HTTPRequestOptions    `json:"request"` // 请求方法、请求头、Cookie 与认证
 }
 
 type DirScanResult struct {
 	URL         string `json:"url"`
+	Method      string `json:"method"`
 	Status      int    `json:"status"`
 	Size        int64  `json:"size"`
 	Location    string `json:"location,omitempty"`
 	Fingerprint string `json:"fingerprint,omitempty"`
 	Title       string `json:"title,omitempty"`
 	ContentType string `json:"content_type,omitempty"`
+	ContentHash string `json:"content_hash,omitempty"`
+	Changed     bool   `json:"changed,omitempty"` // 与上次扫描相比状态码或大小发生变化
+
+	Payload map[string]string `json:"payload,omitempty"` // 模糊测试中各占位符的取值
+
+	WebServiceID int64 `json:"web_service_id,omitempty"`
 }
 
 // GetDictionaries 返回可用字典文件列表
@@ -91,21 +108,7 @@ func (s *InfoService) SelectDictionaryFile() string {
 }
 
 func (s *InfoService) StartDirScan(config DirScanConfig) {
-	s.mu.Lock()
-	if s.scanCancel != nil {
-		s.scanCancel()
-	}
-	s.scanCtx, s.scanCancel = context.WithCancel(context.Background())
-	s.paused.Store(false)
-	s.mu.Unlock()
-
-	// Ensure DB column exists (Quick hack for migration)
-	if db := s.dbManager.GetDB(); db != nil {
-		// Ignore error if column exists
-		db.Exec("ALTER TABLE dir_scan_results ADD COLUMN fingerprint TEXT")
-	}
-
-	go s.runDirScan(config)
+	s.startScanTask(func() { s.runDirScan(config) })
 }
 
 func (s *InfoService) runDirScan(config DirScanConfig) {
@@ -117,10 +120,46 @@ func (s *InfoService) runDirScan(config DirScanConfig) {
 	logger.Info("开始目录扫描", "目标", config.Target, "并发", config.Threads, "递归深度", config.RecursionDepth)
 	s.emitLog(fmt.Sprintf("开始目录扫描: %s (递归深度: %d)", config.Target, config.RecursionDepth))
 
-	if !strings.HasPrefix(config.Target, "http://") && !strings.HasPrefix(config.Target, "https://") {
-		config.Target = "http://" + config.Target
+	config.Target = normalizeDirTarget(config.Target)
+
+	lines, filter, ok := s.prepareDirScan(&config)
+	if !ok {
+		return
+	}
+
+	// Resolve WebService ID for new DB schema
+	var assetID, webServiceID int64
+	if s.dbManager != nil {
+		var err error
+		assetID, webServiceID, err = ensureWebService(s.dbManager, config.Target)
+		if err != nil {
+			logger.Warn("关联 WebService 失败", "目标", config.Target, "错误", err)
+		}
+	}
+
+	stats := &dirScanStats{}
+	stats.hosts.Store(1)
+	stopReporter := stats.startReporter(func(p DirScanProgress) {
+		runtime.EventsEmit(s.ctx, "dirScanProgress", p)
+	})
+	defer stopReporter()
+
+	s.scanDirTarget(config, dirScanTarget{URL: config.Target, AssetID: assetID, WebServiceID: webServiceID}, lines, filter, stats)
+	stats.hostsDone.Add(1)
+}
+
+func normalizeDirTarget(target string) string {
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		target = "http://" + target
+	}
+	return strings.TrimRight(target, "/")
+}
+
+// prepareDirScan 加载字典并编译过滤规则，单目标与批量扫描共用同一份字典
+func (s *InfoService) prepareDirScan(config *DirScanConfig) ([]string, *responseFilter, bool) {
+	if config.Threads <= 0 {
+		config.Threads = 10
 	}
-	config.Target = strings.TrimRight(config.Target, "/")
 
 	wordlistPath := config.CustomDict
 	if wordlistPath == "" {
@@ -139,60 +178,40 @@ func (s *InfoService) runDirScan(config DirScanConfig) {
 
 		if wordlistPath == "" {
 			s.emitLog("未找到默认字典文件")
-			return
+			return nil, nil, false
 		}
 	}
 
+	filter, err := newResponseFilter(config.Filter)
+	if err != nil {
+		s.emitLog(fmt.Sprintf("过滤规则无效: %v", err))
+		return nil, nil, false
+	}
+
 	lines, err := s.loadWordlist(wordlistPath)
 	if err != nil {
 		s.emitLog(fmt.Sprintf("加载字典失败: %v", err))
-		return
+		return nil, nil, false
 	}
 
 	s.emitLog(fmt.Sprintf("字典加载成功，共 %d 行", len(lines)))
+	return lines, filter, true
+}
 
-	// Resolve WebService ID for new DB schema
-	var webServiceID int64
-	if s.dbManager != nil {
-		u, err := url.Parse(config.Target)
-		if err == nil {
-			host := u.Hostname()
-			portStr := u.Port()
-			if portStr == "" {
-				if u.Scheme == "https" {
-					portStr = "443"
-				} else {
-					portStr = "80"
-				}
-			}
-			port, _ := strconv.Atoi(portStr)
-
-			ips, err := net.LookupHost(host)
-			if err == nil && len(ips) > 0 {
-				ip := ips[0]
-				// Upsert Asset
-				if assetID, err := s.dbManager.UpsertAsset(ip, "", true); err == nil {
-					// Upsert Port
-					if portID, err := s.dbManager.UpsertAssetPort(assetID, port, "tcp", u.Scheme, "", "", "", "open"); err == nil {
-						// Upsert WebService
-						webServiceID, _ = s.dbManager.UpsertWebService(assetID, portID, config.Target, "", "", "")
-					}
-				}
-			} else {
-				// Fallback if host is IP
-				if net.ParseIP(host) != nil {
-					if assetID, err := s.dbManager.UpsertAsset(host, "", true); err == nil {
-						if portID, err := s.dbManager.UpsertAssetPort(assetID, port, "tcp", u.Scheme, "", "", "", "open"); err == nil {
-							webServiceID, _ = s.dbManager.UpsertWebService(assetID, portID, config.Target, "", "", "")
-						}
-					}
-				}
-			}
-		}
-	}
+// dirScanTarget 单个扫描目标及其在资产库中的归属
+type dirScanTarget struct {
+	URL          string
+	AssetID      int64
+	WebServiceID int64
+}
+
+// scanDirTarget 对单个站点执行递归目录扫描，config.Threads 为该站点的并发数
+func (s *InfoService) scanDirTarget(config DirScanConfig, target dirScanTarget, lines []string, filter *responseFilter, stats *dirScanStats) {
+	s.warnIfBehindCDNWAF(s.scanCtx, target.URL, target.AssetID, target.WebServiceID, time.Duration(config.Timeout)*time.Millisecond, config.WAFProbe)
 
 	client := &http.Client{
 		Timeout: time.Duration(config.Timeout) * time.Millisecond,
+		Jar:     config.Request.newCookieJar(target.URL),
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		},
@@ -204,15 +223,40 @@ func (s *InfoService) runDirScan(config DirScanConfig) {
 		},
 	}
 
+	seeds := config.Seeds
+	if !config.SkipWellKnown {
+		wellKnown := s.harvestAndSaveWellKnown(s.scanCtx, target.URL, target.WebServiceID, &config.Request, time.Duration(config.Timeout)*time.Millisecond)
+		seeds = append(append([]string{}, seeds...), wellKnown.Directories()...)
+	}
+
+	methods := config.Request.methods()
+	var backup *backupWordlist
+	if config.BackupScan {
+		backup = newBackupWordlist(config.BackupExtensions)
+	}
+	calibrator := newDirCalibrator(func(method, reqURL, path string) (*dirResponse, error) {
+		return s.probeDir(client, &config.Request, method, reqURL, path)
+	}, config.Extensions)
+
 	// BFS Level Management
-	currentTargets := []string{config.Target}
+	currentTargets := []string{target.URL}
 	visited := make(map[string]bool)
-	visited[config.Target] = true
+	visited[target.URL] = true
+	for _, seed := range dirScanSeeds(target.URL, seeds) {
+		if !visited[seed] {
+			visited[seed] = true
+			currentTargets = append(currentTargets, seed)
+		}
+	}
 	var visitedMu sync.Mutex
 
-	type Job struct {
-		BaseURL string
-		Path    string
+	pool := &dirWorkerPool{
+		client:     client,
+		request:    &config.Request,
+		filter:     filter,
+		exclude404: config.Exclude404,
+		calibrator: calibrator,
+		stats:      stats,
 	}
 
 	for depth := 0; depth <= config.RecursionDepth; depth++ {
@@ -224,127 +268,62 @@ func (s *InfoService) runDirScan(config DirScanConfig) {
 			s.emitLog(fmt.Sprintf("进入第 %d 层递归，当前层目标数: %d", depth, len(currentTargets)))
 		}
 
-		jobs := make(chan Job, config.Threads*10)
-		var wg sync.WaitGroup
+		jobs := make(chan dirJob, config.Threads*10)
 
 		var nextLevelTargets []string
 		var nextLevelMu sync.Mutex
 
-		// Start workers
-		for i := 0; i < config.Threads; i++ {
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				for job := range jobs {
-					if s.waitIfPaused() {
-						return
-					}
-
-					select {
-					case <-s.scanCtx.Done():
-						return
-					default:
-					}
-
-					reqURL := job.BaseURL + "/" + strings.TrimLeft(job.Path, "/")
-
-					req, err := http.NewRequestWithContext(s.scanCtx, "GET", reqURL, nil)
-					if err != nil {
-						continue
-					}
-					req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-
-					resp, err := client.Do(req)
-					if err != nil {
-						continue
-					}
-
-					if config.Exclude404 && resp.StatusCode == 404 {
-						resp.Body.Close()
-						continue
-					}
-
-					body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
-					resp.Body.Close()
-					size := int64(len(body))
-
-					contentType := resp.Header.Get("Content-Type")
-					title := ""
-					if strings.Contains(strings.ToLower(contentType), "text/html") {
-						re := regexp.MustCompile(`(?i)<title>(.*?)</title>`)
-						matches := re.FindStringSubmatch(string(body))
-						if len(matches) > 1 {
-							title = strings.TrimSpace(matches[1])
-							if len(title) > 100 {
-								title = title[:100] + "..."
-							}
-						}
-					}
-
-					location := ""
-					if resp.StatusCode >= 300 && resp.StatusCode < 400 {
-						location = resp.Header.Get("Location")
-					}
-
-					fingerprint := ""
-
-					result := DirScanResult{
-						URL:         reqURL,
-						Status:      resp.StatusCode,
-						Size:        size,
-						Location:    location,
-						Fingerprint: fingerprint,
-						Title:       title,
-						ContentType: contentType,
-					}
-
-					runtime.EventsEmit(s.ctx, "dirScanResult", result)
+		// Recursion Check
+		pool.onHit = nil
+		if depth < config.RecursionDepth {
+			pool.onHit = func(result DirScanResult, resp *dirResponse) {
+				reqURL, location := result.URL, resp.Location
+				isDir := strings.HasSuffix(reqURL, "/") || (location != "" && strings.HasSuffix(location, "/"))
+				if resp.Status == 403 {
+					isDir = true
+				}
+				if !isDir {
+					return
+				}
 
-					if err := s.saveDirScanResult(config.Target, result, webServiceID); err != nil {
-						logger.Error("保存目录扫描结果失败", "url", reqURL, "error", err)
+				nextTarget := reqURL
+				if location != "" {
+					if strings.HasPrefix(location, "http") {
+						nextTarget = location
+					} else if strings.HasPrefix(location, "/") {
+						u, _ := url.Parse(reqURL)
+						nextTarget = u.Scheme + "://" + u.Host + location
+					} else {
+						nextTarget = strings.TrimRight(reqURL, "/") + "/" + location
 					}
+				}
+				nextTarget = strings.TrimRight(nextTarget, "/")
 
-					// Recursion Check
-					if depth < config.RecursionDepth {
-						isDir := strings.HasSuffix(reqURL, "/") || (location != "" && strings.HasSuffix(location, "/"))
-						if resp.StatusCode == 403 {
-							isDir = true
-						}
+				visitedMu.Lock()
+				if !visited[nextTarget] {
+					visited[nextTarget] = true
+					visitedMu.Unlock()
 
-						if isDir {
-							nextTarget := reqURL
-							if location != "" {
-								if strings.HasPrefix(location, "http") {
-									nextTarget = location
-								} else if strings.HasPrefix(location, "/") {
-									u, _ := url.Parse(reqURL)
-									nextTarget = u.Scheme + "://" + u.Host + location
-								} else {
-									nextTarget = strings.TrimRight(reqURL, "/") + "/" + location
-								}
-							}
-							nextTarget = strings.TrimRight(nextTarget, "/")
-
-							visitedMu.Lock()
-							if !visited[nextTarget] {
-								visited[nextTarget] = true
-								visitedMu.Unlock()
-
-								nextLevelMu.Lock()
-								nextLevelTargets = append(nextLevelTargets, nextTarget)
-								nextLevelMu.Unlock()
-							} else {
-								visitedMu.Unlock()
-							}
-						}
-					}
+					nextLevelMu.Lock()
+					nextLevelTargets = append(nextLevelTargets, nextTarget)
+					nextLevelMu.Unlock()
+				} else {
+					visitedMu.Unlock()
 				}
-			}()
+			}
 		}
 
 		go func() {
 			defer close(jobs)
-			for _, target := range currentTargets {
+			for _, baseURL := range currentTargets {
+				if !config.SkipCalibrate {
+					for _, method := range methods {
+						for _, b := range calibrator.Calibrate(s.scanCtx, method, baseURL) {
+							s.emitLog(fmt.Sprintf("[校准] %s %s 存在泛解析响应: 状态 %d, 大小 %d, 词数 %d", method, baseURL, b.Status, b.Size, b.Words))
+						}
+					}
+				}
+
 				for _, line := range lines {
 					select {
 					case <-s.scanCtx.Done():
@@ -356,16 +335,40 @@ func (s *InfoService) runDirScan(config DirScanConfig) {
 						for _, ext := range config.Extensions {
 							cleanExt := strings.TrimPrefix(ext, ".")
 							path := strings.ReplaceAll(line, "%EXT%", cleanExt)
-							jobs <- Job{BaseURL: target, Path: path}
+							for _, method := range methods {
+								stats.total.Add(1)
+								jobs <- dirJob{BaseURL: baseURL, Path: path, Method: method, WebServiceID: target.WebServiceID}
+							}
 						}
 					} else {
-						jobs <- Job{BaseURL: target, Path: line}
+						for _, method := range methods {
+							stats.total.Add(1)
+							jobs <- dirJob{BaseURL: baseURL, Path: line, Method: method, WebServiceID: target.WebServiceID}
+						}
+					}
+				}
+
+				if backup != nil {
+					candidates := backup.Candidates(baseURL, baseURL == target.URL)
+					if len(candidates) > 0 {
+						s.emitLog(fmt.Sprintf("[备份] %s 生成 %d 个目标相关候选", baseURL, len(candidates)))
+					}
+					for _, c := range candidates {
+						select {
+						case <-s.scanCtx.Done():
+							return
+						default:
+						}
+						for _, method := range methods {
+							stats.total.Add(1)
+							jobs <- dirJob{BaseURL: c.BaseURL, Path: c.Path, Method: method, WebServiceID: target.WebServiceID}
+						}
 					}
 				}
 			}
 		}()
 
-		wg.Wait()
+		s.runDirWorkers(pool, config.Threads, jobs)
 		currentTargets = nextLevelTargets
 
 		select {
@@ -376,7 +379,218 @@ func (s *InfoService) runDirScan(config DirScanConfig) {
 	}
 }
 
+// dirJob 工作池中的单个请求：目录扫描为 BaseURL + Path，模糊测试为替换占位符后的原始请求
+type dirJob struct {
+	BaseURL string
+	Path    string
+	Method  string
+
+	Raw     *rawRequest       // 非空时按原始请求发送，忽略上面的字段
+	Payload map[string]string // 模糊测试中各占位符的取值
+
+	WebServiceID int64
+}
+
+// dirWorkerPool 目录扫描与模糊测试共用的工作池：暂停 / 取消、进度统计、响应过滤与校准、结果保存与 dirScanResult 事件
+type dirWorkerPool struct {
+	client     *http.Client
+	request    *HTTPRequestOptions // 目录扫描的请求配置，原始请求不使用
+	filter     *responseFilter
+	exclude404 bool
+	calibrator *dirCalibrator // 为空时不做软 404 校准
+	stats      *dirScanStats
+
+	webServices *webServiceCache // 任务未指定 WebServiceID 时按命中 URL 关联 Web 服务，例如模糊测试
+
+	onHit func(result DirScanResult, resp *dirResponse) // 命中并保存后调用，例如目录递归
+}
+
+// runDirWorkers 启动 threads 个工作线程处理 jobs，直到 jobs 关闭或任务取消
+func (s *InfoService) runDirWorkers(pool *dirWorkerPool, threads int, jobs <-chan dirJob) {
+	var wg sync.WaitGroup
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if s.waitIfPaused() {
+					return
+				}
+
+				select {
+				case <-s.scanCtx.Done():
+					return
+				default:
+				}
+
+				var reqURL string
+				var resp *dirResponse
+				var err error
+				if job.Raw != nil {
+					reqURL = job.Raw.URL
+					resp, err = s.sendRaw(pool.client, job.Raw)
+				} else {
+					reqURL = job.BaseURL + "/" + strings.TrimLeft(job.Path, "/")
+					resp, err = s.probeDir(pool.client, pool.request, job.Method, reqURL, job.Path)
+				}
+				pool.stats.done.Add(1)
+				if err != nil {
+					pool.stats.errors.Add(1)
+					continue
+				}
+
+				if (pool.exclude404 && resp.Status == 404) || !pool.filter.Keep(resp) {
+					pool.stats.filtered.Add(1)
+					continue
+				}
+
+				if pool.calibrator != nil && job.Raw == nil && pool.calibrator.Matches(job.Method, job.BaseURL, job.Path, resp) {
+					pool.stats.calibrated.Add(1)
+					continue
+				}
+				pool.stats.found.Add(1)
+
+				result := DirScanResult{
+					URL:         reqURL,
+					Method:      job.Method,
+					Status:      resp.Status,
+					Size:        resp.Size,
+					Location:    resp.Location,
+					Title:       resp.Title,
+					ContentType: resp.ContentType,
+					ContentHash: contentHash(resp.Body),
+					Payload:     job.Payload,
+
+					WebServiceID: job.WebServiceID,
+				}
+
+				if result.WebServiceID == 0 && pool.webServices != nil {
+					result.WebServiceID = pool.webServices.ID(reqURL)
+				}
+				if err := s.saveDirScanResult(&result); err != nil {
+					logger.Error("保存目录扫描结果失败", "url", reqURL, "error", err)
+				}
+
+				runtime.EventsEmit(s.ctx, "dirScanResult", result)
+
+				if pool.onHit != nil {
+					pool.onHit(result, resp)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// dirScanSeeds 将种子解析为与 target 同源的目录 URL
+func dirScanSeeds(target string, seeds []string) []string {
+	base, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	var list []string
+	for _, seed := range seeds {
+		ref, err := url.Parse(strings.TrimSpace(seed))
+		if err != nil || seed == "" {
+			continue
+		}
+		u := base.ResolveReference(ref)
+		if u.Scheme != base.Scheme || u.Host != base.Host {
+			continue
+		}
+		u.RawQuery, u.Fragment = "", ""
+		if seedURL := strings.TrimRight(u.String(), "/"); seedURL != target {
+			list = append(list, seedURL)
+		}
+	}
+	return list
+}
+
+// dirResponse 目录扫描的单次响应摘要，用于过滤、校准与结果展示
+type dirResponse struct {
+	Status      int
+	Size        int64
+	Words       int
+	Lines       int
+	Title       string
+	Location    string
+	ContentType string
+	Header      http.Header
+	Body        []byte
+	Duration    time.Duration
+}
+
+// RawHeader 以 "Key: Value" 行的形式返回响应头，用于正则匹配
+func (r *dirResponse) RawHeader() string {
+	var b strings.Builder
+	for k, vs := range r.Header {
+		for _, v := range vs {
+			b.WriteString(k)
+			b.WriteString(": ")
+			b.WriteString(v)
+			b.WriteString("\r\n")
+		}
+	}
+	return b.String()
+}
+
+var titleRegex = regexp.MustCompile(`(?i)<title>(.*?)</title>`)
+
+// probeDir 按自定义请求配置请求目标 URL 并读取响应摘要
+func (s *InfoService) probeDir(client *http.Client, opts *HTTPRequestOptions, method, reqURL, path string) (*dirResponse, error) {
+	req, err := opts.newRequest(s.scanCtx, method, reqURL, path)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	resp.Body.Close()
+
+	return newDirResponse(resp, body, time.Since(start)), nil
+}
+
+func newDirResponse(resp *http.Response, body []byte, duration time.Duration) *dirResponse {
+	r := &dirResponse{
+		Status:      resp.StatusCode,
+		Size:        int64(len(body)),
+		Words:       len(strings.Fields(string(body))),
+		Lines:       strings.Count(string(body), "\n") + 1,
+		ContentType: resp.Header.Get("Content-Type"),
+		Header:      resp.Header,
+		Body:        body,
+		Duration:    duration,
+	}
+	if len(body) == 0 {
+		r.Lines = 0
+	}
+
+	if strings.Contains(strings.ToLower(r.ContentType), "text/html") {
+		matches := titleRegex.FindSubmatch(body)
+		if len(matches) > 1 {
+			r.Title = strings.TrimSpace(string(matches[1]))
+			if len(r.Title) > 100 {
+				r.Title = r.Title[:100] + "..."
+			}
+		}
+	}
+
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		r.Location = resp.Header.Get("Location")
+	}
+	return r
+}
+
 func (s *InfoService) loadWordlist(path string) ([]string, error) {
+	return readWordlist(path)
+}
+
+// readWordlist 按行读取字典，忽略空行与 # 开头的注释
+func readWordlist(path string) ([]string, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -394,24 +608,34 @@ func (s *InfoService) loadWordlist(path string) ([]string, error) {
 	return lines, scanner.Err()
 }
 
-func (s *InfoService) saveDirScanResult(target string, result DirScanResult, webServiceID int64) error {
-	db := s.dbManager.GetDB()
-	if db == nil {
-		return fmt.Errorf("database not initialized")
+// saveDirScanResult 按 (web_service_id, path, method) 去重保存结果，并回填是否较上次扫描发生变化
+func (s *InfoService) saveDirScanResult(result *DirScanResult) error {
+	if s.dbManager == nil || result.WebServiceID <= 0 {
+		return nil
 	}
 
-	// Save to new IP-centric table if possible
-	if webServiceID > 0 {
-		// Extract path from URL
-		u, err := url.Parse(result.URL)
-		path := "/"
-		if err == nil {
-			path = u.Path
-		}
-		s.dbManager.AddWebDirectory(webServiceID, path, result.Status, int(result.Size), result.Title, result.ContentType, result.Location)
+	path := "/"
+	if u, err := url.Parse(result.URL); err == nil && u.Path != "" {
+		path = u.Path
 	}
 
-	query := `INSERT INTO dir_scan_results (target, url, status, size, location, fingerprint) VALUES (?, ?, ?, ?, ?, ?)`
-	_, err := db.Exec(query, target, result.URL, result.Status, result.Size, result.Location, result.Fingerprint)
+	changed, err := s.dbManager.UpsertWebDirectory(result.WebServiceID, result.Method, path, result.Status, int(result.Size),
+		result.Title, result.ContentType, result.Location, result.ContentHash)
+	result.Changed = changed
+
+	// 像接口的命中同时进入接口清单
+	if looksLikeAPI(path, result.ContentType) {
+		if e, ok := newAPIEndpoint(result.WebServiceID, result.URL, result.Method, "dirscan"); ok {
+			e.StatusCode = result.Status
+			e.ContentType = result.ContentType
+			recordAPIEndpoint(s.dbManager, e)
+		}
+	}
 	return err
 }
+
+// contentHash 响应体的 SHA-1，用于将内容相同的页面归为一组
+func contentHash(body []byte) string {
+	sum := sha1.Sum(body)
+	return hex.EncodeToString(sum[:])
+}
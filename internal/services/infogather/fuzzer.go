@@ -0,0 +1,281 @@
+package infogather
+
+import (
+	"JAttack/internal/pkg/logger"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// FuzzWordlist 一个占位符及其字典
+type FuzzWordlist struct {
+	Keyword string   `json:"keyword"` // 占位符，例如 FUZZ、FUZ2Z
+	Path    string   `json:"path"`    // 字典文件路径
+	Words   []string `json:"words"`   // 直接提供的词条，与字典文件合并
+}
+
+// FuzzConfig 通用 HTTP 模糊测试配置
+// 占位符可以出现在 URL、请求方法、请求头与请求体中的任意位置
+type FuzzConfig struct {
+	Target     string `json:"target"`      // 原始请求为相对路径时使用的协议与主机，如 https://example.com
+	RawRequest string `json:"raw_request"` // 原始请求模板，非空时忽略下面的 URL / Method / Headers / Body
+
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+
+	Wordlists []FuzzWordlist `json:"wordlists"`
+	Mode      string         `json:"mode"` // "clusterbomb"（默认，笛卡尔积）或 "pitchfork"（按行对齐）
+	Threads   int            `json:"threads"`
+	Timeout   int            `json:"timeout"`
+	Redirects bool           `json:"redirects"`
+
+	Filter ResponseFilterOptions `json:"filter"` // 与目录扫描相同的匹配 / 过滤规则
+}
+
+// StartFuzz 启动模糊测试，复用目录扫描的工作池与 dirScanResult / dirScanProgress / dirScanComplete 事件，命中写入目录扫描结果
+func (s *InfoService) StartFuzz(config FuzzConfig) error {
+	template, err := config.template()
+	if err != nil {
+		return err
+	}
+	filter, err := newResponseFilter(config.Filter)
+	if err != nil {
+		return fmt.Errorf("过滤规则无效: %v", err)
+	}
+	if len(config.Wordlists) == 0 {
+		return fmt.Errorf("至少需要一个占位符字典")
+	}
+
+	keywords := make([]string, len(config.Wordlists))
+	lists := make([][]string, len(config.Wordlists))
+	for i, wl := range config.Wordlists {
+		keyword := strings.TrimSpace(wl.Keyword)
+		if keyword == "" {
+			keyword = "FUZZ"
+		}
+		if !template.contains(keyword) {
+			return fmt.Errorf("请求模板中没有占位符 %s", keyword)
+		}
+		words := append([]string{}, wl.Words...)
+		if wl.Path != "" {
+			lines, err := s.loadWordlist(wl.Path)
+			if err != nil {
+				return fmt.Errorf("加载字典 %s 失败: %v", wl.Path, err)
+			}
+			words = append(words, lines...)
+		}
+		if len(words) == 0 {
+			return fmt.Errorf("占位符 %s 的字典为空", keyword)
+		}
+		keywords[i], lists[i] = keyword, words
+	}
+
+	if config.Threads <= 0 {
+		config.Threads = 10
+	}
+
+	s.startScanTask(func() { s.runFuzz(config, template, filter, keywords, lists) })
+	return nil
+}
+
+// template 将配置转换为请求模板
+func (c *FuzzConfig) template() (*rawRequest, error) {
+	if strings.TrimSpace(c.RawRequest) != "" {
+		return parseRawRequest(c.RawRequest, c.Target)
+	}
+	if c.URL == "" {
+		return nil, fmt.Errorf("请提供 URL 或原始请求")
+	}
+	r := &rawRequest{Method: strings.ToUpper(c.Method), URL: c.URL, Body: c.Body}
+	if r.Method == "" {
+		r.Method = "GET"
+	}
+	if !strings.HasPrefix(r.URL, "http://") && !strings.HasPrefix(r.URL, "https://") {
+		r.URL = "http://" + r.URL
+	}
+	for k, v := range c.Headers {
+		r.Headers = append(r.Headers, [2]string{k, v})
+	}
+	if !r.hasHeader("User-Agent") {
+		r.Headers = append(r.Headers, [2]string{"User-Agent", defaultUserAgent})
+	}
+	if r.Body != "" && !r.hasHeader("Content-Type") {
+		r.Headers = append(r.Headers, [2]string{"Content-Type", "application/x-www-form-urlencoded"})
+	}
+	return r, nil
+}
+
+func (r *rawRequest) contains(keyword string) bool {
+	if strings.Contains(r.Method, keyword) || strings.Contains(r.URL, keyword) || strings.Contains(r.Body, keyword) {
+		return true
+	}
+	for _, h := range r.Headers {
+		if strings.Contains(h[0], keyword) || strings.Contains(h[1], keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rawRequest) hasHeader(name string) bool {
+	for _, h := range r.Headers {
+		if strings.EqualFold(h[0], name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *InfoService) runFuzz(config FuzzConfig, template *rawRequest, filter *responseFilter, keywords []string, lists [][]string) {
+	defer func() {
+		s.emitLog("模糊测试任务完成")
+		runtime.EventsEmit(s.ctx, "dirScanComplete")
+	}()
+
+	mode := strings.ToLower(config.Mode)
+	if mode != "pitchfork" {
+		mode = "clusterbomb"
+	}
+	total := fuzzCombinations(mode, lists)
+	logger.Info("开始模糊测试", "URL", template.URL, "模式", mode, "请求数", total)
+	s.emitLog(fmt.Sprintf("开始模糊测试: %s %s (模式: %s, 请求数: %d)", template.Method, template.URL, mode, total))
+
+	client := &http.Client{
+		Timeout: time.Duration(config.Timeout) * time.Millisecond,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !config.Redirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	stats := &dirScanStats{}
+	stats.hosts.Store(1)
+	stats.total.Store(total)
+	stopReporter := stats.startReporter(func(p DirScanProgress) {
+		runtime.EventsEmit(s.ctx, "dirScanProgress", p)
+	})
+	defer stopReporter()
+
+	// 与目录扫描共用工作池：过滤、统计、结果保存与 dirScanResult 事件
+	pool := &dirWorkerPool{client: client, filter: filter, stats: stats}
+	if s.dbManager != nil {
+		pool.webServices = newWebServiceCache(s.dbManager)
+	}
+	jobs := make(chan dirJob, config.Threads*10)
+
+	go func() {
+		defer close(jobs)
+		fuzzIterate(mode, lists, func(words []string) bool {
+			pairs := make([]string, 0, len(keywords)*2)
+			payload := make(map[string]string, len(keywords))
+			for k, keyword := range keywords {
+				pairs = append(pairs, keyword, words[k])
+				payload[keyword] = words[k]
+			}
+			req := template.replace(strings.NewReplacer(pairs...))
+			select {
+			case <-s.scanCtx.Done():
+				return false
+			case jobs <- dirJob{Method: req.Method, Raw: req, Payload: payload}:
+				return true
+			}
+		})
+	}()
+
+	s.runDirWorkers(pool, config.Threads, jobs)
+}
+
+// sendRaw 发送请求并返回与目录扫描相同的响应摘要
+func (s *InfoService) sendRaw(client *http.Client, r *rawRequest) (*dirResponse, error) {
+	req, err := r.build(s.scanCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	resp.Body.Close()
+
+	return newDirResponse(resp, body, time.Since(start)), nil
+}
+
+// fuzzCombinations 计算请求总数
+func fuzzCombinations(mode string, lists [][]string) int64 {
+	if len(lists) == 0 {
+		return 0
+	}
+	if mode == "pitchfork" {
+		n := len(lists[0])
+		for _, l := range lists[1:] {
+			if len(l) < n {
+				n = len(l)
+			}
+		}
+		return int64(n)
+	}
+	total := int64(1)
+	for _, l := range lists {
+		total *= int64(len(l))
+	}
+	return total
+}
+
+// fuzzIterate 按模式依次生成取值组合，fn 返回 false 时停止
+// clusterbomb 以最后一个字典变化最快；pitchfork 在最短的字典用尽时结束
+func fuzzIterate(mode string, lists [][]string, fn func(words []string) bool) {
+	if len(lists) == 0 {
+		return
+	}
+
+	if mode == "pitchfork" {
+		for i := int64(0); i < fuzzCombinations(mode, lists); i++ {
+			words := make([]string, len(lists))
+			for k, l := range lists {
+				words[k] = l[i]
+			}
+			if !fn(words) {
+				return
+			}
+		}
+		return
+	}
+
+	idx := make([]int, len(lists))
+	for {
+		words := make([]string, len(lists))
+		for k, l := range lists {
+			words[k] = l[idx[k]]
+		}
+		if !fn(words) {
+			return
+		}
+
+		k := len(idx) - 1
+		for ; k >= 0; k-- {
+			idx[k]++
+			if idx[k] < len(lists[k]) {
+				break
+			}
+			idx[k] = 0
+		}
+		if k < 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,777 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// graphQLPaths 常见的 GraphQL 端点路径
+var graphQLPaths = []string{
+	"/graphql",
+	"/graphiql",
+	"/api/graphql",
+	"/v1/graphql",
+	"/v2/graphql",
+	"/api/v1/graphql",
+	"/graphql/v1",
+	"/graphql/api",
+	"/gql",
+	"/api/gql",
+	"/query",
+	"/graphql.php",
+	"/index.php?graphql", // WPGraphQL
+	"/playground",
+	"/altair",
+	"/explorer",
+	"/console",
+	"/subscriptions",
+	"/api",
+}
+
+// graphQLFieldWords 无法内省时用于猜测根字段的常见名称，服务端返回的字段建议会继续加入
+var graphQLFieldWords = []string{
+	"me", "viewer", "node", "nodes", "user", "users", "account", "accounts", "profile", "profiles", "member", "members",
+	"admin", "admins", "customer", "customers", "employee", "employees", "role", "roles", "permission", "permissions",
+	"group", "groups", "team", "teams", "organization", "organizations", "project", "projects", "search", "query",
+	"login", "logout", "register", "signup", "signin", "authenticate", "refreshToken", "createToken", "token", "tokens",
+	"apiKey", "apiKeys", "session", "sessions", "secret", "secrets", "password", "resetPassword", "changePassword",
+	"forgotPassword", "verifyEmail", "createUser", "updateUser", "deleteUser", "removeUser", "addUser", "inviteUser",
+	"deleteAccount", "assignRole", "grantPermission", "impersonate", "settings", "setting", "updateSettings", "config",
+	"configuration", "system", "systemInfo", "health", "version", "status", "debug", "file", "files", "upload",
+	"uploadFile", "download", "export", "import", "backup", "restore", "order", "orders", "createOrder", "cancelOrder",
+	"product", "products", "item", "items", "post", "posts", "createPost", "deletePost", "comment", "comments",
+	"message", "messages", "sendMessage", "sendEmail", "notification", "notifications", "payment", "payments",
+	"refund", "transfer", "transaction", "transactions", "invoice", "invoices", "log", "logs", "auditLogs", "report",
+	"reports", "stats", "dashboard", "event", "events", "task", "tasks", "job", "jobs", "execute", "run", "command",
+	"webhook", "webhooks", "createWebhook", "integration", "integrations", "category", "categories", "tag", "tags",
+	"page", "pages", "article", "articles", "document", "documents",
+}
+
+// graphQLArgWords 猜测可选参数时使用的常见参数名
+var graphQLArgWords = []string{
+	"id", "ids", "input", "data", "where", "filter", "first", "last", "after", "before", "limit", "offset", "skip",
+	"take", "page", "pageSize", "orderBy", "sort", "search", "query", "q", "name", "email", "username", "password",
+	"token", "code", "type", "status", "key", "slug", "userId", "uuid", "url", "file", "path", "from", "to",
+}
+
+// graphQLDangerRules 按操作名称标记危险操作，mutationOnly 的规则只作用于 mutation
+var graphQLDangerRules = []struct {
+	re           *regexp.Regexp
+	reason       string
+	mutationOnly bool
+}{
+	{regexp.MustCompile(`(?i)delete|remove|destroy|drop|purge|truncate|wipe|flush|erase|revoke|ban|disable|deactivate|reset`), "destructive", true},
+	{regexp.MustCompile(`(?i)exec|eval|command|shell|script|sql|rawquery|runquery`), "code-execution", false},
+	{regexp.MustCompile(`(?i)admin|role|permission|grant|privilege|impersonate|sudo|superuser`), "privilege", false},
+	{regexp.MustCompile(`(?i)password|passwd|secret|token|apikey|api_key|credential|otp|mfa|session`), "credential", false},
+	{regexp.MustCompile(`(?i)upload|download|file|import|export|backup|restore`), "file", false},
+	{regexp.MustCompile(`(?i)pay|refund|transfer|withdraw|deposit|charge|balance|wallet|coupon|credit`), "financial", true},
+	{regexp.MustCompile(`(?i)webhook|callback|proxy|fetch|url`), "ssrf", true},
+	{regexp.MustCompile(`(?i)debug|internal|system|config|env`), "debug", false},
+}
+
+// GraphQLConfig GraphQL 探测配置
+type GraphQLConfig struct {
+	Targets      []string             `json:"targets"`       // 站点地址，可带应用上下文路径
+	Services     *db.WebServiceFilter `json:"services"`      // 不为空时同时探测资产库中符合条件的 Web 服务
+	Paths        []string             `json:"paths"`         // 额外的端点路径
+	NoSuggestion bool                 `json:"no_suggestion"` // 内省被禁用时不通过字段建议恢复结构
+	Words        []string             `json:"words"`         // 额外的根字段候选名，与内置列表合并
+	Threads      int                  `json:"threads"`       // 同时探测的站点数，默认 5
+	Timeout      int                  `json:"timeout"`       // 单次请求超时（毫秒），默认 10000
+
+	Request HTTPRequestOptions `json:"request"` // 请求头、Cookie 与认证
+}
+
+// GraphQLOperation 根类型上的字段（查询、变更或订阅）
+type GraphQLOperation struct {
+	Kind       string   `json:"kind"` // query、mutation、subscription
+	Name       string   `json:"name"`
+	Args       []string `json:"args"`
+	ReturnType string   `json:"return_type"`
+	Dangerous  bool     `json:"dangerous"`
+	Reason     string   `json:"reason"` // 例如 "destructive,privilege"
+}
+
+// GraphQLEndpoint 确认的 GraphQL 端点，随 graphql:endpoint 事件推送
+type GraphQLEndpoint struct {
+	URL           string             `json:"url"`
+	Transport     string             `json:"transport"`     // POST（JSON 请求体）或 GET（查询串）
+	Introspection bool               `json:"introspection"` // 内省查询可用
+	Suggestions   bool               `json:"suggestions"`   // 错误信息中包含字段建议（Did you mean）
+	Source        string             `json:"source"`        // 结构来源：introspection / suggestion，为空表示未能获取
+	Operations    []GraphQLOperation `json:"operations"`
+	SchemaID      int64              `json:"schema_id"`
+}
+
+// GraphQLResult 探测结果汇总，随 graphql:complete 事件推送
+type GraphQLResult struct {
+	Targets   int               `json:"targets"`
+	Endpoints []GraphQLEndpoint `json:"endpoints"`
+}
+
+type GraphQLService struct {
+	ctx       context.Context
+	dbManager *db.Manager
+
+	mu         sync.Mutex // 保护 scanCancel
+	scanCancel context.CancelFunc
+}
+
+func NewGraphQLService(dbManager *db.Manager) *GraphQLService {
+	return &GraphQLService{
+		dbManager: dbManager,
+	}
+}
+
+func (s *GraphQLService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// StartGraphQLScan 探测 GraphQL 端点并获取结构，结果通过 graphql:endpoint / graphql:complete 事件推送
+func (s *GraphQLService) StartGraphQLScan(config GraphQLConfig) (int, error) {
+	targets, err := resolveScanTargets(s.dbManager, config.Targets, config.Services)
+	if err != nil {
+		return 0, err
+	}
+	config.Targets = targets
+
+	s.mu.Lock()
+	if s.scanCancel != nil {
+		s.scanCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.scanCancel = cancel
+	s.mu.Unlock()
+
+	go s.runScan(ctx, config)
+	return len(targets), nil
+}
+
+// StopGraphQLScan 停止当前 GraphQL 探测任务
+func (s *GraphQLService) StopGraphQLScan() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scanCancel != nil {
+		s.scanCancel()
+		s.scanCancel = nil
+		logger.Info("GraphQL 探测任务已停止")
+	}
+}
+
+// GetGraphQLSchemas 获取 Web 服务上发现的 GraphQL 端点（不含结构文档）
+func (s *GraphQLService) GetGraphQLSchemas(webServiceID int64) ([]db.GraphQLSchema, error) {
+	return s.dbManager.GetGraphQLSchemas(webServiceID)
+}
+
+// GetGraphQLSchema 获取 GraphQL 结构文档，内省得到的结构可直接导入 GraphQL Voyager 等工具
+func (s *GraphQLService) GetGraphQLSchema(id int64) (*db.GraphQLSchema, error) {
+	return s.dbManager.GetGraphQLSchema(id)
+}
+
+// GetGraphQLOperations 获取结构中的查询与变更，危险操作排在前面
+func (s *GraphQLService) GetGraphQLOperations(schemaID int64) ([]db.GraphQLOperation, error) {
+	return s.dbManager.GetGraphQLOperations(schemaID)
+}
+
+func (s *GraphQLService) emitLog(msg string) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "graphql:log", msg)
+	}
+}
+
+func (s *GraphQLService) runScan(ctx context.Context, config GraphQLConfig) {
+	if config.Threads <= 0 {
+		config.Threads = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10000
+	}
+
+	cache := newWebServiceCache(s.dbManager)
+	result := GraphQLResult{Targets: len(config.Targets), Endpoints: []GraphQLEndpoint{}}
+	var mu sync.Mutex
+	defer func() {
+		s.emitLog(fmt.Sprintf("GraphQL 探测完成: %d 个站点，发现 %d 个端点", result.Targets, len(result.Endpoints)))
+		runtime.EventsEmit(s.ctx, "graphql:complete", result)
+	}()
+
+	logger.Info("开始探测 GraphQL", "站点数", len(config.Targets))
+	s.emitLog(fmt.Sprintf("开始探测 GraphQL: %d 个站点", len(config.Targets)))
+
+	sem := make(chan struct{}, config.Threads)
+	var wg sync.WaitGroup
+	for _, target := range config.Targets {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(target string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, ep := range s.scanTarget(ctx, config, target, cache) {
+				mu.Lock()
+				result.Endpoints = append(result.Endpoints, ep)
+				mu.Unlock()
+				runtime.EventsEmit(s.ctx, "graphql:endpoint", ep)
+			}
+		}(target)
+	}
+	wg.Wait()
+}
+
+// scanTarget 探测单个站点的候选路径，接口清单中像 GraphQL 的路径同样作为候选
+func (s *GraphQLService) scanTarget(ctx context.Context, config GraphQLConfig, target string, cache *webServiceCache) []GraphQLEndpoint {
+	var candidates []string
+	for _, p := range append(append([]string{}, graphQLPaths...), config.Paths...) {
+		candidates = append(candidates, target+"/"+strings.TrimLeft(p, "/"))
+	}
+	if u, err := url.Parse(target); err == nil && s.dbManager != nil {
+		if wsID := cache.ID(target); wsID > 0 {
+			if known, err := s.dbManager.GetAPIEndpoints(wsID); err == nil {
+				for _, e := range known {
+					if strings.Contains(strings.ToLower(e.Path), "graphql") || strings.Contains(strings.ToLower(e.Path), "gql") {
+						candidates = append(candidates, u.Scheme+"://"+u.Host+e.Path)
+					}
+				}
+			}
+		}
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(config.Timeout) * time.Millisecond,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	var found []GraphQLEndpoint
+	seen := make(map[string]bool)
+	fingerprints := make(map[string]bool)
+	for _, endpoint := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		if seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+
+		c := &graphQLClient{ctx: ctx, client: client, opts: &config.Request, endpoint: endpoint, canary: graphQLCanary()}
+		if !c.detect() {
+			continue
+		}
+		s.emitLog(fmt.Sprintf("发现 GraphQL 端点: %s (%s)", endpoint, c.transport))
+
+		ep := GraphQLEndpoint{URL: endpoint, Transport: c.transport, Operations: []GraphQLOperation{}}
+		schema, ops, err := c.introspect()
+		if err == nil {
+			ep.Introspection = true
+			ep.Source = "introspection"
+		} else if !config.NoSuggestion {
+			logger.Info("GraphQL 内省不可用，尝试通过字段建议恢复结构", "端点", endpoint, "原因", err)
+			words := append(append([]string{}, graphQLFieldWords...), config.Words...)
+			ops = c.recover(words)
+			ep.Suggestions = c.suggestions
+			if len(ops) > 0 {
+				ep.Source = "suggestion"
+				data, _ := json.Marshal(ops)
+				schema = string(data)
+			}
+		}
+		for i := range ops {
+			classifyGraphQLOperation(&ops[i])
+		}
+		if ops != nil {
+			ep.Operations = ops
+		}
+
+		// 框架常把同一个端点挂在多个路径上，结构相同的只保留第一个
+		var names []string
+		for _, op := range ep.Operations {
+			names = append(names, op.Kind+":"+op.Name)
+		}
+		sort.Strings(names)
+		fp := ep.Transport + "|" + strings.Join(names, ",")
+		if len(names) > 0 && fingerprints[fp] {
+			continue
+		}
+		fingerprints[fp] = true
+
+		ep.SchemaID = s.save(cache, ep, schema)
+		found = append(found, ep)
+	}
+	return found
+}
+
+// save 写入 GraphQL 结构与操作，端点本身加入接口清单
+func (s *GraphQLService) save(cache *webServiceCache, ep GraphQLEndpoint, schema string) int64 {
+	if s.dbManager == nil {
+		return 0
+	}
+	wsID := cache.ID(ep.URL)
+	if wsID == 0 {
+		return 0
+	}
+
+	row := db.GraphQLSchema{WebServiceID: wsID, Endpoint: ep.URL, Transport: ep.Transport, Source: ep.Source, Schema: schema}
+	var ops []db.GraphQLOperation
+	for _, op := range ep.Operations {
+		switch op.Kind {
+		case "query":
+			row.Queries++
+		case "mutation":
+			row.Mutations++
+		}
+		ops = append(ops, db.GraphQLOperation{Kind: op.Kind, Name: op.Name, Args: op.Args, ReturnType: op.ReturnType, Dangerous: op.Dangerous, Reason: op.Reason})
+	}
+	id, err := s.dbManager.SaveGraphQLSchema(row, ops)
+	if err != nil {
+		logger.Warn("保存 GraphQL 结构失败", "端点", ep.URL, "错误", err)
+	}
+
+	if e, ok := newAPIEndpoint(wsID, ep.URL, ep.Transport, "graphql"); ok {
+		e.StatusCode = 200
+		e.ContentType = "application/json"
+		recordAPIEndpoint(s.dbManager, e)
+	}
+	return id
+}
+
+// classifyGraphQLOperation 按名称标记危险操作
+func classifyGraphQLOperation(op *GraphQLOperation) {
+	var reasons []string
+	for _, rule := range graphQLDangerRules {
+		if rule.mutationOnly && op.Kind != "mutation" {
+			continue
+		}
+		if rule.re.MatchString(op.Name) {
+			reasons = append(reasons, rule.reason)
+		}
+	}
+	op.Dangerous = len(reasons) > 0
+	op.Reason = strings.Join(reasons, ",")
+}
+
+// graphQLQueryTemplate 生成操作的查询模板，参数值留空待填写，返回类型不是内置标量时选择 __typename
+func graphQLQueryTemplate(kind, name string, args []string, returnType string) string {
+	var b strings.Builder
+	b.WriteString(kind)
+	b.WriteString(" { ")
+	b.WriteString(name)
+	if len(args) > 0 {
+		parts := make([]string, len(args))
+		for i, a := range args {
+			parts[i] = a + `: ""`
+		}
+		b.WriteString("(" + strings.Join(parts, ", ") + ")")
+	}
+	switch strings.Trim(returnType, "[]!") {
+	case "String", "Int", "Float", "Boolean", "ID":
+	default:
+		b.WriteString(" { __typename }")
+	}
+	b.WriteString(" }")
+	return b.String()
+}
+
+// graphQLResponse GraphQL 响应，data 保持原始 JSON
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (r *graphQLResponse) messages() []string {
+	list := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		list[i] = e.Message
+	}
+	return list
+}
+
+// graphQLClient 向单个端点发送 GraphQL 查询
+type graphQLClient struct {
+	ctx       context.Context
+	client    *http.Client
+	opts      *HTTPRequestOptions
+	endpoint  string
+	transport string // detect 确认的传输方式
+	canary    string // 不可能存在的字段名，见 graphQLCanary
+
+	suggestions bool // 错误信息中出现过字段建议
+}
+
+// graphQLCanary 生成随机字段名。字段猜测的每个请求都带上它，保证操作一定无法通过校验：
+// 候选全部是服务端建议过的有效字段时，mutation { logout deleteAccount } 这样的批次也不会被执行
+func graphQLCanary() string {
+	return fmt.Sprintf("jattack_%d", time.Now().UnixNano())
+}
+
+// do 发送查询，transport 为空时使用 POST
+func (c *graphQLClient) do(transport, query string) (*graphQLResponse, error) {
+	var req *http.Request
+	var err error
+	if transport == "GET" {
+		u, perr := url.Parse(c.endpoint)
+		if perr != nil {
+			return nil, perr
+		}
+		q := u.Query()
+		q.Set("query", query)
+		u.RawQuery = q.Encode()
+		req, err = http.NewRequestWithContext(c.ctx, "GET", u.String(), nil)
+	} else {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req, err = http.NewRequestWithContext(c.ctx, "POST", c.endpoint, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	c.opts.applyTo(req)
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", defaultUserAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 20*1024*1024))
+	if err != nil {
+		return nil, err
+	}
+	var r graphQLResponse
+	if err := json.Unmarshal(bytes.TrimSpace(data), &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// graphQLErrorHint GraphQL 服务端特有的错误信息；REST 接口的 "invalid query parameter" 之类不算
+var graphQLErrorHint = regexp.MustCompile(`Cannot query field|Syntax Error|Must provide query string|must have a selection of subfields|GraphQL operations must contain|Unknown operation named|POST body missing|GET query missing`)
+
+// detect 发送 {__typename}，先 POST 后 GET，响应中 data.__typename 存在或出现 GraphQL 特有的错误即视为 GraphQL 端点
+func (c *graphQLClient) detect() bool {
+	for _, transport := range []string{"POST", "GET"} {
+		r, err := c.do(transport, "query { __typename }")
+		if err != nil {
+			continue
+		}
+		var data map[string]any
+		if json.Unmarshal(r.Data, &data) == nil && data["__typename"] != nil {
+			c.transport = transport
+			return true
+		}
+		for _, msg := range r.messages() {
+			if graphQLErrorHint.MatchString(msg) {
+				c.transport = transport
+				return true
+			}
+		}
+	}
+	return false
+}
+
+const graphQLIntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) { name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// graphQLTypeRef 内省结果中的类型引用
+type graphQLTypeRef struct {
+	Kind   string          `json:"kind"`
+	Name   string          `json:"name"`
+	OfType *graphQLTypeRef `json:"ofType"`
+}
+
+func (t *graphQLTypeRef) String() string {
+	if t == nil {
+		return ""
+	}
+	switch t.Kind {
+	case "NON_NULL":
+		return t.OfType.String() + "!"
+	case "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// introspect 执行内省查询，返回可供其他工具导入的 {"data": ...} 文档与根字段
+func (c *graphQLClient) introspect() (string, []GraphQLOperation, error) {
+	r, err := c.do(c.transport, graphQLIntrospectionQuery)
+	if err != nil {
+		return "", nil, err
+	}
+	var data struct {
+		Schema *struct {
+			QueryType        *struct{ Name string } `json:"queryType"`
+			MutationType     *struct{ Name string } `json:"mutationType"`
+			SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+			Types            []struct {
+				Name   string `json:"name"`
+				Fields []struct {
+					Name string `json:"name"`
+					Args []struct {
+						Name string `json:"name"`
+					} `json:"args"`
+					Type *graphQLTypeRef `json:"type"`
+				} `json:"fields"`
+			} `json:"types"`
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(r.Data, &data); err != nil || data.Schema == nil {
+		if msgs := r.messages(); len(msgs) > 0 {
+			return "", nil, fmt.Errorf("%s", msgs[0])
+		}
+		return "", nil, fmt.Errorf("内省结果为空")
+	}
+
+	roots := map[string]string{}
+	if t := data.Schema.QueryType; t != nil {
+		roots[t.Name] = "query"
+	}
+	if t := data.Schema.MutationType; t != nil {
+		roots[t.Name] = "mutation"
+	}
+	if t := data.Schema.SubscriptionType; t != nil {
+		roots[t.Name] = "subscription"
+	}
+	ops := []GraphQLOperation{}
+	for _, t := range data.Schema.Types {
+		kind, ok := roots[t.Name]
+		if !ok {
+			continue
+		}
+		for _, f := range t.Fields {
+			op := GraphQLOperation{Kind: kind, Name: f.Name, ReturnType: f.Type.String()}
+			for _, a := range f.Args {
+				op.Args = append(op.Args, a.Name)
+			}
+			ops = append(ops, op)
+		}
+	}
+
+	doc, _ := json.Marshal(map[string]json.RawMessage{"data": r.Data})
+	return string(doc), ops, nil
+}
+
+var (
+	graphQLQuoted          = `["'\x60]`
+	graphQLSuggestionRegex = regexp.MustCompile(`Did you mean (.+?)\?`)
+	graphQLNameRegex       = regexp.MustCompile(graphQLQuoted + `([_A-Za-z][_0-9A-Za-z]*)` + graphQLQuoted)
+	graphQLUnknownField    = regexp.MustCompile(`Cannot query field ` + graphQLQuoted + `([_A-Za-z][_0-9A-Za-z]*)` + graphQLQuoted)
+	graphQLSubfieldsType   = regexp.MustCompile(`Field ` + graphQLQuoted + `([_A-Za-z][_0-9A-Za-z]*)` + graphQLQuoted + ` of type ` + graphQLQuoted + `([^"'\x60]+)` + graphQLQuoted + ` must have a selection`)
+	graphQLRequiredArg     = regexp.MustCompile(`Field ` + graphQLQuoted + `([_A-Za-z][_0-9A-Za-z]*)` + graphQLQuoted + ` argument ` + graphQLQuoted + `([_A-Za-z][_0-9A-Za-z]*)` + graphQLQuoted + ` of type`)
+	graphQLUnknownArg      = regexp.MustCompile(`Unknown argument ` + graphQLQuoted + `([_A-Za-z][_0-9A-Za-z]*)` + graphQLQuoted)
+	graphQLNoMutations     = regexp.MustCompile(`(?i)not configured (?:to support|for) mutations|does not support mutations|no mutation type`)
+)
+
+const (
+	graphQLBatchSize = 32  // 每个请求猜测的字段数
+	graphQLMaxFields = 200 // 每种根类型最多恢复的字段数
+)
+
+// recover 内省被禁用时，利用校验错误与字段建议（Did you mean）恢复 query 与 mutation 的根字段
+func (c *graphQLClient) recover(words []string) []GraphQLOperation {
+	var ops []GraphQLOperation
+	for _, kind := range []string{"query", "mutation"} {
+		if c.ctx.Err() != nil {
+			break
+		}
+		ops = append(ops, c.recoverRoot(kind, words)...)
+	}
+	return ops
+}
+
+func (c *graphQLClient) recoverRoot(kind string, words []string) []GraphQLOperation {
+	fields := make(map[string]*GraphQLOperation)
+	var order []string
+	tried := make(map[string]bool)
+	queue := append([]string{}, words...)
+
+	for len(queue) > 0 && len(fields) < graphQLMaxFields && c.ctx.Err() == nil {
+		var batch []string
+		for len(queue) > 0 && len(batch) < graphQLBatchSize {
+			w := queue[0]
+			queue = queue[1:]
+			if !tried[w] {
+				tried[w] = true
+				batch = append(batch, w)
+			}
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		r, err := c.do(c.transport, kind+" { "+strings.Join(batch, " ")+" "+c.canary+" }")
+		if err != nil {
+			continue
+		}
+		msgs := r.messages()
+
+		unknown := make(map[string]bool)
+		for _, msg := range msgs {
+			if kind == "mutation" && graphQLNoMutations.MatchString(msg) {
+				return nil
+			}
+			if m := graphQLUnknownField.FindStringSubmatch(msg); m != nil {
+				unknown[m[1]] = true
+			}
+			for _, name := range c.suggested(msg) {
+				if !tried[name] {
+					queue = append(queue, name)
+				}
+			}
+		}
+
+		// 校验会报告全部未知字段，因此服务端报告了随机字段时，其余候选均为有效字段；
+		// 否则只接受出现在其他错误中的候选
+		for _, w := range batch {
+			if unknown[w] {
+				continue
+			}
+			valid := unknown[c.canary]
+			for _, msg := range msgs {
+				if containsQuotedName(msg, w) {
+					valid = true
+				}
+			}
+			if !valid || fields[w] != nil {
+				continue
+			}
+			fields[w] = &GraphQLOperation{Kind: kind, Name: w}
+			order = append(order, w)
+		}
+
+		for _, msg := range msgs {
+			if m := graphQLSubfieldsType.FindStringSubmatch(msg); m != nil && fields[m[1]] != nil {
+				fields[m[1]].ReturnType = m[2]
+			}
+			if m := graphQLRequiredArg.FindStringSubmatch(msg); m != nil && fields[m[1]] != nil {
+				fields[m[1]].Args = append(fields[m[1]].Args, m[2])
+			}
+		}
+	}
+
+	ops := make([]GraphQLOperation, 0, len(order))
+	for _, name := range order {
+		op := fields[name]
+		op.Args = unique(append(op.Args, c.recoverArgs(kind, name)...))
+		ops = append(ops, *op)
+	}
+	return ops
+}
+
+// recoverArgs 一次性传入常见参数名，未被报告为 Unknown argument 的即为存在的参数
+func (c *graphQLClient) recoverArgs(kind, field string) []string {
+	if c.ctx.Err() != nil {
+		return nil
+	}
+	parts := make([]string, len(graphQLArgWords))
+	for i, a := range graphQLArgWords {
+		parts[i] = a + ": 1"
+	}
+	// 随机字段同样保证操作不会被执行
+	r, err := c.do(c.transport, kind+" { "+field+"("+strings.Join(parts, ", ")+") "+c.canary+" }")
+	if err != nil {
+		return nil
+	}
+
+	unknown := make(map[string]bool)
+	var suggested []string
+	for _, msg := range r.messages() {
+		if m := graphQLUnknownArg.FindStringSubmatch(msg); m != nil {
+			unknown[m[1]] = true
+		}
+		for _, name := range c.suggested(msg) {
+			// 随机字段的建议是字段名而非参数名
+			if !strings.Contains(msg, c.canary) {
+				suggested = append(suggested, name)
+			}
+		}
+	}
+	// 服务端不报告未知参数时无法判断
+	if len(unknown) == 0 {
+		return suggested
+	}
+	var args []string
+	for _, a := range graphQLArgWords {
+		if !unknown[a] {
+			args = append(args, a)
+		}
+	}
+	return append(args, suggested...)
+}
+
+// suggested 提取错误信息中 "Did you mean" 之后的名称
+func (c *graphQLClient) suggested(msg string) []string {
+	m := graphQLSuggestionRegex.FindStringSubmatch(msg)
+	if m == nil {
+		return nil
+	}
+	c.suggestions = true
+	var names []string
+	for _, n := range graphQLNameRegex.FindAllStringSubmatch(m[1], -1) {
+		names = append(names, n[1])
+	}
+	return names
+}
+
+// containsQuotedName 判断错误信息中是否以引号包裹的形式出现 name
+func containsQuotedName(msg, name string) bool {
+	for _, m := range graphQLNameRegex.FindAllStringSubmatch(msg, -1) {
+		if m[1] == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,342 @@
+package infogather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGraphQLTypeRefString(t *testing.T) {
+	tests := []struct {
+		ref  *graphQLTypeRef
+		want string
+	}{
+		{nil, ""},
+		{&graphQLTypeRef{Kind: "OBJECT", Name: "User"}, "User"},
+		{&graphQLTypeRef{Kind: "NON_NULL", OfType: &graphQLTypeRef{Kind: "SCALAR", Name: "ID"}}, "ID!"},
+		{
+			&graphQLTypeRef{Kind: "NON_NULL", OfType: &graphQLTypeRef{Kind: "LIST", OfType: &graphQLTypeRef{Kind: "NON_NULL", OfType: &graphQLTypeRef{Kind: "OBJECT", Name: "Post"}}}},
+			"[Post!]!",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.ref.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestGraphQLQueryTemplate(t *testing.T) {
+	tests := []struct {
+		kind, name string
+		args       []string
+		returnType string
+		want       string
+	}{
+		{"query", "me", nil, "User", `query { me { __typename } }`},
+		{"query", "version", nil, "String!", `query { version }`},
+		{"mutation", "login", []string{"username", "password"}, "[ID]", `mutation { login(username: "", password: "") }`},
+		{"query", "node", []string{"id"}, "", `query { node(id: "") { __typename } }`},
+	}
+	for _, tt := range tests {
+		if got := graphQLQueryTemplate(tt.kind, tt.name, tt.args, tt.returnType); got != tt.want {
+			t.Errorf("graphQLQueryTemplate(%q, %q) = %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyGraphQLOperation(t *testing.T) {
+	tests := []struct {
+		op     GraphQLOperation
+		reason string
+	}{
+		{GraphQLOperation{Kind: "mutation", Name: "deleteUser"}, "destructive"},
+		{GraphQLOperation{Kind: "query", Name: "deletedItems"}, ""}, // destructive 只作用于 mutation
+		{GraphQLOperation{Kind: "query", Name: "adminApiKeys"}, "privilege,credential"},
+		{GraphQLOperation{Kind: "mutation", Name: "uploadFile"}, "file"},
+		{GraphQLOperation{Kind: "mutation", Name: "createWebhook"}, "ssrf"},
+		{GraphQLOperation{Kind: "query", Name: "posts"}, ""},
+	}
+	for _, tt := range tests {
+		op := tt.op
+		classifyGraphQLOperation(&op)
+		if op.Reason != tt.reason || op.Dangerous != (tt.reason != "") {
+			t.Errorf("classify(%s %s) = %v %q, want %q", tt.op.Kind, tt.op.Name, op.Dangerous, op.Reason, tt.reason)
+		}
+	}
+}
+
+func TestGraphQLSuggested(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want []string
+	}{
+		{`Cannot query field "usr" on type "Query". Did you mean "user" or "users"?`, []string{"user", "users"}},
+		{`Cannot query field 'accout' on type 'Query'. Did you mean 'account'?`, []string{"account"}},
+		{"Cannot query field `x` on type `Query`. Did you mean `xs`, `xy`, or `xz`?", []string{"xs", "xy", "xz"}},
+		{`Cannot query field "zzz" on type "Query".`, nil},
+	}
+	for _, tt := range tests {
+		c := &graphQLClient{}
+		got := c.suggested(tt.msg)
+		if !reflect.DeepEqual(got, tt.want) || c.suggestions != (tt.want != nil) {
+			t.Errorf("suggested(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func newTestGraphQLClient(endpoint string) *graphQLClient {
+	return &graphQLClient{
+		ctx:      context.Background(),
+		client:   http.DefaultClient,
+		opts:     &HTTPRequestOptions{},
+		endpoint: endpoint,
+		canary:   graphQLCanary(),
+	}
+}
+
+func TestGraphQLDetect(t *testing.T) {
+	tests := []struct {
+		name      string
+		handler   http.HandlerFunc
+		want      bool
+		transport string
+	}{
+		{
+			name: "data.__typename",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"data": {"__typename": "Query"}}`)
+			},
+			want: true, transport: "POST",
+		},
+		{
+			name: "只接受 GET",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "GET" || r.URL.Query().Get("query") == "" {
+					http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+					return
+				}
+				fmt.Fprint(w, `{"data": {"__typename": "Query"}}`)
+			},
+			want: true, transport: "GET",
+		},
+		{
+			name: "GraphQL 校验错误",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"errors": [{"message": "Cannot query field \"__typename\" on type \"Root\"."}]}`)
+			},
+			want: true, transport: "POST",
+		},
+		{
+			name: "REST 接口的 errors 数组",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"errors": [{"message": "invalid query parameter"}]}`)
+			},
+		},
+		{
+			name: "REST 接口的 data 对象",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"code": 0, "data": {"list": []}}`)
+			},
+		},
+		{
+			name: "HTML 页面",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `<html><body>GraphQL Playground</body></html>`)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			c := newTestGraphQLClient(srv.URL)
+			if got := c.detect(); got != tt.want || c.transport != tt.transport {
+				t.Errorf("detect() = %v (%q), want %v (%q)", got, c.transport, tt.want, tt.transport)
+			}
+		})
+	}
+}
+
+func TestGraphQLIntrospect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": {"__schema": {
+			"queryType": {"name": "Query"},
+			"mutationType": {"name": "Mutation"},
+			"subscriptionType": null,
+			"types": [
+				{"name": "Query", "fields": [
+					{"name": "user", "args": [{"name": "id"}], "type": {"kind": "OBJECT", "name": "User"}},
+					{"name": "users", "args": [], "type": {"kind": "LIST", "ofType": {"kind": "OBJECT", "name": "User"}}}
+				]},
+				{"name": "Mutation", "fields": [
+					{"name": "login", "args": [{"name": "email"}, {"name": "password"}], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}}
+				]},
+				{"name": "User", "fields": [{"name": "email", "args": [], "type": {"kind": "SCALAR", "name": "String"}}]}
+			]
+		}}}`)
+	}))
+	defer srv.Close()
+
+	doc, ops, err := newTestGraphQLClient(srv.URL).introspect()
+	if err != nil {
+		t.Fatalf("introspect() error = %v", err)
+	}
+	want := []GraphQLOperation{
+		{Kind: "query", Name: "user", Args: []string{"id"}, ReturnType: "User"},
+		{Kind: "query", Name: "users", ReturnType: "[User]"},
+		{Kind: "mutation", Name: "login", Args: []string{"email", "password"}, ReturnType: "String!"},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("operations = %+v, want %+v", ops, want)
+	}
+	if !strings.HasPrefix(doc, `{"data":{"__schema"`) {
+		t.Errorf("document = %.40q..., want a {\"data\": ...} introspection result", doc)
+	}
+}
+
+func TestGraphQLIntrospectDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors": [{"message": "GraphQL introspection is not allowed"}]}`)
+	}))
+	defer srv.Close()
+
+	if _, _, err := newTestGraphQLClient(srv.URL).introspect(); err == nil || !strings.Contains(err.Error(), "introspection is not allowed") {
+		t.Errorf("introspect() error = %v, want the server message", err)
+	}
+}
+
+// fakeGraphQLValidator 按 graphql-js 的格式返回校验错误，并记录通过校验（会被执行）的操作
+type fakeGraphQLValidator struct {
+	fields   map[string]map[string]fakeGraphQLField // kind -> 字段名 -> 定义
+	mu       sync.Mutex
+	executed []string
+}
+
+type fakeGraphQLField struct {
+	returnType string // 非标量时要求选择子字段
+	args       []string
+	suggest    string // 查询该名称时在错误信息中建议此字段
+}
+
+var fakeGraphQLFieldRegex = regexp.MustCompile(`([_A-Za-z][_0-9A-Za-z]*)\s*(?:\(([^)]*)\))?`)
+
+func (v *fakeGraphQLValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query string `json:"query"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+	kind, rest, _ := strings.Cut(strings.TrimSpace(body.Query), " ")
+	inner := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(rest), "{"), "}")
+	root := map[string]string{"query": "Query", "mutation": "Mutation"}[kind]
+
+	var errs []string
+	for _, m := range fakeGraphQLFieldRegex.FindAllStringSubmatch(inner, -1) {
+		name, args := m[1], m[2]
+		def, ok := v.fields[kind][name]
+		if !ok {
+			msg := fmt.Sprintf(`Cannot query field "%s" on type "%s".`, name, root)
+			for other, d := range v.fields[kind] {
+				if d.suggest == name {
+					msg += fmt.Sprintf(` Did you mean "%s"?`, other)
+				}
+			}
+			errs = append(errs, msg)
+			continue
+		}
+		if args != "" {
+			for _, a := range strings.Split(args, ",") {
+				a, _, _ = strings.Cut(strings.TrimSpace(a), ":")
+				known := false
+				for _, d := range def.args {
+					known = known || d == a
+				}
+				if !known {
+					errs = append(errs, fmt.Sprintf(`Unknown argument "%s" on field "%s.%s".`, a, root, name))
+				}
+			}
+		}
+		if t := strings.Trim(def.returnType, "[]!"); t != "" && t != "Boolean" && t != "String" {
+			errs = append(errs, fmt.Sprintf(`Field "%s" of type "%s" must have a selection of subfields. Did you mean "%s { ... }"?`, name, def.returnType, name))
+		}
+	}
+
+	if len(errs) == 0 {
+		v.mu.Lock()
+		v.executed = append(v.executed, body.Query)
+		v.mu.Unlock()
+		fmt.Fprint(w, `{"data": {}}`)
+		return
+	}
+	resp := map[string]any{"errors": []map[string]string{}}
+	for _, e := range errs {
+		resp["errors"] = append(resp["errors"].([]map[string]string), map[string]string{"message": e})
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func TestGraphQLRecover(t *testing.T) {
+	v := &fakeGraphQLValidator{fields: map[string]map[string]fakeGraphQLField{
+		"query": {
+			"user":        {returnType: "User", args: []string{"id"}},
+			"users":       {returnType: "[User!]!"},
+			"secretNotes": {returnType: "[String]", suggest: "secret"}, // 只能通过字段建议发现
+		},
+		"mutation": {
+			"logout":        {returnType: "Boolean"},
+			"deleteAccount": {returnType: "Boolean", args: []string{"password"}},
+		},
+	}}
+	srv := httptest.NewServer(v)
+	defer srv.Close()
+
+	c := newTestGraphQLClient(srv.URL)
+	got := c.recover(graphQLFieldWords)
+	for i := range got {
+		if len(got[i].Args) == 0 {
+			got[i].Args = nil
+		}
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i].Kind+got[i].Name < got[j].Kind+got[j].Name })
+
+	want := []GraphQLOperation{
+		{Kind: "mutation", Name: "deleteAccount", Args: []string{"password"}},
+		{Kind: "mutation", Name: "logout"},
+		{Kind: "query", Name: "secretNotes"}, // 标量字段不会报告类型
+		{Kind: "query", Name: "user", Args: []string{"id"}, ReturnType: "User"},
+		{Kind: "query", Name: "users", ReturnType: "[User!]!"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("recover() =\n%+v\nwant\n%+v", got, want)
+	}
+	if !c.suggestions {
+		t.Error("suggestions not recorded")
+	}
+	// 每个请求都带有随机字段，mutation { logout deleteAccount } 之类的批次不能通过校验
+	if len(v.executed) > 0 {
+		t.Errorf("operations executed during recovery: %q", v.executed)
+	}
+}
+
+func TestGraphQLRecoverNoMutations(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors": [{"message": "Schema is not configured for mutations."}]}`)
+	}))
+	defer srv.Close()
+
+	if ops := newTestGraphQLClient(srv.URL).recoverRoot("mutation", []string{"logout", "login"}); len(ops) != 0 {
+		t.Errorf("recoverRoot() = %+v, want none", ops)
+	}
+}
@@ -0,0 +1,185 @@
+package infogather
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// rawRequest 解析后的原始 HTTP 请求，各字段均可包含模糊测试占位符
+type rawRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers [][2]string `json:"headers"` // 保持原始顺序，不含 Content-Length（发送时重新计算）
+	Body    string      `json:"body"`
+}
+
+// parseRawRequest 解析 Burp 风格的原始请求
+// 请求行中为相对路径时，协议与主机取自 baseURL（如 https://example.com:8443），baseURL 为空时使用 http 与 Host 头
+func parseRawRequest(raw, baseURL string) (*rawRequest, error) {
+	raw = strings.TrimLeft(raw, "\r\n")
+	head, body := splitRawRequest(raw)
+	head = strings.ReplaceAll(head, "\r\n", "\n")
+
+	scanner := bufio.NewScanner(strings.NewReader(head))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("请求为空")
+	}
+	parts := strings.Fields(scanner.Text())
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("请求行格式无效: %s", scanner.Text())
+	}
+
+	r := &rawRequest{Method: strings.ToUpper(parts[0]), Body: body}
+	target := parts[1]
+
+	var host string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("请求头格式无效: %s", line)
+		}
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		if strings.EqualFold(name, "Host") {
+			host = value
+		}
+		r.Headers = append(r.Headers, [2]string{name, value})
+	}
+
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		r.URL = target
+		return r, nil
+	}
+
+	scheme := "http"
+	if baseURL != "" {
+		if !strings.Contains(baseURL, "://") {
+			baseURL = "http://" + baseURL
+		}
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("目标地址无效: %v", err)
+		}
+		scheme, host = u.Scheme, u.Host
+	}
+	if host == "" {
+		return nil, fmt.Errorf("缺少 Host 头且未指定目标地址")
+	}
+	if !strings.HasPrefix(target, "/") {
+		target = "/" + target
+	}
+	r.URL = scheme + "://" + host + target
+	return r, nil
+}
+
+// splitRawRequest 在第一个空行处拆分请求头与请求体，空行可为 CRLF 或 LF 形式
+// 请求体原样返回，其中的 CRLF（如 multipart 边界）不做转换
+func splitRawRequest(raw string) (head, body string) {
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\n' {
+			continue
+		}
+		rest := raw[i+1:]
+		if strings.HasPrefix(rest, "\r\n") {
+			return raw[:i+1], rest[2:]
+		}
+		if strings.HasPrefix(rest, "\n") {
+			return raw[:i+1], rest[1:]
+		}
+	}
+	return raw, ""
+}
+
+// replace 对请求的所有部分执行占位符替换
+func (r *rawRequest) replace(rep *strings.Replacer) *rawRequest {
+	out := &rawRequest{
+		Method:  rep.Replace(r.Method),
+		URL:     rep.Replace(r.URL),
+		Headers: make([][2]string, len(r.Headers)),
+		Body:    rep.Replace(r.Body),
+	}
+	for i, h := range r.Headers {
+		out.Headers[i] = [2]string{rep.Replace(h[0]), rep.Replace(h[1])}
+	}
+	return out
+}
+
+// build 构造可发送的 http.Request，Host 头写入 req.Host 以支持虚拟主机
+func (r *rawRequest) build(ctx context.Context) (*http.Request, error) {
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range r.Headers {
+		if strings.EqualFold(h[0], "Host") {
+			req.Host = h[1]
+			continue
+		}
+		req.Header.Add(h[0], h[1])
+	}
+	return req, nil
+}
+
+// String 以原始报文形式输出请求
+func (r *rawRequest) String() string {
+	u, err := url.Parse(r.URL)
+	target := r.URL
+	if err == nil {
+		target = requestTarget(r.URL)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s HTTP/1.1\r\n", r.Method, target)
+	hasHost := false
+	for _, h := range r.Headers {
+		if strings.EqualFold(h[0], "Host") {
+			hasHost = true
+		}
+	}
+	if !hasHost && u != nil {
+		fmt.Fprintf(&b, "Host: %s\r\n", u.Host)
+	}
+	for _, h := range r.Headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", h[0], h[1])
+	}
+	if r.Body != "" {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n", len(r.Body))
+	}
+	b.WriteString("\r\n")
+	b.WriteString(r.Body)
+	return b.String()
+}
+
+// requestTarget 返回 URL 中主机之后的路径与查询串，保持原始写法，不像 url.URL.RequestURI 那样把 {id} 等占位符转义
+func requestTarget(rawURL string) string {
+	rest, _, _ := strings.Cut(rawURL, "#")
+	if _, after, ok := strings.Cut(rest, "://"); ok {
+		rest = after
+	}
+	i := strings.IndexAny(rest, "/?")
+	if i < 0 {
+		return "/"
+	}
+	if rest[i] == '?' {
+		return "/" + rest[i:]
+	}
+	return rest[i:]
+}
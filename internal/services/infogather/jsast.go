@@ -0,0 +1,647 @@
+package infogather
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Mzack9999/goja/ast"
+	"github.com/Mzack9999/goja/parser"
+	"github.com/Mzack9999/goja/token"
+)
+
+// JSEndpoint 从 JS 语法树中提取的结构化端点
+type JSEndpoint struct {
+	URL    string   `json:"url"`              // 求值后的路径或 URL，无法求值的部分以 {name} 占位
+	Method string   `json:"method,omitempty"` // GET / POST 等，字符串常量与路由为空
+	Params []string `json:"params,omitempty"` // 查询参数与请求体字段
+	Kind   string   `json:"kind"`             // "call"（HTTP 调用）、"route"（前端路由）、"base"（baseURL）、"string"（路径常量）
+	Caller string   `json:"caller,omitempty"` // 调用方，例如 fetch、axios.post、$.ajax
+	Source string   `json:"source,omitempty"` // 所在脚本
+}
+
+// maxASTSource 超过该大小的脚本只做正则提取，避免解析耗时过长
+const maxASTSource = 8 * 1024 * 1024
+
+// maxEvalDepth 常量展开的最大递归深度，防止循环引用
+const maxEvalDepth = 8
+
+var (
+	// goja 不支持 ES Module 语法，解析前将静态 import / export 去掉，动态 import() 改写为普通调用
+	esImportRegex        = regexp.MustCompile(`(^|[;\n}])\s*import\s*(?:[\w$*{}\s,]+?\s*from\s*)?["'][^"'\n]*["']\s*;?`)
+	esExportListRegex    = regexp.MustCompile(`(^|[;\n}])\s*export\s*(?:\*\s*(?:as\s+[\w$]+\s*)?from\s*["'][^"'\n]*["']|\{[^}]*\}(?:\s*from\s*["'][^"'\n]*["'])?)\s*;?`)
+	esExportDefaultRegex = regexp.MustCompile(`\bexport\s+default\s+`)
+	esExportDeclRegex    = regexp.MustCompile(`\bexport\s+((?:async\s+)?function|class|const|let|var)\b`)
+	esDynamicImportRegex = regexp.MustCompile(`\bimport\s*\(`)
+	esImportMetaRegex    = regexp.MustCompile(`\bimport\.meta\b`)
+
+	// api/user/list 这类不以 / 开头的相对路径
+	relativePathRegex = regexp.MustCompile(`^[\w\-.{}]+/[\w\-.{}/]+(\?.*)?$`)
+
+	// baseKeyRegex 声明接口前缀的配置项与环境常量：axios 的 baseURL、ky 的 prefixUrl，
+	// 以及构建时内联的 VUE_APP_BASE_API、VITE_API_URL、REACT_APP_API_BASE 等
+	baseKeyRegex    = regexp.MustCompile(`^(?:baseURL|baseUrl|BASE_URL|apiBase|baseApi|BASE_API|API_BASE|prefixUrl|apiPrefix|API_PREFIX|apiRoot|API_ROOT|apiUrl|API_URL|(?:VUE_APP|VITE|REACT_APP|NEXT_PUBLIC|UMI_APP)_\w*(?:BASE|API|PREFIX|URL|PATH|HOST)\w*)$`)
+	baseAssignRegex = regexp.MustCompile(`["']?([A-Za-z_$][\w$]*)["']?\s*[:=]\s*["']((?:https?://|/)[^"'\s<>]*)["']`)
+)
+
+var errSourceTooLarge = errors.New("source too large for AST extraction")
+
+// httpMethods 可作为调用方方法名的 HTTP 方法
+var httpMethods = map[string]bool{
+	"get": true, "post": true, "put": true, "delete": true, "patch": true, "head": true, "options": true,
+}
+
+// stripModuleSyntax 把 ES Module 源码转换为 goja 可解析的脚本
+func stripModuleSyntax(src string) string {
+	if !strings.Contains(src, "import") && !strings.Contains(src, "export") {
+		return src
+	}
+	src = esImportRegex.ReplaceAllString(src, "$1")
+	src = esExportListRegex.ReplaceAllString(src, "$1")
+	src = esExportDefaultRegex.ReplaceAllString(src, "var __export_default = ")
+	src = esExportDeclRegex.ReplaceAllString(src, "$1")
+	src = esImportMetaRegex.ReplaceAllString(src, "__import_meta")
+	src = esDynamicImportRegex.ReplaceAllString(src, "__import(")
+	return src
+}
+
+// jsExtractor 基于语法树的端点提取：先收集字符串常量，再识别 HTTP 调用、路由表与路径常量
+type jsExtractor struct {
+	defs      map[string][]ast.Expression // 变量名 / 点号路径 -> 赋值表达式，定义多于一次时不展开
+	routes    map[*ast.ObjectLiteral]bool // 已作为子路由处理的对象
+	consumed  map[ast.Node]bool           // 已作为更大表达式的一部分处理过的字符串片段
+	endpoints []JSEndpoint
+	seen      map[string]int // Kind + Method + URL -> endpoints 下标
+}
+
+// extractJSEndpoints 解析 JS 源码并返回结构化端点，无法解析（例如 HTML 或不支持的语法）时返回错误
+func extractJSEndpoints(content string) ([]JSEndpoint, error) {
+	if len(content) > maxASTSource {
+		return nil, errSourceTooLarge
+	}
+	program, err := parser.ParseFile(nil, "", stripModuleSyntax(content), parser.IgnoreRegExpErrors, parser.WithDisableSourceMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	x := &jsExtractor{
+		defs:     make(map[string][]ast.Expression),
+		routes:   make(map[*ast.ObjectLiteral]bool),
+		consumed: make(map[ast.Node]bool),
+		seen:     make(map[string]int),
+	}
+	walkAST(program, x.collect)
+	walkAST(program, x.extract)
+	return x.endpoints, nil
+}
+
+// walkAST 通过反射遍历语法树，跳过重复引用节点的 DeclarationList 与源文件信息
+func walkAST(node ast.Node, fn func(ast.Node)) {
+	var visit func(v reflect.Value)
+	visit = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Interface:
+			if !v.IsNil() {
+				visit(v.Elem())
+			}
+		case reflect.Ptr:
+			if v.IsNil() {
+				return
+			}
+			if n, ok := v.Interface().(ast.Node); ok {
+				fn(n)
+			}
+			visit(v.Elem())
+		case reflect.Struct:
+			t := v.Type()
+			for i := 0; i < v.NumField(); i++ {
+				switch t.Field(i).Name {
+				case "DeclarationList", "File":
+					continue
+				}
+				if t.Field(i).IsExported() {
+					visit(v.Field(i))
+				}
+			}
+		case reflect.Slice:
+			for i := 0; i < v.Len(); i++ {
+				visit(v.Index(i))
+			}
+		}
+	}
+	visit(reflect.ValueOf(node))
+}
+
+// collect 记录变量、属性赋值与对象字面量中的字符串常量
+func (x *jsExtractor) collect(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.Binding:
+		if id, ok := n.Target.(*ast.Identifier); ok && n.Initializer != nil {
+			x.define(id.Name.String(), n.Initializer)
+		}
+	case *ast.AssignExpression:
+		if n.Operator == token.ASSIGN {
+			if name := dottedName(n.Left); name != "" {
+				x.define(name, n.Right)
+			}
+		}
+	}
+}
+
+func (x *jsExtractor) define(name string, expr ast.Expression) {
+	x.defs[name] = append(x.defs[name], expr)
+	if obj, ok := expr.(*ast.ObjectLiteral); ok {
+		for _, prop := range obj.Value {
+			if key, value := propertyKeyValue(prop); key != "" {
+				x.define(name+"."+key, value)
+			}
+		}
+	}
+}
+
+// lookup 返回唯一定义的常量表达式
+func (x *jsExtractor) lookup(name string) ast.Expression {
+	if defs := x.defs[name]; len(defs) == 1 {
+		return defs[0]
+	}
+	return nil
+}
+
+// eval 对字符串表达式求值，未知部分以 {name} 占位；ok 表示结果中至少包含一段已知文本
+func (x *jsExtractor) eval(expr ast.Expression, depth int) (string, bool) {
+	if expr == nil || depth > maxEvalDepth {
+		return "{?}", false
+	}
+	switch e := expr.(type) {
+	case *ast.StringLiteral:
+		return e.Value.String(), true
+	case *ast.NumberLiteral:
+		return e.Literal, true
+	case *ast.TemplateLiteral:
+		if e.Tag != nil {
+			return "{?}", false
+		}
+		var b strings.Builder
+		ok := false
+		for i, el := range e.Elements {
+			b.WriteString(el.Parsed.String())
+			if el.Parsed != "" {
+				ok = true
+			}
+			if i < len(e.Expressions) {
+				s, sub := x.eval(e.Expressions[i], depth+1)
+				b.WriteString(s)
+				ok = ok || sub
+			}
+		}
+		return b.String(), ok
+	case *ast.BinaryExpression:
+		if e.Operator != token.PLUS {
+			return "{?}", false
+		}
+		l, lok := x.eval(e.Left, depth+1)
+		r, rok := x.eval(e.Right, depth+1)
+		return l + r, lok || rok
+	case *ast.Identifier, *ast.DotExpression:
+		name := dottedName(e)
+		if def := x.lookup(name); def != nil {
+			if s, ok := x.eval(def, depth+1); ok {
+				return s, true
+			}
+		}
+		return "{" + lastName(name) + "}", false
+	case *ast.ConditionalExpression:
+		return x.eval(e.Consequent, depth+1)
+	case *ast.CallExpression:
+		// "".concat(a, "/b") 是 Babel 编译模板字符串的产物
+		if dot, ok := e.Callee.(*ast.DotExpression); ok && dot.Identifier.Name.String() == "concat" {
+			s, ok := x.eval(dot.Left, depth+1)
+			for _, arg := range e.ArgumentList {
+				v, sub := x.eval(arg, depth+1)
+				s += v
+				ok = ok || sub
+			}
+			return s, ok
+		}
+		if id, ok := e.Callee.(*ast.Identifier); ok && len(e.ArgumentList) == 1 {
+			switch id.Name.String() {
+			case "encodeURIComponent", "encodeURI", "String":
+				return x.eval(e.ArgumentList[0], depth+1)
+			}
+		}
+	}
+	return "{?}", false
+}
+
+// extract 识别 HTTP 调用、路由表、baseURL 与路径常量
+func (x *jsExtractor) extract(node ast.Node) {
+	switch n := node.(type) {
+	case *ast.CallExpression:
+		if dot, ok := n.Callee.(*ast.DotExpression); ok && dot.Identifier.Name.String() == "concat" {
+			x.consumed[dot.Left] = true
+			for _, arg := range n.ArgumentList {
+				x.consumed[arg] = true
+			}
+			if s, ok := x.eval(n, 0); ok && !x.consumed[n] {
+				x.addString(s)
+			}
+			return
+		}
+		x.extractCall(n.Callee, n.ArgumentList)
+	case *ast.NewExpression:
+		if id, ok := n.Callee.(*ast.Identifier); ok && len(n.ArgumentList) > 0 {
+			switch id.Name.String() {
+			case "Request":
+				x.extractFetch("new Request", n.ArgumentList)
+			case "WebSocket", "EventSource":
+				x.addCall(id.Name.String(), "GET", n.ArgumentList[0], nil)
+			}
+		}
+	case *ast.ObjectLiteral:
+		x.extractObject(n)
+	case *ast.AssignExpression:
+		// axios.defaults.baseURL = "/api" / window.API_BASE = "/api"
+		if n.Operator == token.ASSIGN && baseKeyRegex.MatchString(lastName(dottedName(n.Left))) {
+			x.addBase(n.Right)
+		}
+	case *ast.Binding:
+		// const BASE_API = "/prod-api"
+		if id, ok := n.Target.(*ast.Identifier); ok && n.Initializer != nil && baseKeyRegex.MatchString(id.Name.String()) {
+			x.addBase(n.Initializer)
+		}
+	case *ast.StringLiteral:
+		if !x.consumed[n] {
+			x.addString(n.Value.String())
+		}
+	case *ast.BinaryExpression:
+		if n.Operator == token.PLUS {
+			// 只取最外层拼接的结果，各个片段不再单独作为路径
+			x.consumed[n.Left], x.consumed[n.Right] = true, true
+			if !x.consumed[n] {
+				if s, ok := x.eval(n, 0); ok {
+					x.addString(s)
+				}
+			}
+		}
+	case *ast.TemplateLiteral:
+		for _, e := range n.Expressions {
+			x.consumed[e] = true
+		}
+		if s, ok := x.eval(n, 0); ok && len(n.Expressions) > 0 && !x.consumed[n] {
+			x.addString(s)
+		}
+	}
+}
+
+func (x *jsExtractor) extractCall(callee ast.Expression, args []ast.Expression) {
+	name := dottedName(callee)
+	method := lastName(name)
+
+	switch {
+	case name == "fetch" || strings.HasSuffix(name, ".fetch"):
+		x.extractFetch("fetch", args)
+
+	case method == "open" && len(args) >= 2:
+		// xhr.open("POST", url)
+		if m, ok := x.eval(args[0], 0); ok && httpMethods[strings.ToLower(m)] {
+			x.addCall(name, strings.ToUpper(m), args[1], nil)
+		}
+
+	case method == "sendBeacon" && len(args) >= 1:
+		x.addCall(name, "POST", args[0], objectKeys(argAt(args, 1)))
+
+	case httpMethods[strings.ToLower(method)] && name != method && len(args) >= 1:
+		// axios.get(url, config) / axios.post(url, data, config) / $.get(url, data) / this.$http.put(url, data)
+		if obj, ok := args[0].(*ast.ObjectLiteral); ok {
+			x.extractConfig(name, strings.ToUpper(method), obj)
+			return
+		}
+		var params []string
+		if method == "get" || method == "delete" || method == "head" || method == "options" {
+			params = configParams(argAt(args, 1), true)
+		} else {
+			params = append(objectKeys(argAt(args, 1)), configParams(argAt(args, 2), false)...)
+		}
+		x.addCall(name, strings.ToUpper(method), args[0], params)
+
+	case len(args) >= 1:
+		// axios(config) / $.ajax(config) / request({url, method, data}) / axios.request(config)
+		if obj, ok := args[0].(*ast.ObjectLiteral); ok && objectProperty(obj, "url") != nil {
+			x.extractConfig(name, "", obj)
+			return
+		}
+		// axios(url, config) / $.ajax(url, settings)
+		if (name == "axios" || method == "ajax" || method == "request") && len(args) >= 2 {
+			if obj, ok := args[1].(*ast.ObjectLiteral); ok {
+				m := x.methodOf(obj)
+				x.addCall(name, m, args[0], configParams(obj, m == "GET"))
+			}
+		}
+	}
+}
+
+// extractFetch 处理 fetch(url, {method, body})
+func (x *jsExtractor) extractFetch(caller string, args []ast.Expression) {
+	if len(args) == 0 {
+		return
+	}
+	method := "GET"
+	var params []string
+	if obj, ok := argAt(args, 1).(*ast.ObjectLiteral); ok {
+		if m := x.methodOf(obj); m != "" {
+			method = m
+		}
+		params = bodyKeys(objectProperty(obj, "body"))
+	}
+	x.addCall(caller, method, args[0], params)
+}
+
+// extractConfig 处理 {url, method | type, params, data} 形式的请求配置
+func (x *jsExtractor) extractConfig(caller, method string, obj *ast.ObjectLiteral) {
+	target := objectProperty(obj, "url")
+	if target == nil {
+		return
+	}
+	if m := x.methodOf(obj); m != "" {
+		method = m
+	}
+	if method == "" {
+		method = "GET"
+	}
+	x.addCall(caller, method, target, configParams(obj, method == "GET"))
+}
+
+func (x *jsExtractor) methodOf(obj *ast.ObjectLiteral) string {
+	for _, key := range []string{"method", "type"} {
+		if v := objectProperty(obj, key); v != nil {
+			if m, ok := x.eval(v, 0); ok && httpMethods[strings.ToLower(m)] {
+				return strings.ToUpper(m)
+			}
+		}
+	}
+	return ""
+}
+
+// extractObject 识别 baseURL 配置、内联的环境常量与前端路由表（{path, component | children | redirect | name}）
+func (x *jsExtractor) extractObject(obj *ast.ObjectLiteral) {
+	for _, prop := range obj.Value {
+		if key, v := propertyKeyValue(prop); v != nil && baseKeyRegex.MatchString(key) {
+			x.addBase(v)
+		}
+	}
+	if !x.routes[obj] {
+		x.extractRoute(obj, "")
+	}
+}
+
+// addBase 记录接口前缀，只保留路径或 URL 形式的值
+func (x *jsExtractor) addBase(expr ast.Expression) {
+	x.consumed[expr] = true
+	if s, ok := x.eval(expr, 0); ok && looksLikeBase(s) {
+		x.add(JSEndpoint{URL: s, Kind: "base"})
+	}
+}
+
+// extractBaseConfigs 语法树解析失败时（HTML、不支持的语法）用正则匹配 key: "value" 与 key = "value" 形式的接口前缀
+func extractBaseConfigs(content string) []JSEndpoint {
+	var bases []JSEndpoint
+	for _, m := range baseAssignRegex.FindAllStringSubmatch(content, -1) {
+		if baseKeyRegex.MatchString(m[1]) && looksLikeBase(m[2]) {
+			bases = append(bases, JSEndpoint{URL: m[2], Kind: "base"})
+		}
+	}
+	return bases
+}
+
+// looksLikeBase 判断配置值是否为接口前缀："/prod-api"、"https://api.example.com/v1" 等
+func looksLikeBase(s string) bool {
+	if len(s) > 200 || strings.ContainsAny(s, " \t\n<>\"'") {
+		return false
+	}
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func (x *jsExtractor) extractRoute(obj *ast.ObjectLiteral, parent string) {
+	pathExpr := objectProperty(obj, "path")
+	if pathExpr == nil {
+		return
+	}
+	isRoute := false
+	for _, key := range []string{"component", "components", "children", "redirect", "element", "loadChildren", "loadComponent"} {
+		if objectProperty(obj, key) != nil {
+			isRoute = true
+			break
+		}
+	}
+	if !isRoute {
+		return
+	}
+	x.consumed[pathExpr] = true
+	p, ok := x.eval(pathExpr, 0)
+	if !ok {
+		return
+	}
+	if !strings.HasPrefix(p, "/") && parent != "" {
+		p = strings.TrimSuffix(parent, "/") + "/" + p
+	}
+	x.routes[obj] = true
+	if p != "" && p != "*" && !strings.HasPrefix(p, "/:pathMatch") {
+		x.add(JSEndpoint{URL: p, Kind: "route"})
+	}
+
+	if children, ok := objectProperty(obj, "children").(*ast.ArrayLiteral); ok {
+		for _, child := range children.Value {
+			if c, ok := child.(*ast.ObjectLiteral); ok {
+				x.extractRoute(c, p)
+			}
+		}
+	}
+}
+
+func (x *jsExtractor) addCall(caller, method string, target ast.Expression, params []string) {
+	x.consumed[target] = true
+	u, ok := x.eval(target, 0)
+	if !ok || !looksLikeEndpoint(u) {
+		return
+	}
+	if i := strings.IndexByte(u, '?'); i >= 0 {
+		if values, err := url.ParseQuery(u[i+1:]); err == nil {
+			for k := range values {
+				params = append(params, k)
+			}
+		}
+	}
+	x.add(JSEndpoint{URL: u, Method: method, Params: params, Kind: "call", Caller: caller})
+}
+
+func (x *jsExtractor) addString(s string) {
+	if looksLikeEndpoint(s) && (strings.HasPrefix(s, "/") || strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")) && isValidEndpoint(s) {
+		x.add(JSEndpoint{URL: s, Kind: "string"})
+	}
+}
+
+// add 按 Kind + Method + URL 去重，重复的调用合并参数
+func (x *jsExtractor) add(ep JSEndpoint) {
+	key := ep.Kind + " " + ep.Method + " " + ep.URL
+	if i, ok := x.seen[key]; ok {
+		x.endpoints[i].Params = unique(append(x.endpoints[i].Params, ep.Params...))
+		sort.Strings(x.endpoints[i].Params)
+		return
+	}
+	ep.Params = unique(ep.Params)
+	sort.Strings(ep.Params)
+	x.seen[key] = len(x.endpoints)
+	x.endpoints = append(x.endpoints, ep)
+}
+
+// uniqueJSEndpoints 合并多个脚本的提取结果，相同端点保留首次出现的来源并合并参数
+func uniqueJSEndpoints(eps []JSEndpoint) []JSEndpoint {
+	x := &jsExtractor{seen: make(map[string]int)}
+	for _, ep := range eps {
+		x.add(ep)
+	}
+	if x.endpoints == nil {
+		return []JSEndpoint{}
+	}
+	return x.endpoints
+}
+
+// looksLikeEndpoint 过滤明显不是 URL 的字符串：空白、HTML、MIME 类型、日期格式等
+func looksLikeEndpoint(s string) bool {
+	if len(s) < 2 || len(s) > 300 || strings.ContainsAny(s, " \t\n<>\"'") {
+		return false
+	}
+	if strings.HasPrefix(s, "//") {
+		// 协议相对地址
+		host, _, _ := strings.Cut(s[2:], "/")
+		return strings.Contains(host, ".")
+	}
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "/") ||
+		strings.HasPrefix(s, "./") || strings.HasPrefix(s, "../") || strings.HasPrefix(s, "{") {
+		return strings.Contains(s, "/")
+	}
+	// api/user/list 这类相对路径至少包含两段，排除 MIME 类型
+	return relativePathRegex.MatchString(s) &&
+		!strings.HasPrefix(s, "text/") && !strings.HasPrefix(s, "application/") && !strings.HasPrefix(s, "image/")
+}
+
+// dottedName 将 a.b.c / this.x 转换为点号路径，其他表达式返回空串
+func dottedName(expr ast.Expression) string {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return e.Name.String()
+	case *ast.ThisExpression:
+		return "this"
+	case *ast.DotExpression:
+		if left := dottedName(e.Left); left != "" {
+			return left + "." + e.Identifier.Name.String()
+		}
+		return e.Identifier.Name.String()
+	}
+	return ""
+}
+
+func lastName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// propertyKeyValue 返回对象属性的键名与值，计算属性名返回空键
+func propertyKeyValue(prop ast.Property) (string, ast.Expression) {
+	switch p := prop.(type) {
+	case *ast.PropertyKeyed:
+		if p.Computed || p.Kind != ast.PropertyKindValue {
+			return "", nil
+		}
+		switch k := p.Key.(type) {
+		case *ast.StringLiteral:
+			return k.Value.String(), p.Value
+		case *ast.Identifier:
+			return k.Name.String(), p.Value
+		case *ast.NumberLiteral:
+			return k.Literal, p.Value
+		}
+	case *ast.PropertyShort:
+		return p.Name.Name.String(), &p.Name
+	}
+	return "", nil
+}
+
+func objectProperty(obj *ast.ObjectLiteral, key string) ast.Expression {
+	for _, prop := range obj.Value {
+		if k, v := propertyKeyValue(prop); k == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// objectKeys 返回对象字面量的键名，非对象返回 nil
+func objectKeys(expr ast.Expression) []string {
+	obj, ok := expr.(*ast.ObjectLiteral)
+	if !ok {
+		return nil
+	}
+	var keys []string
+	for _, prop := range obj.Value {
+		if k, _ := propertyKeyValue(prop); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// configParams 从请求配置中取参数名：params 为查询参数，data / body 为请求体
+// bare 为 true 时，不含已知配置项的对象本身视为参数（jQuery 的 $.get(url, data)）
+func configParams(expr ast.Expression, bare bool) []string {
+	obj, ok := expr.(*ast.ObjectLiteral)
+	if !ok {
+		return nil
+	}
+	var params []string
+	known := false
+	for _, key := range []string{"params", "data", "body", "query"} {
+		if v := objectProperty(obj, key); v != nil {
+			known = true
+			params = append(params, bodyKeys(v)...)
+		}
+	}
+	for _, key := range []string{"headers", "timeout", "method", "type", "url", "withCredentials", "responseType", "dataType", "contentType"} {
+		if objectProperty(obj, key) != nil {
+			known = true
+		}
+	}
+	if !known && bare {
+		params = objectKeys(obj)
+	}
+	return params
+}
+
+// bodyKeys 取请求体字段：对象字面量、JSON.stringify({...})、new URLSearchParams({...}) 与 qs.stringify({...})
+func bodyKeys(expr ast.Expression) []string {
+	switch e := expr.(type) {
+	case *ast.ObjectLiteral:
+		return objectKeys(e)
+	case *ast.CallExpression:
+		if len(e.ArgumentList) > 0 && strings.HasSuffix(dottedName(e.Callee), "stringify") {
+			return objectKeys(e.ArgumentList[0])
+		}
+	case *ast.NewExpression:
+		if len(e.ArgumentList) > 0 {
+			return objectKeys(e.ArgumentList[0])
+		}
+	}
+	return nil
+}
+
+func argAt(args []ast.Expression, i int) ast.Expression {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package infogather
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractJSEndpoints(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []JSEndpoint // 结果中必须包含的端点
+		deny []string     // 不应作为任何端点出现的 URL
+	}{
+		{
+			name: "fetch 与请求体字段",
+			src:  `fetch("/api/login", {method: "POST", body: JSON.stringify({username: u, password: p})})`,
+			want: []JSEndpoint{{URL: "/api/login", Method: "POST", Params: []string{"password", "username"}, Kind: "call", Caller: "fetch"}},
+		},
+		{
+			name: "axios 方法调用与查询参数",
+			src:  `axios.get("/api/user/list?page=1", {params: {size: 10}})`,
+			want: []JSEndpoint{{URL: "/api/user/list?page=1", Method: "GET", Params: []string{"page", "size"}, Kind: "call", Caller: "axios.get"}},
+		},
+		{
+			name: "请求配置对象",
+			src:  `request({url: "/system/user", method: "put", data: {id: 1, name: "x"}})`,
+			want: []JSEndpoint{{URL: "/system/user", Method: "PUT", Params: []string{"id", "name"}, Kind: "call", Caller: "request"}},
+		},
+		{
+			name: "常量展开与字符串拼接",
+			src: `const API = "/api/v1";
+var id = getId();
+axios.post(API + "/order/" + id)`,
+			want: []JSEndpoint{{URL: "/api/v1/order/{id}", Method: "POST", Kind: "call", Caller: "axios.post"}},
+			deny: []string{"/order/"},
+		},
+		{
+			name: "模板字符串",
+			src:  "const base = '/prod-api'; fetch(`${base}/user/${uid}/profile`)",
+			want: []JSEndpoint{{URL: "/prod-api/user/{uid}/profile", Method: "GET", Kind: "call", Caller: "fetch"}},
+		},
+		{
+			name: "XMLHttpRequest",
+			src:  `var xhr = new XMLHttpRequest(); xhr.open("DELETE", "/api/item/3")`,
+			want: []JSEndpoint{{URL: "/api/item/3", Method: "DELETE", Kind: "call", Caller: "xhr.open"}},
+		},
+		{
+			name: "baseURL 配置与环境常量",
+			src: `axios.create({baseURL: "/prod-api", timeout: 5000});
+var VUE_APP_BASE_API = "/dev-api"`,
+			want: []JSEndpoint{{URL: "/prod-api", Kind: "base"}, {URL: "/dev-api", Kind: "base"}},
+		},
+		{
+			name: "嵌套路由表",
+			src:  `new Router({routes: [{path: "/system", component: Layout, children: [{path: "user", component: User}]}]})`,
+			want: []JSEndpoint{{URL: "/system", Kind: "route"}, {URL: "/system/user", Kind: "route"}},
+		},
+		{
+			name: "ES Module 语法",
+			src: `import axios from "axios";
+export const getInfo = () => axios.get("/getInfo");
+export default {};`,
+			want: []JSEndpoint{{URL: "/getInfo", Method: "GET", Kind: "call", Caller: "axios.get"}},
+		},
+		{
+			name: "路径常量过滤 MIME 类型与 HTML",
+			src:  `var a = "/static/config.json", b = "application/json", c = "<div>/x/y</div>"`,
+			want: []JSEndpoint{{URL: "/static/config.json", Kind: "string"}},
+			deny: []string{"application/json", "<div>/x/y</div>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractJSEndpoints(tt.src)
+			if err != nil {
+				t.Fatalf("extractJSEndpoints() error = %v", err)
+			}
+			for _, w := range tt.want {
+				if !containsJSEndpoint(got, w) {
+					t.Errorf("missing %+v in %+v", w, got)
+				}
+			}
+			for _, d := range tt.deny {
+				for _, ep := range got {
+					if ep.URL == d {
+						t.Errorf("unexpected endpoint %+v", ep)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExtractJSEndpointsInvalidSource(t *testing.T) {
+	if _, err := extractJSEndpoints("<html><body>not js</body></html>"); err == nil {
+		t.Error("expected a parse error for HTML input")
+	}
+}
+
+func TestExtractBaseConfigs(t *testing.T) {
+	got := extractBaseConfigs(`<script>window.config = {apiBase: "https://api.example.com/v1", title: "/home", VITE_API_URL: '/gateway'}</script>`)
+	want := []JSEndpoint{{URL: "https://api.example.com/v1", Kind: "base"}, {URL: "/gateway", Kind: "base"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractBaseConfigs() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLooksLikeEndpoint(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"/api/user", true},
+		{"api/user/list", true},
+		{"./api/user", true},
+		{"//cdn.example.com/a.js", true},
+		{"//comment", false},
+		{"text/html", false},
+		{"application/json", false},
+		{"/", false},
+		{"YYYY-MM-DD", false},
+		{"/api/a b", false},
+	}
+	for _, tt := range tests {
+		if got := looksLikeEndpoint(tt.in); got != tt.want {
+			t.Errorf("looksLikeEndpoint(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func containsJSEndpoint(eps []JSEndpoint, want JSEndpoint) bool {
+	for _, ep := range eps {
+		ep.Source = ""
+		if len(ep.Params) == 0 {
+			ep.Params = nil
+		}
+		if reflect.DeepEqual(ep, want) {
+			return true
+		}
+	}
+	return false
+}
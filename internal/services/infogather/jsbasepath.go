@@ -0,0 +1,175 @@
+package infogather
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBasePathSamples is how many call endpoints are requested under each candidate base path
+const maxBasePathSamples = 8
+
+// BasePathCandidate is an API prefix found in the JS configuration and how many sample endpoints exist under it
+type BasePathCandidate struct {
+	Path     string `json:"path"`     // "" is the page-relative resolution without prefix
+	Source   string `json:"source"`   // Script declaring the prefix
+	Hits     int    `json:"hits"`     // Sample endpoints that responded differently from a missing path
+	Samples  int    `json:"samples"`  // Sample endpoints requested
+	Selected bool   `json:"selected"` // The prefix was applied to the relative call endpoints
+}
+
+// basePathCandidates collects the same-origin prefixes declared as baseURL or environment constants.
+// Prefixes on other origins are skipped, the session is never sent there.
+func basePathCandidates(targetURL string, apis []JSEndpoint) []BasePathCandidate {
+	target, err := url.Parse(targetURL)
+	if err != nil {
+		return nil
+	}
+	var candidates []BasePathCandidate
+	seen := make(map[string]bool)
+	for _, api := range apis {
+		if api.Kind != "base" || strings.Contains(api.URL, "{") {
+			continue
+		}
+		u, err := url.Parse(api.URL)
+		if err != nil {
+			continue
+		}
+		if u.IsAbs() && (u.Scheme != target.Scheme || u.Host != target.Host) {
+			continue
+		}
+		path := "/" + strings.Trim(u.Path, "/")
+		if path == "/" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		candidates = append(candidates, BasePathCandidate{Path: path, Source: api.Source})
+	}
+	return candidates
+}
+
+// basePathSamples picks relative GET call endpoints that don't already carry one of the prefixes
+func basePathSamples(apis []JSEndpoint, candidates []BasePathCandidate) []string {
+	var samples []string
+	for _, api := range apis {
+		if len(samples) >= maxBasePathSamples {
+			break
+		}
+		if api.Kind != "call" || (api.Method != "" && api.Method != "GET") || !isRelativeRef(api.URL) ||
+			strings.Contains(api.URL, "{") || isDangerousEndpoint(api.URL) || hasBasePath(api.URL, candidates) {
+			continue
+		}
+		samples = append(samples, api.URL)
+	}
+	return unique(samples)
+}
+
+// isRelativeRef reports whether a reference is resolved against a base by HTTP clients such as axios
+func isRelativeRef(ref string) bool {
+	return !strings.HasPrefix(ref, "//") && !strings.HasPrefix(ref, "../") &&
+		!strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://")
+}
+
+func hasBasePath(ref string, candidates []BasePathCandidate) bool {
+	path := "/" + strings.TrimLeft(strings.TrimPrefix(ref, "./"), "/")
+	for _, c := range candidates {
+		if path == c.Path || strings.HasPrefix(path, c.Path+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// joinBasePath prefixes a relative reference the way axios combines baseURL and url
+func joinBasePath(base, ref string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(strings.TrimPrefix(ref, "./"), "/")
+}
+
+// inferBasePath requests the sample endpoints without prefix and under every candidate. A response
+// counts as a hit unless it is a 404 or matches what a random missing path returns under the same
+// prefix (SPA fallbacks and gateways answer every path). Returns the candidates, the page-relative
+// resolution first, with the winner selected when it resolves more samples than no prefix.
+func (p *endpointProber) inferBasePath(targetURL string, candidates []BasePathCandidate, samples []string, concurrency int) []BasePathCandidate {
+	results := append([]BasePathCandidate{{}}, candidates...)
+	missing := fmt.Sprintf("jattack-missing-%d", time.Now().UnixNano())
+
+	for i := range results {
+		if p.ctx.Err() != nil {
+			return nil
+		}
+		c := &results[i]
+		resolve := func(ref string) (string, error) {
+			if c.Path != "" {
+				ref = joinBasePath(c.Path, ref)
+			}
+			return resolveURL(targetURL, ref)
+		}
+
+		baseline := probeResponse{status: 404}
+		if u, err := resolve(missing); err == nil {
+			if r, err := p.send("GET", u, p.withSession); err == nil {
+				baseline = r
+			}
+		}
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		sem := make(chan struct{}, concurrency)
+		for _, ref := range samples {
+			u, err := resolve(ref)
+			if err != nil {
+				continue
+			}
+			c.Samples++
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(u string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				r, err := p.send("GET", u, p.withSession)
+				if err != nil || r.status == 404 || similarResponses(r, baseline) {
+					return
+				}
+				mu.Lock()
+				c.Hits++
+				mu.Unlock()
+			}(u)
+		}
+		wg.Wait()
+	}
+
+	best := -1
+	for i := 1; i < len(results); i++ {
+		if results[i].Hits > results[0].Hits && (best < 0 || results[i].Hits > results[best].Hits) {
+			best = i
+		}
+	}
+	if best > 0 {
+		results[best].Selected = true
+	}
+	return results
+}
+
+// applyBasePath prefixes the relative call endpoints with the verified base path, both in the
+// structured results and in the endpoint list, so they are probed and stored under the working path
+func applyBasePath(result *JSFindResult, base string) {
+	candidates := []BasePathCandidate{{Path: base}}
+	rewritten := make(map[string]string)
+	for i, api := range result.APIs {
+		if api.Kind != "call" || !isRelativeRef(api.URL) || hasBasePath(api.URL, candidates) {
+			continue
+		}
+		joined := joinBasePath(base, api.URL)
+		rewritten[api.URL] = joined
+		result.APIs[i].URL = joined
+	}
+	for i, ep := range result.Endpoints {
+		if joined, ok := rewritten[ep]; ok {
+			result.Endpoints[i] = joined
+		}
+	}
+	result.Endpoints = unique(result.Endpoints)
+	result.APIs = uniqueJSEndpoints(result.APIs)
+}
@@ -0,0 +1,368 @@
+package infogather
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxChunksPerScript 单个运行时脚本最多推算的 chunk 数量
+const maxChunksPerScript = 2000
+
+var (
+	// webpack 5：__webpack_require__.u = (e) => "static/js/" + e + "." + {...}[e] + ".js"
+	// 压缩后形如 r.u=e=>"js/"+e+".js" 或 r.u=function(e){return ...}
+	webpackChunkFuncRegex = regexp.MustCompile(`(?:^|[^\w$.])[\w$]+\.u\s*=\s*(?:function\s*\(\s*([\w$]+)\s*\)\s*\{\s*(?://[^\n]*\s*)*return\s+|\(?\s*([\w$]+)\s*\)?\s*=>\s*(?:\{\s*(?://[^\n]*\s*)*return\s+)?)`)
+	// webpack 4：function jsonpScriptSrc(e){return a.p+"static/js/"+({}[e]||e)+"."+{...}[e]+".chunk.js"}
+	webpackJsonpSrcRegex = regexp.MustCompile(`function\s*[\w$]*\s*\(\s*([\w$]+)\s*\)\s*\{\s*return\s+([\w$]+\.p\s*\+)`)
+	// publicPath：__webpack_require__.p = "/static/"
+	webpackPublicPathRegex = regexp.MustCompile(`(?:^|[^\w$.])[\w$]+\.p\s*=\s*["']([^"']*)["']`)
+	// 按需加载调用：__webpack_require__.e("src_views_About_vue") / r.e(12)，开发模式下没有 id 映射表时用于枚举 chunk id
+	webpackEnsureRegex = regexp.MustCompile(`[\w$]\.e\(\s*["']?([\w\-./~]+?)["']?\s*\)`)
+	// 对象字面量中的 key: "value"
+	chunkMapEntryRegex = regexp.MustCompile(`(?:"([^"]+)"|'([^']+)'|([\w$.\-]+))\s*:\s*(?:"([^"]*)"|'([^']*)'|(\d+))`)
+
+	// Vite：const __vite__mapDeps=(i,m=__vite__mapDeps,d=(m.f||(m.f=["assets/About-abc.js",...])))=>i.map(i=>d[i])
+	viteMapDepsRegex = regexp.MustCompile(`\.f\s*=\s*\[([^\]]*)\]`)
+	// 动态 import：import("./About-abc.js")
+	dynamicImportRegex = regexp.MustCompile("\\bimport\\(\\s*[\"'`]([^\"'`]+?\\.m?js)[\"'`]\\s*\\)")
+	quotedStringRegex  = regexp.MustCompile(`"([^"]+)"|'([^']+)'`)
+)
+
+// discoverChunks 解析 webpack / Vite 运行时，推算所有按需加载的 chunk 地址
+// scriptURL 为运行时所在脚本（内联在页面中时即页面地址），pageURL 为入口页面
+func discoverChunks(scriptURL, pageURL, content string) []string {
+	var chunks []string
+	chunks = append(chunks, webpackChunks(scriptURL, pageURL, content)...)
+	chunks = append(chunks, viteChunks(scriptURL, pageURL, content)...)
+	chunks = unique(chunks)
+	if len(chunks) > maxChunksPerScript {
+		chunks = chunks[:maxChunksPerScript]
+	}
+	return chunks
+}
+
+// webpackChunks 计算 webpack 运行时中 chunk id -> 文件名映射得到的全部 chunk 地址
+func webpackChunks(scriptURL, pageURL, content string) []string {
+	type chunkFunc struct {
+		param string
+		start int
+	}
+	var funcs []chunkFunc
+	for _, m := range webpackChunkFuncRegex.FindAllStringSubmatchIndex(content, -1) {
+		param := ""
+		if m[2] >= 0 {
+			param = content[m[2]:m[3]]
+		} else if m[4] >= 0 {
+			param = content[m[4]:m[5]]
+		}
+		funcs = append(funcs, chunkFunc{param: param, start: m[1]})
+	}
+	for _, m := range webpackJsonpSrcRegex.FindAllStringSubmatchIndex(content, -1) {
+		funcs = append(funcs, chunkFunc{param: content[m[2]:m[3]], start: m[4]})
+	}
+	if len(funcs) == 0 {
+		return nil
+	}
+
+	var ensured []string
+	for _, m := range webpackEnsureRegex.FindAllStringSubmatch(content, -1) {
+		ensured = append(ensured, m[1])
+	}
+
+	base := webpackPublicPath(scriptURL, pageURL, content)
+	var chunks []string
+	for _, f := range funcs {
+		expr := jsExpression(content[f.start:])
+		for _, name := range evalChunkExpression(expr, f.param, ensured) {
+			if !strings.HasSuffix(strings.SplitN(name, "?", 2)[0], ".js") {
+				continue
+			}
+			if full, err := resolveURL(base, name); err == nil {
+				chunks = append(chunks, full)
+			}
+		}
+	}
+	return chunks
+}
+
+// webpackPublicPath 返回 chunk 的基准地址：显式的 publicPath 相对页面解析，未设置（auto）时为运行时脚本所在目录
+func webpackPublicPath(scriptURL, pageURL, content string) string {
+	if m := webpackPublicPathRegex.FindStringSubmatch(content); m != nil {
+		if full, err := resolveURL(pageURL, m[1]); err == nil {
+			return full
+		}
+	}
+	return scriptURL
+}
+
+// jsExpression 截取从表达式开头到顶层 ; , ) } 为止的源码，跳过字符串与嵌套括号
+func jsExpression(s string) string {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s) && i < 256*1024; i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			if depth == 0 {
+				return s[:i]
+			}
+			depth--
+		case ';', ',', '\n':
+			if depth == 0 {
+				return s[:i]
+			}
+		}
+	}
+	return ""
+}
+
+// splitTopLevel 按顶层的分隔符切分表达式
+func splitTopLevel(expr, sep string) []string {
+	var parts []string
+	depth, last := 0, 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(expr[i:], sep) {
+				parts = append(parts, expr[last:i])
+				last = i + len(sep)
+				i += len(sep) - 1
+			}
+		}
+	}
+	return append(parts, expr[last:])
+}
+
+// chunkTerm 拼接表达式中的一项
+type chunkTerm struct {
+	literal  string
+	isID     bool              // chunk id 本身
+	lookup   map[string]string // {id: value}[id]
+	fallback *chunkTerm        // ({...}[id] || id)
+}
+
+func (t *chunkTerm) value(id string) (string, bool) {
+	switch {
+	case t.isID:
+		return id, true
+	case t.lookup != nil:
+		if v, ok := t.lookup[id]; ok {
+			return v, true
+		}
+		if t.fallback != nil {
+			return t.fallback.value(id)
+		}
+		return "", false
+	}
+	return t.literal, true
+}
+
+// evalChunkExpression 对 "a" + e + "." + {...}[e] + ".js" 形式的表达式，枚举映射表中的全部 chunk id（以及 extraIDs）并求值
+// 无法识别的项（函数调用等）会使整个表达式放弃求值
+func evalChunkExpression(expr, param string, extraIDs []string) []string {
+	if strings.TrimSpace(expr) == "" || param == "" {
+		return nil
+	}
+
+	var terms []*chunkTerm
+	ids := make(map[string]bool)
+	for _, id := range extraIDs {
+		ids[id] = true
+	}
+	for _, raw := range splitTopLevel(expr, "+") {
+		t := parseChunkTerm(strings.TrimSpace(raw), param)
+		if t == nil {
+			return nil
+		}
+		for c := t; c != nil; c = c.fallback {
+			for id := range c.lookup {
+				ids[id] = true
+			}
+		}
+		terms = append(terms, t)
+	}
+
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	var names []string
+	for _, id := range sorted {
+		var b strings.Builder
+		ok := true
+		for _, t := range terms {
+			v, found := t.value(id)
+			if !found {
+				ok = false
+				break
+			}
+			b.WriteString(v)
+		}
+		if ok {
+			names = append(names, b.String())
+		}
+	}
+	return names
+}
+
+func parseChunkTerm(raw, param string) *chunkTerm {
+	for wrappedInParens(raw) {
+		raw = strings.TrimSpace(raw[1 : len(raw)-1])
+	}
+
+	if alts := splitTopLevel(raw, "||"); len(alts) > 1 {
+		t := parseChunkTerm(strings.TrimSpace(alts[0]), param)
+		if t == nil {
+			return nil
+		}
+		t.fallback = parseChunkTerm(strings.TrimSpace(strings.Join(alts[1:], "||")), param)
+		if t.fallback == nil {
+			return nil
+		}
+		return t
+	}
+
+	switch {
+	case raw == param:
+		return &chunkTerm{isID: true}
+	case strings.HasSuffix(raw, ".p") && !strings.ContainsAny(raw, "\"'({["):
+		// publicPath，由调用方统一作为基准地址
+		return &chunkTerm{}
+	case len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') && raw[len(raw)-1] == raw[0]:
+		return &chunkTerm{literal: unquoteJS(raw)}
+	case strings.HasPrefix(raw, "{") && strings.HasSuffix(raw, "["+param+"]"):
+		obj := strings.TrimSuffix(raw, "["+param+"]")
+		lookup := make(map[string]string)
+		for _, m := range chunkMapEntryRegex.FindAllStringSubmatch(obj, -1) {
+			key := m[1] + m[2] + m[3]
+			lookup[key] = m[4] + m[5] + m[6]
+		}
+		return &chunkTerm{lookup: lookup}
+	}
+	return nil
+}
+
+// wrappedInParens 判断整个表达式是否被一对括号包裹，(a)(b) 这类调用返回 false
+func wrappedInParens(raw string) bool {
+	if len(raw) < 2 || raw[0] != '(' || raw[len(raw)-1] != ')' {
+		return false
+	}
+	depth := 0
+	var quote byte
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '"', '\'', '`':
+			quote = c
+		case '(', '{', '[':
+			depth++
+		case ')', '}', ']':
+			depth--
+			if depth == 0 && i < len(raw)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// unquoteJS 解析 JS 字符串字面量，单引号字符串转为双引号后交给 strconv
+func unquoteJS(raw string) string {
+	if raw[0] == '\'' {
+		raw = `"` + strings.ReplaceAll(strings.ReplaceAll(raw[1:len(raw)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	if s, err := strconv.Unquote(raw); err == nil {
+		return s
+	}
+	return raw[1 : len(raw)-1]
+}
+
+// viteChunks 提取 Vite 构建产物中的预加载依赖表与动态 import
+func viteChunks(scriptURL, pageURL, content string) []string {
+	var chunks []string
+
+	if strings.Contains(content, "__vite__mapDeps") {
+		for _, m := range viteMapDepsRegex.FindAllStringSubmatch(content, -1) {
+			for _, q := range quotedStringRegex.FindAllStringSubmatch(m[1], -1) {
+				dep := q[1] + q[2]
+				if !strings.HasSuffix(dep, ".js") {
+					continue
+				}
+				if full, err := resolveURL(viteBase(scriptURL, pageURL, dep), dep); err == nil {
+					chunks = append(chunks, full)
+				}
+			}
+		}
+	}
+
+	for _, m := range dynamicImportRegex.FindAllStringSubmatch(content, -1) {
+		if full, err := resolveURL(scriptURL, m[1]); err == nil {
+			chunks = append(chunks, full)
+		}
+	}
+	return chunks
+}
+
+// viteBase 预加载依赖（如 assets/About-abc.js）相对站点 base 解析：
+// 若运行时脚本位于同名目录下（/app/assets/index.js），base 取该目录的上一级，否则取页面所在目录
+func viteBase(scriptURL, pageURL, dep string) string {
+	dir, _, ok := strings.Cut(dep, "/")
+	if u, err := url.Parse(scriptURL); err == nil && ok {
+		if i := strings.LastIndex(u.Path, "/"+dir+"/"); i >= 0 {
+			u.Path = u.Path[:i+1]
+			u.RawQuery, u.Fragment = "", ""
+			return u.String()
+		}
+	}
+	if u, err := url.Parse(pageURL); err == nil {
+		u.Path = path.Dir(u.Path+"x") + "/"
+		if u.Path == "//" {
+			u.Path = "/"
+		}
+		u.RawQuery, u.Fragment = "", ""
+		return u.String()
+	}
+	return pageURL
+}
@@ -0,0 +1,142 @@
+package infogather
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestEvalChunkExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		param string
+		extra []string
+		want  []string
+	}{
+		{
+			name:  "webpack 5 文件名映射",
+			expr:  `"static/js/" + e + "." + {12: "a1b2", 34: "c3d4"}[e] + ".js"`,
+			param: "e",
+			want:  []string{"static/js/12.a1b2.js", "static/js/34.c3d4.js"},
+		},
+		{
+			name:  "webpack 4 具名 chunk 回退到 id",
+			expr:  `a.p + "static/js/" + ({1: "about"}[e] || e) + "." + {1: "ff", 2: "ee"}[e] + ".chunk.js"`,
+			param: "e",
+			want:  []string{"static/js/about.ff.chunk.js", "static/js/2.ee.chunk.js"},
+		},
+		{
+			name:  "单引号与带引号的键",
+			expr:  `'js/' + {'src_views_About_vue': 'x9'}[t] + '.js'`,
+			param: "t",
+			want:  []string{"js/x9.js"},
+		},
+		{
+			name:  "开发模式只有 id 时使用按需加载调用中的 id",
+			expr:  `"js/" + e + ".js"`,
+			param: "e",
+			extra: []string{"src_views_Home_vue", "chunk-1"},
+			want:  []string{"js/chunk-1.js", "js/src_views_Home_vue.js"},
+		},
+		{
+			name:  "映射表中缺少的 id 被跳过",
+			expr:  `"js/" + e + "." + {1: "aa"}[e] + ".js"`,
+			param: "e",
+			extra: []string{"2"},
+			want:  []string{"js/1.aa.js"},
+		},
+		{
+			name:  "无法识别的函数调用放弃求值",
+			expr:  `"js/" + getName(e) + ".js"`,
+			param: "e",
+			want:  nil,
+		},
+		{
+			name:  "缺少参数名",
+			expr:  `"js/app.js"`,
+			param: "",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evalChunkExpression(tt.expr, tt.param, tt.extra)
+			sort.Strings(got)
+			want := append([]string(nil), tt.want...)
+			sort.Strings(want)
+			if len(got) == 0 && len(want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("evalChunkExpression() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestJSExpression(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`"a" + e + ".js"}, r.p = "/"`, `"a" + e + ".js"`},
+		{`"a;b" + {1: "x"}[e];rest`, `"a;b" + {1: "x"}[e]`},
+		{`("x" + e), next`, `("x" + e)`},
+		{`"unterminated`, ``},
+	}
+	for _, tt := range tests {
+		if got := jsExpression(tt.in); got != tt.want {
+			t.Errorf("jsExpression(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDiscoverChunks(t *testing.T) {
+	tests := []struct {
+		name      string
+		scriptURL string
+		pageURL   string
+		content   string
+		want      []string
+	}{
+		{
+			name:      "webpack 5 显式 publicPath",
+			scriptURL: "https://example.com/static/js/runtime.js",
+			pageURL:   "https://example.com/app/index.html",
+			content:   `r.p="/assets/",r.u=e=>"js/"+e+"."+{7:"abc"}[e]+".js";`,
+			want:      []string{"https://example.com/assets/js/7.abc.js"},
+		},
+		{
+			name:      "webpack 5 auto publicPath 相对运行时脚本",
+			scriptURL: "https://example.com/static/js/runtime.js",
+			pageURL:   "https://example.com/",
+			content:   `__webpack_require__.u = function(chunkId) { return "" + chunkId + ".js"; }; __webpack_require__.e("about")`,
+			want:      []string{"https://example.com/static/js/about.js"},
+		},
+		{
+			name:      "Vite 预加载依赖表",
+			scriptURL: "https://example.com/app/assets/index-abc.js",
+			pageURL:   "https://example.com/app/",
+			content:   `const __vite__mapDeps=(i,m=__vite__mapDeps,d=(m.f||(m.f=["assets/About-1.js","assets/About-1.css"])))=>i.map(i=>d[i]);`,
+			want:      []string{"https://example.com/app/assets/About-1.js"},
+		},
+		{
+			name:      "动态 import 相对运行时脚本",
+			scriptURL: "https://example.com/assets/index.js",
+			pageURL:   "https://example.com/",
+			content:   `const About = () => import("./About-2.js")`,
+			want:      []string{"https://example.com/assets/About-2.js"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := discoverChunks(tt.scriptURL, tt.pageURL, tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discoverChunks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,80 +5,100 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"JAttack/internal/db"
 	"JAttack/internal/pkg/logger"
-)
 
-// Pre-compiled regex rules for sensitive information
-var secretRules = []struct {
-	Name  string
-	Regex *regexp.Regexp
-}{
-	// Cloud Providers
-	{"Aliyun AccessKey", regexp.MustCompile(`\bLTAI[a-zA-Z0-9]{20}\b`)},
-	{"Aliyun Secret", regexp.MustCompile(`(?i)(?:aliyun|access_key|access_token).{0,20}['"]([0-9a-zA-Z]{30})['"]`)},
-	{"AWS AccessKey", regexp.MustCompile(`\b((?:AKIA|ABIA|ACCA|ASIA)[0-9A-Z]{16})\b`)},
-	{"AWS Secret", regexp.MustCompile(`(?i)aws.{0,20}['"]([0-9a-zA-Z\/+]{40})['"]`)},
-	{"Google API Key", regexp.MustCompile(`\bAIza[0-9A-Za-z\\-_]{35}\b`)},
-	{"Google OAuth", regexp.MustCompile(`[0-9]+-[0-9A-Za-z_]{32}\.apps\.googleusercontent\.com`)},
-	{"Tencent SecretId", regexp.MustCompile(`\bAKID[a-zA-Z0-9]{32}\b`)},
-	{"Tencent SecretKey", regexp.MustCompile(`\b(?i)SecretKey\s*[:=]\s*['"]([A-Za-z0-9]{32})['"]`)},
-	{"Azure SharedKey", regexp.MustCompile(`[a-z0-9]+\.blob\.core\.windows\.net`)},
-	{"Heroku API Key", regexp.MustCompile(`(?i)heroku.{0,20}[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)},
-
-	// SaaS & Tools
-	{"Slack Token", regexp.MustCompile(`xox[baprs]-[a-zA-Z0-9]{10,48}`)},
-	{"Slack Webhook", regexp.MustCompile(`https://hooks\.slack\.com/services/T[0-9A-Z]{9}/B[0-9A-Z]{9}/[a-zA-Z0-9]{24}`)},
-	{"GitHub Token", regexp.MustCompile(`(gh[pousr]_[a-zA-Z0-9]{36,255})`)},
-	{"Stripe Key", regexp.MustCompile(`(?:r|s)k_(?:live|test)_[0-9a-zA-Z]{24}`)},
-	{"PayPal Token", regexp.MustCompile(`access_token\$production\$[0-9a-z]{16}\$[0-9a-f]{32}`)},
-	{"Twilio SID", regexp.MustCompile(`\bAC[a-z0-9]{32}\b`)},
-	{"Mailgun API Key", regexp.MustCompile(`key-[0-9a-zA-Z]{32}`)},
-	{"Telegram Bot Token", regexp.MustCompile(`[0-9]+:AA[0-9A-Za-z\-_]{33}`)},
-	{"Facebook Access Token", regexp.MustCompile(`EAACEdEose0cBA[0-9A-Za-z]+`)},
-	{"Square Access Token", regexp.MustCompile(`sq0atp-[0-9A-Za-z\-_]{22}`)},
-	{"Square OAuth Secret", regexp.MustCompile(`sq0csp-[0-9A-Za-z\-_]{43}`)},
-
-	// Crypto
-	{"Private Key", regexp.MustCompile(`-----BEGIN ((EC|PGP|DSA|RSA|OPENSSH) )?PRIVATE KEY( BLOCK)?-----`)},
-
-	// Generic High Entropy
-	{"Generic API Key", regexp.MustCompile(`(?i)(?:api_?key|access_?token|secret|password|auth|auth_token)\s*[:=]\s*['"]([a-zA-Z0-9_\-]{16,64})['"]`)},
-	{"Bearer Token", regexp.MustCompile(`(?i)Bearer\s+[a-zA-Z0-9\-\._~\+/]+=*`)},
-	{"URI Credentials", regexp.MustCompile(`(?i)[a-z]+://[^/\s]+:[^/\s]+@[^/\s]+`)},
-	{"JWT", regexp.MustCompile(`eyJ[A-Za-z0-9-_]+\.[A-Za-z0-9-_]+\.[A-Za-z0-9-_]*`)},
-	{"Basic Auth", regexp.MustCompile(`(?i)Basic\s+[a-zA-Z0-9+/]+={0,2}`)},
-}
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
 
 type JSFinderService struct {
 	ctx       context.Context
 	client    *http.Client
 	dbManager *db.Manager
+
+	mu             sync.Mutex // protects findCancel and validateCancel
+	findCancel     context.CancelFunc
+	validateCancel context.CancelFunc
 }
 
 type JSFinderOptions struct {
 	DeepScan     bool `json:"deep_scan"`     // Recursively scan JS files
+	MaxDepth     int  `json:"max_depth"`     // Levels of nested JS followed by DeepScan, 0 means 2, negative means no limit
 	ActiveScan   bool `json:"active_scan"`   // Active request to verify/find more
 	DangerFilter bool `json:"danger_filter"` // Skip dangerous operations in active scan
 	Concurrency  int  `json:"concurrency"`   // Concurrency count
 	Timeout      int  `json:"timeout"`       // Timeout in milliseconds
+
+	Scripts        []string `json:"scripts"`          // Extra script URLs to analyze, e.g. found by the crawler
+	SkipWellKnown  bool     `json:"skip_well_known"`  // Don't use robots.txt / sitemap.xml entries as extra seeds
+	SkipSourceMaps bool     `json:"skip_source_maps"` // Don't look for and recover source maps of fetched scripts
+	SkipChunks     bool     `json:"skip_chunks"`      // Don't compute lazily loaded webpack / Vite chunks from runtime manifests
+
+	ContextSize     int                    `json:"context_size"`     // Bytes of surrounding code kept on each side of a sensitive finding, default 80
+	ValidateSecrets bool                   `json:"validate_secrets"` // Check found credentials live against their provider
+	Validation      SecretValidationConfig `json:"validation"`       // Validator endpoints and limits
+
+	Request HTTPRequestOptions `json:"request"` // Session used by the active scan, responses with and without it are compared
 }
 
 type JSFindResult struct {
-	URL           string   `json:"url"`
-	Endpoints     []string `json:"endpoints"`
-	JSFiles       []string `json:"js_files"`
-	SensitiveInfo []string `json:"sensitive_info"`
-	Error         string   `json:"error,omitempty"`
+	URL           string              `json:"url"`
+	Endpoints     []string            `json:"endpoints"`
+	JSFiles       []string            `json:"js_files"`
+	SensitiveInfo []string            `json:"sensitive_info"`
+	Secrets       []SecretFinding     `json:"secrets"`     // Structured sensitive info findings with severity and entropy
+	Params        []string            `json:"params"`      // Candidate parameter names, used by the parameter miner
+	APIs          []JSEndpoint        `json:"apis"`        // Structured endpoints with HTTP method and parameters
+	Probes        []EndpointProbe     `json:"probes"`      // Responses observed by the active scan
+	BasePath      string              `json:"base_path"`   // Verified API prefix applied to relative call endpoints
+	BasePaths     []BasePathCandidate `json:"base_paths"`  // Prefixes declared in the JS and tried by the active scan
+	SourceMaps    []string            `json:"source_maps"` // Recovered source map URLs
+	SourceDir     string              `json:"source_dir"`  // Directory holding the reconstructed source tree
+	Error         string              `json:"error,omitempty"`
+	Stopped       bool                `json:"stopped,omitempty"` // The run was stopped before finishing, results are partial
+
+	wellKnown *WellKnownResult // robots.txt / sitemap.xml harvest, saved as web URLs
+}
+
+// EndpointProbe is the response observed when the active scan requests a discovered endpoint
+type EndpointProbe struct {
+	URL         string `json:"url"`
+	Method      string `json:"method"` // Method inferred from the JS call site, empty when unknown
+	Probe       string `json:"probe"`  // Method actually sent: GET, or OPTIONS for state-changing endpoints
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Kind        string `json:"kind"` // Response fingerprint: json-data, json-error, error-stack, login, html, xml, text, empty
+	Length      int    `json:"length"`
+	Hash        string `json:"hash"`
+	Access      string `json:"access"`      // unauthenticated, different, auth-required or open, empty when undetermined
+	AnonStatus  int    `json:"anon_status"` // Status without the session, 0 when no session was supplied
+	Allow       string `json:"allow"`       // Allow header returned to OPTIONS
+}
+
+// JSFinderProgress is emitted as "jsfinder:progress" after each script is analyzed
+type JSFinderProgress struct {
+	Level   int    `json:"level"`
+	Done    int    `json:"done"`
+	Total   int    `json:"total"` // Scripts queued so far, grows as nested scripts and chunks are found
+	Current string `json:"current"`
+}
+
+// contentFindings is what analyzeContent extracts from a single page or script
+type contentFindings struct {
+	Source        string // Page, script or source map file the findings come from
+	Endpoints     []string
+	JSFiles       []string
+	SensitiveInfo []string
+	Secrets       []SecretFinding
+	Params        []string
+	APIs          []JSEndpoint
 }
 
 func NewJSFinderService(dbManager *db.Manager) *JSFinderService {
@@ -97,12 +117,63 @@ func (s *JSFinderService) Startup(ctx context.Context) {
 	s.ctx = ctx
 }
 
+// FindJS runs JSFinder and blocks until it finishes
 func (s *JSFinderService) FindJS(targetURL string, options JSFinderOptions) JSFindResult {
+	return s.findJS(context.Background(), targetURL, options, false)
+}
+
+// StartFindJS runs JSFinder in the background. Progress is reported through the
+// "jsfinder:log", "jsfinder:progress", "jsfinder:endpoint" and "jsfinder:secret" events,
+// the final JSFindResult through "jsfinder:complete".
+func (s *JSFinderService) StartFindJS(targetURL string, options JSFinderOptions) error {
+	if strings.TrimSpace(targetURL) == "" {
+		return fmt.Errorf("请提供目标地址")
+	}
+
+	s.mu.Lock()
+	if s.findCancel != nil {
+		s.findCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.findCancel = cancel
+	s.mu.Unlock()
+
+	go func() {
+		result := s.findJS(ctx, strings.TrimSpace(targetURL), options, true)
+		if s.ctx != nil {
+			runtime.EventsEmit(s.ctx, "jsfinder:complete", result)
+		}
+	}()
+	return nil
+}
+
+// StopFindJS stops the running background task, results found so far are kept
+func (s *JSFinderService) StopFindJS() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.findCancel != nil {
+		s.findCancel()
+		s.findCancel = nil
+		logger.Info("JSFinder task stopped")
+	}
+}
+
+func (s *JSFinderService) findJS(ctx context.Context, targetURL string, options JSFinderOptions, events bool) JSFindResult {
+	emit := func(name string, data interface{}) {
+		if events && s.ctx != nil {
+			runtime.EventsEmit(s.ctx, "jsfinder:"+name, data)
+		}
+	}
+
 	result := JSFindResult{
 		URL:           targetURL,
 		Endpoints:     []string{},
 		JSFiles:       []string{},
 		SensitiveInfo: []string{},
+		Secrets:       []SecretFinding{},
+		Params:        []string{},
+		APIs:          []JSEndpoint{},
+		SourceMaps:    []string{},
 	}
 
 	if !strings.HasPrefix(targetURL, "http") {
@@ -125,17 +196,59 @@ func (s *JSFinderService) FindJS(targetURL string, options JSFinderOptions) JSFi
 	var mu sync.Mutex
 	visited := make(map[string]bool)
 	jsQueue := make([]string, 0)
+	seenEndpoints := make(map[string]bool)
+	seenSecrets := make(map[string]bool)
 
 	// Helper to add data
-	addData := func(eps []string, js []string, info []string) {
+	addData := func(f contentFindings) {
 		mu.Lock()
 		defer mu.Unlock()
-		result.Endpoints = append(result.Endpoints, eps...)
-		result.JSFiles = append(result.JSFiles, js...)
-		result.SensitiveInfo = append(result.SensitiveInfo, info...)
+		for i := range f.APIs {
+			if f.APIs[i].Source == "" {
+				f.APIs[i].Source = f.Source
+			}
+		}
+		for i := range f.Secrets {
+			if f.Secrets[i].Source == "" {
+				f.Secrets[i].Source = f.Source
+			}
+		}
+
+		// Stream new endpoints and findings to the UI
+		if events {
+			apis := make(map[string]JSEndpoint)
+			for _, api := range f.APIs {
+				apis[api.URL] = api
+			}
+			for _, e := range f.Endpoints {
+				if seenEndpoints[e] {
+					continue
+				}
+				seenEndpoints[e] = true
+				api, ok := apis[e]
+				if !ok {
+					api = JSEndpoint{URL: e, Kind: "string", Source: f.Source}
+				}
+				emit("endpoint", api)
+			}
+			for _, secret := range f.Secrets {
+				key := secret.Source + "\x00" + secret.RuleID + "\x00" + secret.Value
+				if !seenSecrets[key] {
+					seenSecrets[key] = true
+					emit("secret", secret)
+				}
+			}
+		}
+
+		result.Endpoints = append(result.Endpoints, f.Endpoints...)
+		result.JSFiles = append(result.JSFiles, f.JSFiles...)
+		result.SensitiveInfo = append(result.SensitiveInfo, f.SensitiveInfo...)
+		result.Secrets = append(result.Secrets, f.Secrets...)
+		result.Params = append(result.Params, f.Params...)
+		result.APIs = append(result.APIs, f.APIs...)
 
 		// Add new JS to queue if not visited
-		for _, j := range js {
+		for _, j := range f.JSFiles {
 			// Normalize JS URL
 			fullJS, err := resolveURL(targetURL, j) // simplified resolution context
 			if err == nil && !visited[fullJS] {
@@ -148,16 +261,31 @@ func (s *JSFinderService) FindJS(targetURL string, options JSFinderOptions) JSFi
 	}
 
 	// 1. Fetch Main Page
-	body, err := s.fetch(targetURL, timeout)
+	emit("log", "开始分析 "+targetURL)
+	body, err := s.fetch(ctx, targetURL, timeout)
 	if err != nil {
 		result.Error = fmt.Sprintf("Failed to fetch target: %v", err)
+		emit("log", result.Error)
 		return result
 	}
 	visited[targetURL] = true
 
 	// Analyze Main Page
-	eps, jsLinks, info := s.analyzeContent(body)
-	addData(eps, jsLinks, info)
+	page := s.analyzeContent(body, options.ContextSize)
+	page.JSFiles = append(page.JSFiles, options.Scripts...)
+	if !options.SkipChunks {
+		// The webpack runtime is often inlined into index.html
+		page.JSFiles = append(page.JSFiles, discoverChunks(targetURL, targetURL, body)...)
+	}
+	if !options.SkipWellKnown {
+		// Only the scripts are analyzed; the other robots / sitemap URLs are page URLs, not JS
+		// endpoints, and are stored as web URLs instead of being active-scanned
+		result.wellKnown = HarvestWellKnown(ctx, targetURL, nil, timeout)
+		page.JSFiles = append(page.JSFiles, result.wellKnown.Scripts()...)
+	}
+	page.Source = targetURL
+	addData(page)
+	jsLinks := page.JSFiles
 
 	// Prepare queue for JS files
 	// We use a simple map to avoid duplicates in queue
@@ -184,54 +312,87 @@ func (s *JSFinderService) FindJS(targetURL string, options JSFinderOptions) JSFi
 	// If DeepScan is enabled, we might want to do 2 levels.
 	// Level 1: JS found on HTML.
 	// Level 2: JS found in Level 1 JS.
+	// Lazily loaded chunks computed from webpack / Vite runtimes are always queued for the next level,
+	// so the loop runs until no new chunk turns up.
 
 	levels := 1
 	if options.DeepScan {
 		levels = 2
+		if options.MaxDepth != 0 {
+			levels = options.MaxDepth
+		}
 	}
 
 	currentLevelQueue := jsQueue
+	progress := JSFinderProgress{Total: len(jsQueue)}
 
-	for l := 0; l < levels; l++ {
+	for l := 0; len(currentLevelQueue) > 0 && ctx.Err() == nil; l++ {
 		nextLevelQueue := []string{}
 		var nlMu sync.Mutex
 
 		logger.Info("Processing JS Level", "level", l+1, "count", len(currentLevelQueue))
+		emit("log", fmt.Sprintf("第 %d 层: %d 个脚本", l+1, len(currentLevelQueue)))
 
 		for _, jsURL := range currentLevelQueue {
+			if ctx.Err() != nil {
+				break
+			}
 			wg.Add(1)
 			sem <- struct{}{}
 			go func(urlStr string) {
 				defer wg.Done()
 				defer func() { <-sem }()
+				defer func() {
+					mu.Lock()
+					progress.Level = l + 1
+					progress.Done++
+					progress.Current = urlStr
+					p := progress
+					mu.Unlock()
+					emit("progress", p)
+				}()
 
 				// Fetch
-				content, err := s.fetch(urlStr, timeout)
+				content, err := s.fetch(ctx, urlStr, timeout)
 				if err != nil {
 					return
 				}
 
 				// Analyze
-				e, j, i := s.analyzeContent(content)
+				f := s.analyzeContent(content, options.ContextSize)
 
 				// Mark info source
-				for idx := range i {
-					i[idx] = fmt.Sprintf("[%s] %s", urlStr, i[idx])
+				for idx := range f.SensitiveInfo {
+					f.SensitiveInfo[idx] = fmt.Sprintf("[%s] %s", urlStr, f.SensitiveInfo[idx])
+				}
+				f.Source = urlStr
+
+				addData(f)
+
+				// Recover original sources from the source map and analyze them as well
+				if !options.SkipSourceMaps {
+					s.analyzeSourceMap(ctx, urlStr, content, timeout, options.ContextSize, &result, &mu, addData)
 				}
 
-				addData(e, j, i)
+				// Compute lazily loaded chunks, their URLs never appear as string literals
+				var chunks []string
+				if !options.SkipChunks {
+					chunks = discoverChunks(urlStr, targetURL, content)
+					addData(contentFindings{JSFiles: chunks})
+				}
 
 				// If DeepScan, collect JS for next level
-				if options.DeepScan && l < levels-1 {
-					nlMu.Lock()
-					for _, newJS := range j {
+				nlMu.Lock()
+				nextLevelQueue = append(nextLevelQueue, chunks...)
+				if options.DeepScan && (levels < 0 || l < levels-1) {
+					for _, newJS := range f.JSFiles {
 						full, err := resolveURL(urlStr, newJS)
 						if err == nil {
 							nextLevelQueue = append(nextLevelQueue, full)
 						}
 					}
-					nlMu.Unlock()
 				}
+				nlMu.Unlock()
 
 				// If ActiveScan, try to poke endpoints?
 				// API Sword does active requests.
@@ -241,36 +402,81 @@ func (s *JSFinderService) FindJS(targetURL string, options JSFinderOptions) JSFi
 		wg.Wait()
 
 		// Filter duplicates for next level
-		if options.DeepScan {
-			uniqueNext := []string{}
-			for _, u := range nextLevelQueue {
-				if !queued[u] {
-					queued[u] = true
-					uniqueNext = append(uniqueNext, u)
-				}
+		uniqueNext := []string{}
+		for _, u := range nextLevelQueue {
+			if !queued[u] {
+				queued[u] = true
+				uniqueNext = append(uniqueNext, u)
 			}
-			currentLevelQueue = uniqueNext
 		}
+		currentLevelQueue = uniqueNext
+		mu.Lock()
+		progress.Total += len(uniqueNext)
+		mu.Unlock()
+	}
+	if ctx.Err() != nil {
+		result.Stopped = true
+		emit("log", "任务已停止，保存已发现的结果")
 	}
 
 	// 3. Deduplicate results
 	result.Endpoints = unique(result.Endpoints)
 	result.JSFiles = unique(result.JSFiles)
 	result.SensitiveInfo = unique(result.SensitiveInfo)
+	result.Secrets = uniqueSecretFindings(result.Secrets)
+	result.Params = unique(result.Params)
+	result.APIs = uniqueJSEndpoints(result.APIs)
 
 	// 4. Active Scan (Verification)
-	if options.ActiveScan {
+	if options.ActiveScan && !result.Stopped {
 		logger.Info("Starting Active Scan on endpoints", "count", len(result.Endpoints))
-		activeResults := s.performActiveScan(targetURL, result.Endpoints, options.DangerFilter, concurrency, timeout)
-		// Merge active results (e.g., mark them or add to sensitive info if interesting)
-		// For now, let's just add found valid endpoints to sensitive info as "Verified API"
-		for _, r := range activeResults {
-			result.SensitiveInfo = append(result.SensitiveInfo, "Verified API: "+r)
+		emit("log", fmt.Sprintf("主动验证 %d 个端点", len(result.Endpoints)))
+		prober := s.newEndpointProber(ctx, &options.Request, timeout)
+		// Relative endpoints are usually requested through a client with a baseURL such as /prod-api
+		if candidates := basePathCandidates(targetURL, result.APIs); len(candidates) > 0 {
+			if samples := basePathSamples(result.APIs, candidates); len(samples) > 0 {
+				emit("log", fmt.Sprintf("推断接口前缀：%d 个候选，%d 个样本接口", len(candidates), len(samples)))
+				result.BasePaths = prober.inferBasePath(targetURL, candidates, samples, concurrency)
+				for _, c := range result.BasePaths {
+					if c.Selected {
+						result.BasePath = c.Path
+						applyBasePath(&result, c.Path)
+						emit("log", fmt.Sprintf("接口前缀 %s 命中 %d/%d", c.Path, c.Hits, c.Samples))
+					}
+				}
+			}
+		}
+		result.Probes = s.performActiveScan(prober, targetURL, result.Endpoints, result.APIs, options.DangerFilter, concurrency)
+		// Probes are stored with the API inventory, interesting ones are also listed for display
+		for _, p := range result.Probes {
+			if !p.interesting() {
+				continue
+			}
+			info := fmt.Sprintf("Verified API: [%d] %s %s %s", p.Status, p.Probe, p.URL, p.Kind)
+			if p.Access != "" {
+				info += " access=" + p.Access
+			}
+			if p.Allow != "" {
+				info += " allow=" + p.Allow
+			}
+			result.SensitiveInfo = append(result.SensitiveInfo, info)
 		}
 	}
 
-	// 5. Save Results to DB
+	// 5. Live validation of found credentials
+	if options.ValidateSecrets && len(result.Secrets) > 0 && ctx.Err() == nil {
+		logger.Info("Validating secrets", "count", len(result.Secrets))
+		emit("log", fmt.Sprintf("在线验证 %d 条敏感信息", len(result.Secrets)))
+		validations := validateSecrets(ctx, result.Secrets, options.Validation)
+		for i, v := range validations {
+			result.Secrets[i].Validation = v.Status
+			result.Secrets[i].ValidationDetail = v.Detail
+		}
+	}
+
+	// 6. Save Results to DB
 	s.saveResults(result)
+	emit("log", fmt.Sprintf("分析完成: %d 个端点, %d 个脚本, %d 条敏感信息", len(result.Endpoints), len(result.JSFiles), len(result.Secrets)))
 
 	return result
 }
@@ -280,98 +486,157 @@ func (s *JSFinderService) saveResults(result JSFindResult) {
 		return
 	}
 
-	// Parse Target URL
-	u, err := url.Parse(result.URL)
+	_, webServiceID, err := ensureWebService(s.dbManager, result.URL)
 	if err != nil {
-		logger.Error("Failed to parse target URL for saving", "url", result.URL, "error", err)
+		logger.Error("Failed to resolve web service for saving", "url", result.URL, "error", err)
 		return
 	}
 
-	host := u.Hostname()
-	portStr := u.Port()
-	if portStr == "" {
-		if u.Scheme == "https" {
-			portStr = "443"
-		} else {
-			portStr = "80"
-		}
+	// Save JS Files
+	for _, js := range result.JSFiles {
+		fullJS, _ := resolveURL(result.URL, js)
+		s.dbManager.AddWebJSFile(webServiceID, js, fullJS)
 	}
-	port, _ := strconv.Atoi(portStr)
 
-	// Resolve IP
-	ips, err := net.LookupHost(host)
-	if err != nil || len(ips) == 0 {
-		// Fallback: if host is IP, use it
-		if net.ParseIP(host) != nil {
-			ips = []string{host}
-		} else {
-			logger.Error("Failed to resolve host for saving", "host", host, "error", err)
-			return
+	// Save Sensitive Info
+	for _, f := range result.Secrets {
+		source := f.Source
+		if source == "" {
+			source = result.URL
+		}
+		s.dbManager.AddSensitiveResult(db.SensitiveResult{
+			WebServiceID:     webServiceID,
+			SourceFile:       source,
+			RuleID:           f.RuleID,
+			InfoType:         f.Name,
+			Content:          f.Value,
+			Context:          f.Context,
+			Offset:           f.Offset,
+			Line:             f.Line,
+			Column:           f.Column,
+			Severity:         f.Severity,
+			Entropy:          f.Entropy,
+			ValidationStatus: f.Validation,
+			ValidationDetail: f.ValidationDetail,
+		})
+	}
+
+	// Save candidate parameter names
+	for _, name := range result.Params {
+		s.dbManager.UpsertWebParam(webServiceID, result.URL, name, "js", "")
+	}
+
+	s.saveAPIEndpoints(webServiceID, result)
+	if result.wellKnown != nil {
+		recordWellKnown(s.dbManager, webServiceID, result.wellKnown)
+	}
+
+	// Save the prefixes tried, recording which one works for the service. The page-relative
+	// resolution without prefix is only the baseline they are compared against and is not stored.
+	var paths []db.APIBasePath
+	for _, c := range result.BasePaths {
+		if c.Path == "" {
+			continue
 		}
+		paths = append(paths, db.APIBasePath{BasePath: c.Path, SourceFile: c.Source, Hits: c.Hits, Samples: c.Samples, Verified: c.Selected})
 	}
-	ip := ips[0] // Use first IP
-
-	// Upsert Asset
-	assetID, err := s.dbManager.UpsertAsset(ip, "", true)
-	if err != nil {
-		logger.Error("Failed to upsert asset", "ip", ip, "error", err)
-		return
+	if len(paths) > 0 {
+		if err := s.dbManager.SaveAPIBasePaths(webServiceID, paths); err != nil {
+			logger.Warn("Failed to save API base paths", "url", result.URL, "error", err)
+		}
 	}
+}
 
-	// Upsert Port
-	portID, err := s.dbManager.UpsertAssetPort(assetID, port, "tcp", u.Scheme, "", "", "", "open")
+// saveAPIEndpoints adds the endpoints on the target's origin to the API inventory, together with
+// the response fingerprint observed by the active scan. Endpoints on other origins are left to their own scans.
+func (s *JSFinderService) saveAPIEndpoints(webServiceID int64, result JSFindResult) {
+	target, err := url.Parse(result.URL)
 	if err != nil {
-		logger.Error("Failed to upsert port", "port", port, "error", err)
 		return
 	}
 
-	// Upsert WebService
-	webServiceID, err := s.dbManager.UpsertWebService(assetID, portID, result.URL, "", "", "")
-	if err != nil {
-		logger.Error("Failed to upsert web service", "url", result.URL, "error", err)
-		return
+	var endpoints []db.APIEndpoint
+	byPath := make(map[string][]int)
+	byKey := make(map[string][]int)
+	covered := make(map[string]bool)
+	add := func(ref, method, sourceFile string, params []string) {
+		full, err := resolveURL(result.URL, ref)
+		if err != nil {
+			return
+		}
+		u, err := url.Parse(full)
+		if err != nil || u.Scheme != target.Scheme || u.Host != target.Host {
+			return
+		}
+		e, ok := newAPIEndpoint(webServiceID, full, method, "jsfinder")
+		if !ok {
+			return
+		}
+		e.Params = append(e.Params, params...)
+		e.SourceFile = sourceFile
+		byKey[e.Method+" "+e.Path] = append(byKey[e.Method+" "+e.Path], len(endpoints))
+		byPath[e.Path] = append(byPath[e.Path], len(endpoints))
+		endpoints = append(endpoints, e)
+	}
+
+	for _, api := range result.APIs {
+		covered[api.URL] = true
+		// Base URLs and front-end routes are not endpoints themselves
+		if api.Kind == "base" || api.Kind == "route" {
+			continue
+		}
+		add(api.URL, api.Method, api.Source, api.Params)
 	}
-
-	// Save JS Files
-	for _, js := range result.JSFiles {
-		fullJS, _ := resolveURL(result.URL, js)
-		s.dbManager.AddWebJSFile(webServiceID, js, fullJS)
+	for _, ep := range result.Endpoints {
+		if !covered[ep] {
+			add(ep, "", "", nil)
+		}
 	}
 
-	// Save Sensitive Info
-	for _, info := range result.SensitiveInfo {
-		// info format: "[source_url] Type: Value"
-		source := result.URL
-		content := info
-		infoType := "Sensitive Info"
-
-		if strings.HasPrefix(info, "[") {
-			end := strings.Index(info, "]")
-			if end > 1 {
-				source = info[1:end]
-				content = strings.TrimSpace(info[end+1:])
-			}
+	for _, p := range result.Probes {
+		u, err := url.Parse(p.URL)
+		if err != nil {
+			continue
 		}
-
-		parts := strings.SplitN(content, ":", 2)
-		if len(parts) == 2 {
-			infoType = strings.TrimSpace(parts[0])
-			content = strings.TrimSpace(parts[1])
+		// A probe belongs to the row with its inferred method, rows without a method take any probe of the path
+		rows := byKey[p.Method+" "+u.Path]
+		if len(rows) == 0 {
+			rows = byKey[" "+u.Path]
 		}
+		if len(rows) == 0 {
+			rows = byPath[u.Path]
+		}
+		for _, i := range rows {
+			e := &endpoints[i]
+			e.StatusCode = p.Status
+			e.ContentType = p.ContentType
+			e.ResponseKind = p.Kind
+			e.ResponseLength = p.Length
+			e.ResponseHash = p.Hash
+			e.Access = p.Access
+			e.AllowMethods = p.Allow
+			e.AuthRequired = p.Access == "auth-required"
+		}
+	}
 
-		s.dbManager.AddSensitiveResult(webServiceID, source, infoType, content, "")
+	for _, e := range endpoints {
+		recordAPIEndpoint(s.dbManager, e)
 	}
 }
 
-func (s *JSFinderService) fetch(urlStr string, timeout time.Duration) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+func (s *JSFinderService) fetch(ctx context.Context, urlStr string, timeout time.Duration) (string, error) {
+	return s.fetchLimit(ctx, urlStr, timeout, 5*1024*1024) // 5MB limit
+}
+
+func (s *JSFinderService) fetchLimit(ctx context.Context, urlStr string, timeout time.Duration, limit int64) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", urlStr, nil)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	req.Header.Set("User-Agent", defaultUserAgent)
 
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -380,14 +645,49 @@ func (s *JSFinderService) fetch(urlStr string, timeout time.Duration) (string, e
 	defer resp.Body.Close()
 
 	// Limit read to avoid huge files
-	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024)) // 5MB limit
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, limit))
 	if err != nil {
 		return "", err
 	}
 	return string(bodyBytes), nil
 }
 
-func (s *JSFinderService) analyzeContent(content string) ([]string, []string, []string) {
+// analyzeSourceMap recovers the original sources of a script, writes them into the project data dir
+// and feeds the application sources (not node_modules) back through analyzeContent
+func (s *JSFinderService) analyzeSourceMap(ctx context.Context, jsURL, content string, timeout time.Duration, contextSize int, result *JSFindResult, mu *sync.Mutex, addData func(contentFindings)) {
+	mapURL, sources := s.recoverSourceMap(ctx, jsURL, content, timeout)
+	if len(sources) == 0 {
+		return
+	}
+	logger.Info("Recovered source map", "map", mapURL, "sources", len(sources))
+
+	dir, err := s.saveSourceTree(jsURL, sources)
+	if err != nil {
+		logger.Warn("Failed to write recovered sources", "map", mapURL, "error", err)
+	}
+
+	mu.Lock()
+	result.SourceMaps = append(result.SourceMaps, mapURL)
+	if dir != "" {
+		result.SourceDir = dir
+	}
+	mu.Unlock()
+
+	for _, src := range sources {
+		if isThirdPartySource(src.Path) {
+			continue
+		}
+		f := s.analyzeContent(src.Content, contextSize)
+		for idx := range f.SensitiveInfo {
+			f.SensitiveInfo[idx] = fmt.Sprintf("[%s#%s] %s", mapURL, src.Path, f.SensitiveInfo[idx])
+		}
+		f.Source = mapURL + "#" + src.Path
+		f.JSFiles = nil
+		addData(f)
+	}
+}
+
+func (s *JSFinderService) analyzeContent(content string, contextSize int) contentFindings {
 	var endpoints []string
 	var jsFiles []string
 	var sensitiveInfo []string
@@ -412,119 +712,75 @@ func (s *JSFinderService) analyzeContent(content string) ([]string, []string, []
 		}
 	}
 
-	// 2. Sensitive Info - Rule Based
-	for _, rule := range secretRules {
-		// If regex has submatches, we prefer the first group (actual value without quotes/keys)
-		if rule.Regex.NumSubexp() > 0 {
-			matches := rule.Regex.FindAllStringSubmatch(content, -1)
-			for _, m := range matches {
-				if len(m) > 1 {
-					val := m[1]
-					if len(val) > 100 {
-						val = val[:100] + "..."
-					}
-					sensitiveInfo = append(sensitiveInfo, fmt.Sprintf("%s: %s", rule.Name, val))
-				}
-			}
-		} else {
-			matches := rule.Regex.FindAllString(content, -1)
-			for _, m := range matches {
-				val := m
-				if len(val) > 100 {
-					val = val[:100] + "..."
+	// Scripts that parse are analyzed on the syntax tree instead: concatenations, templates and
+	// call arguments are evaluated and the quoted-string noise is dropped. HTML and sources the
+	// parser rejects keep the regex results.
+	apis, err := extractJSEndpoints(content)
+	if err == nil {
+		endpoints = endpoints[:0]
+		for _, api := range apis {
+			if strings.HasSuffix(strings.SplitN(api.URL, "?", 2)[0], ".js") {
+				if !strings.Contains(api.URL, "{") {
+					jsFiles = append(jsFiles, api.URL)
 				}
-				sensitiveInfo = append(sensitiveInfo, fmt.Sprintf("%s: %s", rule.Name, val))
+				continue
 			}
+			endpoints = append(endpoints, api.URL)
 		}
+	} else {
+		apis = extractBaseConfigs(content)
 	}
 
-	// 3. PII & Other Info
-	// IP
-	ipRegex := regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
-	for _, ip := range ipRegex.FindAllString(content, -1) {
-		if !strings.HasPrefix(ip, "0.") && !strings.HasPrefix(ip, "127.") && !strings.HasPrefix(ip, "255.") {
-			sensitiveInfo = append(sensitiveInfo, "IP: "+ip)
-		}
+	// 2. Sensitive Info - user editable rules, see secret_rules.yaml
+	secrets := scanSecrets(content, activeSecretRules(), contextSize)
+	for _, f := range secrets {
+		sensitiveInfo = append(sensitiveInfo, f.String())
 	}
 
-	// Phone & Email
-	phoneRegex := regexp.MustCompile(`\b1[3-9]\d{9}\b`)
-	for _, p := range phoneRegex.FindAllString(content, -1) {
-		sensitiveInfo = append(sensitiveInfo, "Phone: "+p)
+	// 3. Candidate parameter names
+	params := extractParamNames(content)
+	for _, api := range apis {
+		params = append(params, api.Params...)
 	}
 
-	emailRegex := regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
-	for _, e := range emailRegex.FindAllString(content, -1) {
-		if !strings.HasSuffix(e, ".png") && !strings.HasSuffix(e, ".jpg") && !strings.HasSuffix(e, ".svg") {
-			sensitiveInfo = append(sensitiveInfo, "Email: "+e)
-		}
+	return contentFindings{
+		Endpoints:     endpoints,
+		JSFiles:       jsFiles,
+		SensitiveInfo: sensitiveInfo,
+		Secrets:       secrets,
+		Params:        params,
+		APIs:          apis,
 	}
-
-	return endpoints, jsFiles, sensitiveInfo
 }
 
-func (s *JSFinderService) performActiveScan(baseURL string, endpoints []string, dangerFilter bool, concurrency int, timeout time.Duration) []string {
-	var valid []string
-	var wg sync.WaitGroup
-	sem := make(chan struct{}, concurrency)
-	var mu sync.Mutex
+// Patterns that reveal parameter names in JS / HTML
+var paramNameRules = []*regexp.Regexp{
+	// Query strings: "/api/list?page=1&size=10"
+	regexp.MustCompile(`[?&]([a-zA-Z_][a-zA-Z0-9_.\-]{0,39})=`),
+	// URLSearchParams / FormData / Map access: params.append("id", ...), query.get('token')
+	regexp.MustCompile(`\.(?:get|getAll|set|append|has)\(\s*['"]([a-zA-Z_][a-zA-Z0-9_.\-]{0,39})['"]`),
+	// HTML inputs: <input name="username">
+	regexp.MustCompile(`(?i)\bname\s*=\s*['"]([a-zA-Z_][a-zA-Z0-9_.\-\[\]]{0,39})['"]`),
+}
 
-	base, _ := url.Parse(baseURL)
+// Request option objects whose keys are parameter names: axios.get(url, {params: {id: 1}}), $.ajax({data: {...}})
+var paramObjectRegex = regexp.MustCompile(`\b(?:params|data|query|body|form)\s*:\s*\{([^{}]{1,500})\}`)
+var paramObjectKeyRegex = regexp.MustCompile(`(?:^|[,{\s])['"]?([a-zA-Z_$][a-zA-Z0-9_$]{0,39})['"]?\s*:`)
 
-	for _, ep := range endpoints {
-		// Filter dangerous
-		if dangerFilter {
-			lower := strings.ToLower(ep)
-			if strings.Contains(lower, "del") || strings.Contains(lower, "remove") ||
-				strings.Contains(lower, "logout") || strings.Contains(lower, "exit") {
-				continue
-			}
+// extractParamNames collects candidate parameter names from JS / HTML source
+func extractParamNames(content string) []string {
+	var names []string
+	for _, rule := range paramNameRules {
+		for _, m := range rule.FindAllStringSubmatch(content, -1) {
+			names = append(names, m[1])
+		}
+	}
+	for _, obj := range paramObjectRegex.FindAllStringSubmatch(content, -1) {
+		for _, m := range paramObjectKeyRegex.FindAllStringSubmatch(obj[1], -1) {
+			names = append(names, m[1])
 		}
-
-		// Construct URL
-		target := ep
-		if !strings.HasPrefix(ep, "http") {
-			u, err := url.Parse(ep)
-			if err != nil {
-				continue
-			}
-			target = base.ResolveReference(u).String()
-		}
-
-		wg.Add(1)
-		sem <- struct{}{}
-		go func(t string) {
-			defer wg.Done()
-			defer func() { <-sem }()
-
-			// Try HEAD first
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
-			defer cancel()
-			req, _ := http.NewRequestWithContext(ctx, "HEAD", t, nil)
-			req.Header.Set("User-Agent", "Mozilla/5.0")
-			resp, err := s.client.Do(req)
-
-			// If Method Not Allowed, try GET
-			if err == nil && resp.StatusCode == 405 {
-				ctx2, cancel2 := context.WithTimeout(context.Background(), timeout)
-				defer cancel2()
-				req, _ = http.NewRequestWithContext(ctx2, "GET", t, nil)
-				resp, err = s.client.Do(req)
-			}
-
-			if err == nil {
-				defer resp.Body.Close()
-				// 200 OK, 401 Unauthorized, 403 Forbidden are interesting
-				if resp.StatusCode == 200 || resp.StatusCode == 401 || resp.StatusCode == 403 || resp.StatusCode == 500 {
-					mu.Lock()
-					valid = append(valid, fmt.Sprintf("[%d] %s", resp.StatusCode, t))
-					mu.Unlock()
-				}
-			}
-		}(target)
 	}
-	wg.Wait()
-	return valid
+	return unique(names)
 }
 
 func isValidEndpoint(s string) bool {
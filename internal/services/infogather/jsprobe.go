@@ -0,0 +1,400 @@
+package infogather
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// maxProbeBody is how much of a probe response is read for fingerprinting
+const maxProbeBody = 512 * 1024
+
+// dangerWords are path tokens of endpoints that change state even when requested with GET
+var dangerWords = map[string]bool{
+	"del": true, "delete": true, "remove": true, "destroy": true, "drop": true,
+	"logout": true, "signout": true, "exit": true, "reset": true, "clear": true,
+	"purge": true, "truncate": true, "kill": true, "shutdown": true, "restart": true,
+	"disable": true, "uninstall": true, "unsubscribe": true, "cancel": true,
+}
+
+var (
+	// loginLocationRegex matches redirect targets of login pages and SSO providers
+	loginLocationRegex = regexp.MustCompile(`(?i)log-?in|sign-?in|logon|/auth|/sso|/cas/|passport|oauth`)
+	// passwordFieldRegex matches a password input, i.e. an HTML login form
+	passwordFieldRegex = regexp.MustCompile(`(?i)<input[^>]+type\s*=\s*["']?password`)
+	// stackTraceRegex matches stack traces and framework error pages
+	stackTraceRegex = regexp.MustCompile(`(?m)^\s*at [\w$.<>]+\(.*(?:\.java|\.js|\.ts|\.cs|Native Method|Unknown Source).*\)|Traceback \(most recent call last\)|Exception in thread|java\.lang\.\w+(?:Exception|Error)|System\.\w+Exception|Stack trace:|Fatal error:|goroutine \d+ \[|Whitelabel Error Page|Whoops, looks like something went wrong|\.php on line \d+`)
+)
+
+// probeTarget is one request planned by the active scan
+type probeTarget struct {
+	url       string // Resolved URL, path placeholders kept
+	method    string // Method inferred from the call site, empty when unknown
+	dangerous bool
+}
+
+// probeResponse is the fingerprint of a single response
+type probeResponse struct {
+	status      int
+	contentType string
+	allow       string
+	kind        string
+	length      int
+	hash        string
+}
+
+// isDangerousEndpoint reports whether a path contains a state-changing word. The path is split on
+// non-alphanumerics and camelCase so "deleteUser" and "user_del" match while "model" does not.
+func isDangerousEndpoint(path string) bool {
+	var token []rune
+	flush := func() bool {
+		word := strings.ToLower(string(token))
+		token = token[:0]
+		return dangerWords[word]
+	}
+	runes := []rune(path)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if flush() {
+				return true
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(token) > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			if flush() {
+				return true
+			}
+		}
+		token = append(token, r)
+	}
+	return flush()
+}
+
+// hasSession reports whether the request options carry credentials worth comparing against
+func hasSession(o *HTTPRequestOptions) bool {
+	if o == nil {
+		return false
+	}
+	return strings.TrimSpace(o.Cookies) != "" || o.AuthType != "" || len(o.Headers) > 0
+}
+
+// classifyResponse fingerprints a response as json-data, json-error, error-stack, login, html, xml, text or empty
+func classifyResponse(status int, header http.Header, body []byte) string {
+	if status >= 300 && status < 400 && loginLocationRegex.MatchString(header.Get("Location")) {
+		return "login"
+	}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return "empty"
+	}
+	if stackTraceRegex.Match(trimmed) {
+		return "error-stack"
+	}
+
+	ct := strings.ToLower(header.Get("Content-Type"))
+	if (strings.Contains(ct, "json") || trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed) {
+		if status >= 400 || isJSONError(trimmed) {
+			return "json-error"
+		}
+		return "json-data"
+	}
+	lower := strings.ToLower(string(trimmed[:min(len(trimmed), 512)]))
+	if strings.Contains(ct, "html") || strings.HasPrefix(lower, "<!doctype html") || strings.Contains(lower, "<html") {
+		if passwordFieldRegex.Match(trimmed) {
+			return "login"
+		}
+		return "html"
+	}
+	if strings.Contains(ct, "xml") || strings.HasPrefix(lower, "<?xml") {
+		return "xml"
+	}
+	return "text"
+}
+
+// isJSONError reports whether a JSON object is an error envelope rather than data,
+// e.g. {"code":401,"msg":"token invalid"} or {"success":false,"error":"..."}
+func isJSONError(body []byte) bool {
+	var obj map[string]any
+	if json.Unmarshal(body, &obj) != nil {
+		return false
+	}
+	for _, k := range []string{"data", "result", "items", "list", "records", "rows", "content"} {
+		if v, ok := obj[k]; ok && v != nil {
+			return false
+		}
+	}
+	for _, k := range []string{"error", "errors", "errmsg", "exception"} {
+		if v, ok := obj[k]; ok && v != nil && v != "" {
+			return true
+		}
+	}
+	if success, ok := obj["success"].(bool); ok && !success {
+		return true
+	}
+	// RuoYi-style APIs answer HTTP 200 on auth failure and report the error in code / status with a msg / message
+	hasMessage := false
+	for _, k := range []string{"msg", "message"} {
+		if v, ok := obj[k].(string); ok && v != "" {
+			hasMessage = true
+		}
+	}
+	if hasMessage {
+		for _, k := range []string{"code", "status", "errcode"} {
+			if v, ok := obj[k]; ok && !isSuccessCode(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSuccessCode reports whether a code / status value of a JSON envelope means success
+func isSuccessCode(v any) bool {
+	switch c := v.(type) {
+	case float64:
+		return c == 0 || c == 200
+	case string:
+		switch strings.ToLower(c) {
+		case "0", "200", "ok", "success", "succeed":
+			return true
+		}
+		return false
+	case bool:
+		return c
+	}
+	return true
+}
+
+// similarResponses reports whether two responses carry the same content: identical bodies,
+// or the same fingerprint with lengths within 5% (timestamps, CSRF tokens and the like differ)
+func similarResponses(a, b probeResponse) bool {
+	if a.status != b.status || a.kind != b.kind {
+		return false
+	}
+	if a.hash == b.hash {
+		return true
+	}
+	diff := a.length - b.length
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*20 <= max(a.length, b.length)
+}
+
+// planProbes resolves the endpoints against the target and attaches the methods inferred from JS call
+// sites. Only same-origin endpoints are kept so the supplied session is never sent to third parties.
+func planProbes(baseURL string, endpoints []string, apis []JSEndpoint, dangerFilter bool) []probeTarget {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	methods := make(map[string][]string)
+	for _, api := range apis {
+		if api.Kind == "call" && api.Method != "" {
+			methods[api.URL] = append(methods[api.URL], strings.ToUpper(api.Method))
+		}
+	}
+
+	var targets []probeTarget
+	seen := make(map[string]bool)
+	for _, ep := range endpoints {
+		full, err := resolveURL(baseURL, ep)
+		if err != nil {
+			continue
+		}
+		u, err := url.Parse(full)
+		if err != nil || u.Scheme != base.Scheme || u.Host != base.Host {
+			continue
+		}
+		dangerous := dangerFilter && isDangerousEndpoint(u.Path)
+		inferred := unique(methods[ep])
+		if len(inferred) == 0 {
+			inferred = []string{""}
+		}
+		for _, m := range inferred {
+			key := m + " " + full
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			targets = append(targets, probeTarget{url: full, method: m, dangerous: dangerous})
+		}
+	}
+	return targets
+}
+
+// endpointProber sends the active scan requests. Redirects are not followed so login redirects can be
+// recognized; the session is only applied on request because responses without it are compared.
+type endpointProber struct {
+	ctx         context.Context
+	client      *http.Client
+	session     *HTTPRequestOptions
+	withSession bool
+	timeout     time.Duration
+}
+
+func (s *JSFinderService) newEndpointProber(ctx context.Context, session *HTTPRequestOptions, timeout time.Duration) *endpointProber {
+	if session == nil {
+		session = &HTTPRequestOptions{}
+	}
+	return &endpointProber{
+		ctx: ctx,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: s.client.Transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		session:     session,
+		withSession: hasSession(session),
+		timeout:     timeout,
+	}
+}
+
+func (p *endpointProber) send(method, target string, auth bool) (probeResponse, error) {
+	ctx, cancel := context.WithTimeout(p.ctx, p.timeout)
+	defer cancel()
+	// Unresolved placeholders are filled so the route still matches
+	reqURL := pathParamRegex.ReplaceAllString(strings.NewReplacer("%7B", "{", "%7D", "}").Replace(target), "1")
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, nil)
+	if err != nil {
+		return probeResponse{}, err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if auth {
+		p.session.applyTo(req)
+	} else if p.session.UserAgent != "" {
+		req.Header.Set("User-Agent", p.session.UserAgent)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return probeResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxProbeBody))
+	return probeResponse{
+		status:      resp.StatusCode,
+		contentType: resp.Header.Get("Content-Type"),
+		allow:       resp.Header.Get("Allow"),
+		kind:        classifyResponse(resp.StatusCode, resp.Header, body),
+		length:      len(body),
+		hash:        contentHash(body),
+	}, nil
+}
+
+// performActiveScan verifies the discovered endpoints without changing server state: endpoints whose
+// call site uses GET (or is unknown) are requested with GET, state-changing or dangerous ones only with
+// OPTIONS. When a session is supplied every GET is repeated without it to find endpoints that don't
+// check authentication.
+func (s *JSFinderService) performActiveScan(p *endpointProber, baseURL string, endpoints []string, apis []JSEndpoint, dangerFilter bool, concurrency int) []EndpointProbe {
+	targets := planProbes(baseURL, endpoints, apis, dangerFilter)
+	if len(targets) == 0 {
+		return nil
+	}
+	send, withSession := p.send, p.withSession
+
+	var probes []EndpointProbe
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	sem := make(chan struct{}, concurrency)
+
+	for _, t := range targets {
+		if p.ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t probeTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			probe := EndpointProbe{URL: t.url, Method: t.method}
+			safe := t.method == "" || t.method == "GET" || t.method == "HEAD"
+			if !safe || t.dangerous {
+				// Only ask which methods are allowed
+				r, err := send("OPTIONS", t.url, withSession)
+				if err != nil {
+					return
+				}
+				probe.Probe = "OPTIONS"
+				probe.fill(r)
+				probe.Allow = r.allow
+			} else {
+				r, err := send("GET", t.url, withSession)
+				if err != nil {
+					return
+				}
+				probe.Probe = "GET"
+				probe.fill(r)
+				if withSession {
+					anon, err := send("GET", t.url, false)
+					if err == nil {
+						probe.AnonStatus = anon.status
+						probe.Access = compareAccess(r, anon)
+					}
+				} else {
+					probe.Access = anonymousAccess(r)
+				}
+			}
+
+			mu.Lock()
+			probes = append(probes, probe)
+			mu.Unlock()
+		}(t)
+	}
+	wg.Wait()
+	return probes
+}
+
+func (p *EndpointProbe) fill(r probeResponse) {
+	p.Status = r.status
+	p.ContentType = r.contentType
+	p.Kind = r.kind
+	p.Length = r.length
+	p.Hash = r.hash
+}
+
+// compareAccess compares the responses with and without the session: the same content without
+// credentials means the endpoint doesn't check authentication
+func compareAccess(auth, anon probeResponse) string {
+	if anon.status >= 200 && anon.status < 300 && anon.kind != "login" {
+		if similarResponses(auth, anon) {
+			return "unauthenticated"
+		}
+		return "different"
+	}
+	return anonymousAccess(anon)
+}
+
+// anonymousAccess judges a response obtained without credentials
+func anonymousAccess(r probeResponse) string {
+	switch {
+	case r.status == 401 || r.status == 403 || r.kind == "login":
+		return "auth-required"
+	case r.status >= 200 && r.status < 300:
+		return "open"
+	}
+	return ""
+}
+
+// interesting reports whether a probe is worth listing with the sensitive info
+func (p EndpointProbe) interesting() bool {
+	switch {
+	case p.Kind == "error-stack":
+		return true
+	case p.Access == "unauthenticated" || p.Access == "open":
+		return p.Kind == "json-data" || p.Kind == "xml"
+	case p.Probe == "OPTIONS":
+		return p.Allow != ""
+	}
+	return false
+}
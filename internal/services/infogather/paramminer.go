@@ -0,0 +1,600 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// ParamMinerConfig 隐藏参数挖掘配置
+type ParamMinerConfig struct {
+	Targets   []string `json:"targets"`    // 待挖掘的端点，例如 JSFinder 发现的接口
+	Method    string   `json:"method"`     // query 位置使用的请求方法，默认 GET；form / json 位置为 GET 时改用 POST
+	Locations []string `json:"locations"`  // "query"（默认）、"form"、"json"
+	Wordlist  string   `json:"wordlist"`   // 参数名字典文件
+	Words     []string `json:"words"`      // 直接提供的参数名，与字典合并
+	UseKnown  bool     `json:"use_known"`  // 加入 JSFinder 收集及历史挖掘得到的参数名
+	NoBuiltin bool     `json:"no_builtin"` // 不使用内置的常见参数名
+	ChunkSize int      `json:"chunk_size"` // 每个请求携带的参数数量，默认 128，目标拒绝时自动减半
+	Threads   int      `json:"threads"`    // 并发请求的参数分组数，默认 5
+	Timeout   int      `json:"timeout"`    // 单次请求超时（毫秒），默认 10000
+
+	Request HTTPRequestOptions `json:"request"` // 请求头、Cookie 与认证，Methods 与 Body 不生效
+}
+
+// MinedParam 确认存在的参数，随 paramMiner:param 事件推送
+type MinedParam struct {
+	URL      string `json:"url"`
+	Method   string `json:"method"`
+	Location string `json:"location"`
+	Name     string `json:"name"`
+	Reason   string `json:"reason"` // 例如 "reflected"、"status 200 -> 500"
+}
+
+// ParamMinerProgress 挖掘进度，随 paramMiner:progress 事件推送
+type ParamMinerProgress struct {
+	URL      string `json:"url"`
+	Location string `json:"location"`
+	Tested   int64  `json:"tested"` // 已完成分组中的参数数量
+	Total    int64  `json:"total"`
+	Requests int64  `json:"requests"`
+}
+
+// ParamMinerResult 单个端点的挖掘结果汇总，随 paramMiner:complete 事件推送
+type ParamMinerResult struct {
+	Targets int          `json:"targets"`
+	Params  []MinedParam `json:"params"`
+}
+
+// defaultParamNames 内置的常见参数名
+var defaultParamNames = []string{
+	"id", "uid", "user", "user_id", "userid", "username", "name", "email", "pass", "password", "passwd", "pwd",
+	"token", "access_token", "auth", "key", "api_key", "apikey", "secret", "session", "sid", "code", "state",
+	"q", "query", "search", "keyword", "keywords", "s", "term", "filter", "sort", "order", "orderby", "by",
+	"page", "pagesize", "page_size", "pageSize", "pageNum", "pageNo", "size", "limit", "offset", "start", "count",
+	"type", "action", "act", "do", "cmd", "command", "exec", "func", "function", "method", "op", "mode",
+	"file", "filename", "path", "dir", "folder", "doc", "document", "template", "tpl", "view", "include", "load",
+	"url", "uri", "redirect", "redirect_uri", "redirect_url", "return", "returnUrl", "return_url", "next", "goto",
+	"callback", "cb", "jsonp", "format", "output", "lang", "locale", "debug", "test", "dev", "admin", "role",
+	"config", "cfg", "setting", "settings", "option", "options", "data", "json", "xml", "content", "text", "msg",
+	"message", "body", "value", "val", "field", "fields", "column", "table", "db", "database", "sql",
+	"category", "cat", "tag", "group", "gid", "pid", "cid", "tid", "oid", "aid", "orderId", "order_id", "userId",
+	"date", "time", "timestamp", "ts", "from", "to", "begin", "end", "year", "month", "day", "version", "v",
+	"host", "ip", "port", "domain", "site", "target", "dest", "destination", "src", "source", "ref", "referer",
+	"width", "height", "img", "image", "avatar", "upload", "download", "export", "import", "preview", "show",
+	"status", "enable", "enabled", "disable", "hidden", "visible", "force", "verbose", "trace", "log",
+}
+
+type ParamMinerService struct {
+	ctx       context.Context
+	dbManager *db.Manager
+
+	mu         sync.Mutex // 保护 mineCancel
+	mineCancel context.CancelFunc
+}
+
+func NewParamMinerService(dbManager *db.Manager) *ParamMinerService {
+	return &ParamMinerService{
+		dbManager: dbManager,
+	}
+}
+
+func (s *ParamMinerService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// StartMine 启动参数挖掘，结果通过 paramMiner:param / paramMiner:progress / paramMiner:complete 事件推送
+func (s *ParamMinerService) StartMine(config ParamMinerConfig) error {
+	var targets []string
+	for _, t := range config.Targets {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !strings.HasPrefix(t, "http://") && !strings.HasPrefix(t, "https://") {
+			t = "http://" + t
+		}
+		if u, err := url.Parse(t); err != nil || u.Host == "" {
+			return fmt.Errorf("目标地址无效: %s", t)
+		}
+		targets = append(targets, t)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("请至少提供一个目标")
+	}
+	config.Targets = targets
+
+	var words []string
+	if config.Wordlist != "" {
+		lines, err := readWordlist(config.Wordlist)
+		if err != nil {
+			return fmt.Errorf("加载字典失败: %v", err)
+		}
+		words = append(words, lines...)
+	}
+	words = append(words, config.Words...)
+	if len(words) == 0 && config.NoBuiltin && !config.UseKnown {
+		return fmt.Errorf("参数名字典为空")
+	}
+	config.Words = words
+
+	for _, loc := range config.Locations {
+		switch strings.ToLower(loc) {
+		case "query", "form", "json":
+		default:
+			return fmt.Errorf("未知的参数位置: %s", loc)
+		}
+	}
+
+	s.mu.Lock()
+	if s.mineCancel != nil {
+		s.mineCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mineCancel = cancel
+	s.mu.Unlock()
+
+	go s.runMine(ctx, config)
+	return nil
+}
+
+// StopMine 停止当前参数挖掘任务
+func (s *ParamMinerService) StopMine() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mineCancel != nil {
+		s.mineCancel()
+		s.mineCancel = nil
+		logger.Info("参数挖掘任务已停止")
+	}
+}
+
+func (s *ParamMinerService) emitLog(msg string) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "paramMiner:log", msg)
+	}
+}
+
+func (s *ParamMinerService) runMine(ctx context.Context, config ParamMinerConfig) {
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = 128
+	}
+	if config.Threads <= 0 {
+		config.Threads = 5
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10000
+	}
+	locations := config.Locations
+	if len(locations) == 0 {
+		locations = []string{"query"}
+	}
+
+	cache := newWebServiceCache(s.dbManager)
+	result := ParamMinerResult{Targets: len(config.Targets), Params: []MinedParam{}}
+	defer func() {
+		s.emitLog(fmt.Sprintf("参数挖掘完成: %d 个端点，确认 %d 个参数", result.Targets, len(result.Params)))
+		runtime.EventsEmit(s.ctx, "paramMiner:complete", result)
+	}()
+
+	for _, target := range config.Targets {
+		wsID := cache.ID(target)
+		for _, loc := range locations {
+			if ctx.Err() != nil {
+				return
+			}
+			m := newParamMiner(config, target, strings.ToLower(loc))
+			m.onParam = func(p MinedParam) {
+				result.Params = append(result.Params, p)
+				s.recordParam(wsID, p)
+				runtime.EventsEmit(s.ctx, "paramMiner:param", p)
+			}
+			m.onProgress = func(p ParamMinerProgress) {
+				runtime.EventsEmit(s.ctx, "paramMiner:progress", p)
+			}
+
+			candidates := append([]string{}, config.Words...)
+			if !config.NoBuiltin {
+				candidates = append(candidates, defaultParamNames...)
+			}
+			if config.UseKnown && s.dbManager != nil && wsID > 0 {
+				if known, err := s.dbManager.GetWebParamNames(wsID); err == nil {
+					candidates = append(candidates, known...)
+				}
+			}
+
+			s.emitLog(fmt.Sprintf("开始挖掘参数: %s %s (%s)", m.method, target, m.location))
+			if err := m.Run(ctx, candidates); err != nil {
+				logger.Warn("参数挖掘失败", "目标", target, "位置", m.location, "错误", err)
+				s.emitLog(fmt.Sprintf("参数挖掘失败 %s (%s): %v", target, m.location, err))
+			}
+		}
+	}
+}
+
+// recordParam 将确认的参数写入 web_params 表，URL 不含查询串
+func (s *ParamMinerService) recordParam(wsID int64, p MinedParam) {
+	if s.dbManager == nil || wsID == 0 {
+		return
+	}
+	endpoint := p.URL
+	if u, err := url.Parse(p.URL); err == nil {
+		u.RawQuery, u.Fragment = "", ""
+		endpoint = u.String()
+	}
+	if err := s.dbManager.UpsertWebParam(wsID, endpoint, p.Name, p.Location, p.Reason); err != nil {
+		logger.Warn("保存参数失败", "url", p.URL, "参数", p.Name, "错误", err)
+	}
+}
+
+// paramMiner 对单个端点的单个参数位置进行挖掘：
+// 按分组批量发送参数，与基准响应比较，出现差异的分组二分定位到具体参数
+type paramMiner struct {
+	config   ParamMinerConfig
+	target   string
+	method   string
+	location string
+	client   *http.Client
+
+	onParam    func(MinedParam)
+	onProgress func(ParamMinerProgress)
+
+	canary    string // 参数值前缀，用于检测反射与剔除响应中的回显
+	baseline  *paramSignature
+	echo      bool // 基准请求回显了随机参数（例如页面输出完整 URL），此时不做反射检测，并在比较时剔除参数名
+	chunkSize int
+
+	mu       sync.Mutex
+	found    map[string]bool
+	tested   atomic.Int64
+	requests atomic.Int64
+}
+
+// paramSignature 用于比较的响应特征
+type paramSignature struct {
+	Status   int
+	Location string
+	Words    int
+	Lines    int
+	Hash     string
+
+	// 基准响应两次请求之间不稳定的特征不参与比较
+	compareWords bool
+	compareLines bool
+	compareHash  bool
+}
+
+func newParamMiner(config ParamMinerConfig, target, location string) *paramMiner {
+	method := strings.ToUpper(strings.TrimSpace(config.Method))
+	if method == "" {
+		method = "GET"
+	}
+	if location != "query" && (method == "GET" || method == "HEAD") {
+		method = "POST"
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(config.Timeout) * time.Millisecond,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &paramMiner{
+		config:    config,
+		target:    target,
+		method:    method,
+		location:  location,
+		client:    client,
+		canary:    randomToken()[:8],
+		chunkSize: config.ChunkSize,
+		found:     make(map[string]bool),
+	}
+}
+
+// Run 建立基准并挖掘候选参数
+func (m *paramMiner) Run(ctx context.Context, candidates []string) error {
+	junk := []string{randomToken()[:10]}
+
+	first, err := m.send(ctx, junk)
+	if err != nil {
+		return fmt.Errorf("基准请求失败: %v", err)
+	}
+	second, err := m.send(ctx, junk)
+	if err != nil {
+		return fmt.Errorf("基准请求失败: %v", err)
+	}
+	m.echo = bytes.Contains(first.Body, []byte(m.value(junk[0])))
+	m.baseline = m.signature(first, junk)
+	other := m.signature(second, junk)
+	m.baseline.compareHash = m.baseline.Hash == other.Hash
+	m.baseline.compareWords = m.baseline.Words == other.Words
+	m.baseline.compareLines = m.baseline.Lines == other.Lines
+
+	// 基准响应本身也可能暴露参数名
+	candidates = append(candidates, extractParamNames(string(first.Body))...)
+	candidates = m.filterCandidates(candidates)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// 目标拒绝过多参数（414、413、400 等）时缩小分组
+	for m.chunkSize > 8 {
+		names := make([]string, m.chunkSize)
+		for i := range names {
+			names[i] = randomToken()[:10]
+		}
+		resp, err := m.send(ctx, names)
+		if err != nil {
+			return err
+		}
+		if m.diff(resp, names) == "" {
+			break
+		}
+		m.chunkSize /= 2
+	}
+
+	total := int64(len(candidates))
+	stopProgress := m.startProgress(total)
+	defer stopProgress()
+
+	chunks := make(chan []string)
+	var wg sync.WaitGroup
+	for i := 0; i < m.config.Threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				m.bisect(ctx, chunk)
+				m.tested.Add(int64(len(chunk)))
+			}
+		}()
+	}
+
+feed:
+	for start := 0; start < len(candidates); start += m.chunkSize {
+		end := start + m.chunkSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+		select {
+		case <-ctx.Done():
+			break feed
+		case chunks <- candidates[start:end]:
+		}
+	}
+	close(chunks)
+	wg.Wait()
+	return ctx.Err()
+}
+
+// filterCandidates 去重并剔除目标 URL 中已有的参数
+func (m *paramMiner) filterCandidates(candidates []string) []string {
+	existing := make(map[string]bool)
+	if u, err := url.Parse(m.target); err == nil {
+		for name := range u.Query() {
+			existing[name] = true
+		}
+	}
+	var list []string
+	for _, name := range unique(candidates) {
+		name = strings.TrimSpace(name)
+		if name != "" && !existing[name] && len(name) <= 64 {
+			list = append(list, name)
+		}
+	}
+	return list
+}
+
+// bisect 发送一组参数，响应与基准不同时二分定位
+func (m *paramMiner) bisect(ctx context.Context, names []string) {
+	if ctx.Err() != nil || len(names) == 0 {
+		return
+	}
+	resp, err := m.send(ctx, names)
+	if err != nil {
+		return
+	}
+
+	if !m.echo {
+		for _, name := range names {
+			if bytes.Contains(resp.Body, []byte(m.value(name))) {
+				m.report(name, "reflected")
+			}
+		}
+	}
+
+	reason := m.diff(resp, names)
+	if reason == "" {
+		return
+	}
+	if len(names) == 1 {
+		// 再确认一次，排除偶发的响应波动
+		again, err := m.send(ctx, names)
+		if err == nil && m.diff(again, names) != "" {
+			m.report(names[0], reason)
+		}
+		return
+	}
+	mid := len(names) / 2
+	m.bisect(ctx, names[:mid])
+	m.bisect(ctx, names[mid:])
+}
+
+func (m *paramMiner) report(name, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.found[name] {
+		return
+	}
+	m.found[name] = true
+
+	if m.onParam != nil {
+		m.onParam(MinedParam{URL: m.target, Method: m.method, Location: m.location, Name: name, Reason: reason})
+	}
+}
+
+// diff 比较响应与基准，返回差异描述，无差异时返回空串
+func (m *paramMiner) diff(resp *dirResponse, names []string) string {
+	sig := m.signature(resp, names)
+	base := m.baseline
+	switch {
+	case sig.Status != base.Status:
+		return fmt.Sprintf("status %d -> %d", base.Status, sig.Status)
+	case sig.Location != base.Location:
+		return fmt.Sprintf("location %s -> %s", base.Location, sig.Location)
+	case base.compareHash && sig.Hash != base.Hash:
+		return fmt.Sprintf("content changed (%d -> %d words)", base.Words, sig.Words)
+	case base.compareWords && sig.Words != base.Words:
+		return fmt.Sprintf("words %d -> %d", base.Words, sig.Words)
+	case base.compareLines && sig.Lines != base.Lines:
+		return fmt.Sprintf("lines %d -> %d", base.Lines, sig.Lines)
+	}
+	return ""
+}
+
+// signature 剔除响应中回显的参数值（回显整个请求时连同参数名）后计算特征
+func (m *paramMiner) signature(resp *dirResponse, names []string) *paramSignature {
+	pairs := make([]string, 0, len(names)*4)
+	for _, name := range names {
+		pairs = append(pairs, m.value(name), "")
+		if m.echo {
+			pairs = append(pairs, url.QueryEscape(name), "")
+			if escaped := url.QueryEscape(name); escaped != name {
+				pairs = append(pairs, name, "")
+			}
+		}
+	}
+	body := strings.NewReplacer(pairs...).Replace(string(resp.Body))
+
+	sig := &paramSignature{
+		Status:   resp.Status,
+		Location: resp.Location,
+		Words:    len(strings.Fields(body)),
+		Lines:    strings.Count(body, "\n"),
+		Hash:     contentHash([]byte(body)),
+	}
+	if i := strings.IndexByte(sig.Location, '?'); i >= 0 {
+		sig.Location = sig.Location[:i]
+	}
+	return sig
+}
+
+// value 参数的取值：canary + 参数名的短哈希，便于从响应中识别回显
+func (m *paramMiner) value(name string) string {
+	return m.canary + contentHash([]byte(name))[:6]
+}
+
+// send 携带指定参数发送请求
+func (m *paramMiner) send(ctx context.Context, names []string) (*dirResponse, error) {
+	reqURL := m.target
+	var body io.Reader
+	var contentType string
+
+	switch m.location {
+	case "form":
+		values := url.Values{}
+		for _, name := range names {
+			values.Set(name, m.value(name))
+		}
+		body = strings.NewReader(values.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	case "json":
+		obj := make(map[string]string, len(names))
+		for _, name := range names {
+			obj[name] = m.value(name)
+		}
+		data, _ := json.Marshal(obj)
+		body = bytes.NewReader(data)
+		contentType = "application/json"
+	default:
+		u, err := url.Parse(m.target)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		b.WriteString(u.RawQuery)
+		for _, name := range names {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(name))
+			b.WriteByte('=')
+			b.WriteString(m.value(name))
+		}
+		u.RawQuery = b.String()
+		reqURL = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, m.method, reqURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	m.config.Request.applyTo(req)
+
+	start := time.Now()
+	resp, err := m.client.Do(req)
+	m.requests.Add(1)
+	if err != nil {
+		return nil, err
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	resp.Body.Close()
+	return newDirResponse(resp, data, time.Since(start)), nil
+}
+
+// startProgress 每秒推送一次进度，返回的函数用于停止并推送最终进度
+func (m *paramMiner) startProgress(total int64) func() {
+	emit := func() {
+		if m.onProgress != nil {
+			m.onProgress(ParamMinerProgress{
+				URL:      m.target,
+				Location: m.location,
+				Tested:   m.tested.Load(),
+				Total:    total,
+				Requests: m.requests.Load(),
+			})
+		}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				emit()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		emit()
+	}
+}
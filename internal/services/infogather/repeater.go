@@ -0,0 +1,342 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RepeaterRequest 重放请求参数
+type RepeaterRequest struct {
+	Target          string             `json:"target"`           // 原始请求为相对路径时使用的协议与主机，如 https://example.com
+	RawRequest      string             `json:"raw_request"`      // 原始 HTTP 请求
+	Timeout         int                `json:"timeout"`          // 超时（毫秒），默认 15000
+	FollowRedirects bool               `json:"follow_redirects"` // 是否跟随重定向
+	Options         HTTPRequestOptions `json:"options"`          // 共享的 Cookie / 认证 / 请求头，原始请求中已有的头不会被覆盖
+	Note            string             `json:"note"`             // 历史记录备注
+}
+
+// RepeaterResponse 重放结果
+type RepeaterResponse struct {
+	HistoryID   int64  `json:"history_id"`
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	RawRequest  string `json:"raw_request"`  // 实际发送的请求报文
+	RawResponse string `json:"raw_response"` // 完整响应报文（gzip 已解压）
+	Status      int    `json:"status"`
+	Size        int    `json:"size"`
+	TTFB        int64  `json:"ttfb"`     // 首字节时间（毫秒）
+	Duration    int64  `json:"duration"` // 总耗时（毫秒）
+	Error       string `json:"error,omitempty"`
+}
+
+// maxRepeaterBody 响应体读取上限，超出部分截断
+const maxRepeaterBody = 10 * 1024 * 1024
+
+type RepeaterService struct {
+	ctx       context.Context
+	dbManager *db.Manager
+
+	// 两个客户端共用连接池，仅重定向策略不同，超时通过请求上下文控制
+	client         *http.Client
+	redirectClient *http.Client
+}
+
+func NewRepeaterService(dbManager *db.Manager) *RepeaterService {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		// 保持原始请求中的 Accept-Encoding，由 send 自行解压
+		DisableCompression: true,
+	}
+	return &RepeaterService{
+		dbManager: dbManager,
+		client: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		redirectClient: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func (s *RepeaterService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// Send 发送原始请求并返回完整响应，请求与响应写入当前项目数据库的历史记录
+func (s *RepeaterService) Send(request RepeaterRequest) RepeaterResponse {
+	result := RepeaterResponse{RawRequest: request.RawRequest}
+
+	raw, err := parseRawRequest(request.RawRequest, request.Target)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Method, result.URL = raw.Method, raw.URL
+
+	s.send(raw, request, &result)
+	s.saveHistory(&result, request)
+	return result
+}
+
+// Replay 按原始请求与发送时的超时、重定向和共享请求选项重新发送一条历史记录
+// 早期记录没有保存这些设置，此时重放实际发送的报文
+func (s *RepeaterService) Replay(historyID int64) (RepeaterResponse, error) {
+	item, err := s.dbManager.GetRepeaterHistoryItem(historyID)
+	if err != nil {
+		return RepeaterResponse{}, err
+	}
+
+	var request RepeaterRequest
+	if item.Request != "" {
+		if err := json.Unmarshal([]byte(item.Request), &request); err != nil {
+			return RepeaterResponse{}, fmt.Errorf("解析重放设置失败: %v", err)
+		}
+	} else {
+		request.RawRequest = item.RawRequest
+		if u, err := url.Parse(item.URL); err == nil {
+			request.Target = u.Scheme + "://" + u.Host
+		}
+	}
+	request.Note = item.Note
+	return s.Send(request), nil
+}
+
+// RequestFromEndpoint 根据已保存的端点生成可编辑的原始请求
+// kind: "directory"（目录扫描结果）、"url"（爬虫 / 元数据发现的 URL）、"api"（接口清单）或 "graphql"（GraphQL 操作）
+func (s *RepeaterService) RequestFromEndpoint(kind string, id int64) (string, error) {
+	method := "GET"
+	var rawURL string
+	var err error
+
+	switch kind {
+	case "directory":
+		method, rawURL, err = s.dbManager.GetWebDirectoryURL(id)
+	case "url":
+		rawURL, err = s.dbManager.GetWebURL(id)
+	case "api":
+		var params []string
+		method, rawURL, params, err = s.dbManager.GetAPIEndpointURL(id)
+		// GET 接口把已知参数名作为空值放入查询串，方便直接填值
+		if err == nil && (method == "" || strings.EqualFold(method, "GET")) && len(params) > 0 {
+			rawURL = withEmptyParams(rawURL, params)
+		}
+	case "graphql":
+		return s.requestFromGraphQLOperation(id)
+	default:
+		return "", fmt.Errorf("未知的端点类型: %s", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+	return RequestFromURL(method, rawURL)
+}
+
+// requestFromGraphQLOperation 生成 GraphQL 操作的请求模板，POST 端点使用 JSON 请求体，GET 端点使用 query 参数
+func (s *RepeaterService) requestFromGraphQLOperation(id int64) (string, error) {
+	op, endpoint, transport, err := s.dbManager.GetGraphQLOperationRequest(id)
+	if err != nil {
+		return "", err
+	}
+	query := graphQLQueryTemplate(op.Kind, op.Name, op.Args, op.ReturnType)
+	if transport == "GET" {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", err
+		}
+		q := u.Query()
+		q.Set("query", query)
+		u.RawQuery = q.Encode()
+		return RequestFromURL("GET", u.String())
+	}
+
+	r, err := newRequestTemplate("POST", endpoint)
+	if err != nil {
+		return "", err
+	}
+	body, _ := json.Marshal(map[string]string{"query": query})
+	r.Headers = append(r.Headers, [2]string{"Content-Type", "application/json"})
+	r.Body = string(body)
+	return r.String(), nil
+}
+
+// RequestFromURL 生成指定 URL 的原始请求模板
+func RequestFromURL(method, rawURL string) (string, error) {
+	r, err := newRequestTemplate(method, rawURL)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+func newRequestTemplate(method, rawURL string) (*rawRequest, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("URL 无效: %s", rawURL)
+	}
+	if method == "" {
+		method = "GET"
+	}
+	r := &rawRequest{
+		Method: strings.ToUpper(method),
+		URL:    rawURL, // 不使用 u.String()，保留路径中的 {id} 等占位符
+		Headers: [][2]string{
+			{"Host", u.Host},
+			{"User-Agent", defaultUserAgent},
+			{"Accept", "*/*"},
+			{"Connection", "close"},
+		},
+	}
+	return r, nil
+}
+
+// withEmptyParams 将查询串中缺少的参数名以空值追加到 URL 末尾
+// 只改写查询串，路径中的 {id} 等占位符保持原样，不经过 url.URL 的转义
+func withEmptyParams(rawURL string, params []string) string {
+	base, rawQuery, _ := strings.Cut(rawURL, "?")
+	q, _ := url.ParseQuery(rawQuery)
+
+	var b strings.Builder
+	b.WriteString(rawQuery)
+	for _, p := range params {
+		if q.Has(p) {
+			continue
+		}
+		q.Set(p, "")
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(url.QueryEscape(p) + "=")
+	}
+	if b.Len() == 0 {
+		return base
+	}
+	return base + "?" + b.String()
+}
+
+// GetHistory 获取最近的重放记录（不含响应内容）
+func (s *RepeaterService) GetHistory(limit int) ([]db.RepeaterHistory, error) {
+	return s.dbManager.GetRepeaterHistory(limit)
+}
+
+// GetHistoryItem 获取单条重放记录（含响应内容）
+func (s *RepeaterService) GetHistoryItem(id int64) (*db.RepeaterHistory, error) {
+	return s.dbManager.GetRepeaterHistoryItem(id)
+}
+
+// DeleteHistory 删除重放记录，id 为 0 时清空全部
+func (s *RepeaterService) DeleteHistory(id int64) error {
+	return s.dbManager.DeleteRepeaterHistory(id)
+}
+
+func (s *RepeaterService) send(raw *rawRequest, request RepeaterRequest, result *RepeaterResponse) {
+	timeout := 15 * time.Second
+	if request.Timeout > 0 {
+		timeout = time.Duration(request.Timeout) * time.Millisecond
+	}
+
+	client := s.client
+	if request.FollowRedirects {
+		client = s.redirectClient
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := raw.build(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	request.Options.applyTo(req)
+
+	if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+		result.RawRequest = string(dump)
+		// DumpRequestOut 使用默认 Transport，会补上实际并未发送的 Accept-Encoding
+		if req.Header.Get("Accept-Encoding") == "" {
+			result.RawRequest = strings.Replace(result.RawRequest, "Accept-Encoding: gzip\r\n", "", 1)
+		}
+	}
+
+	start := time.Now()
+	var ttfb time.Duration
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+		GotFirstResponseByte: func() { ttfb = time.Since(start) },
+	}))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Duration = time.Since(start).Milliseconds()
+		result.Error = err.Error()
+		return
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRepeaterBody))
+	resp.Body.Close()
+	result.Duration = time.Since(start).Milliseconds()
+	result.TTFB = ttfb.Milliseconds()
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		if zr, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if plain, err := io.ReadAll(io.LimitReader(zr, maxRepeaterBody)); err == nil {
+				body = plain
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\r\n", resp.Proto, resp.Status)
+	resp.Header.Write(&b)
+	b.WriteString("\r\n")
+	b.Write(body)
+
+	result.Status = resp.StatusCode
+	result.Size = len(body)
+	result.RawResponse = b.String()
+}
+
+func (s *RepeaterService) saveHistory(result *RepeaterResponse, request RepeaterRequest) {
+	if s.dbManager == nil || s.dbManager.GetDB() == nil {
+		return
+	}
+	// 备注单独存储，设置中只保留重放所需的字段
+	note := request.Note
+	request.Note = ""
+	settings, _ := json.Marshal(request)
+
+	id, err := s.dbManager.AddRepeaterHistory(db.RepeaterHistory{
+		Method:      result.Method,
+		URL:         result.URL,
+		RawRequest:  result.RawRequest,
+		RawResponse: result.RawResponse,
+		StatusCode:  result.Status,
+		DurationMs:  result.Duration,
+		Error:       result.Error,
+		Note:        note,
+		Request:     string(settings),
+	})
+	if err != nil {
+		logger.Warn("保存重放记录失败", "url", result.URL, "错误", err)
+		return
+	}
+	result.HistoryID = id
+}
@@ -2,7 +2,6 @@ package infogather
 
 import (
 	"JAttack/internal/pkg/logger"
-	"context"
 	"fmt"
 	"net"
 	"strconv"
@@ -23,6 +22,8 @@ type ScanConfig struct {
 	EnableICMP     bool   `json:"enable_icmp"`      // 启用 ICMP 存活检测
 	EnablePing     bool   `json:"enable_ping"`      // ICMP 的别名
 	EnableUDP      bool   `json:"enable_udp"`       // 启用 UDP 探测
+
+	HarvestWellKnown bool `json:"harvest_well_known"` // 发现 Web 服务时收集 robots.txt、sitemap.xml 等元数据，请求计入并发线程数
 }
 
 type ScanResult struct {
@@ -34,15 +35,7 @@ type ScanResult struct {
 
 // StartScan 启动扫描任务
 func (s *InfoService) StartScan(config ScanConfig) {
-	s.mu.Lock()
-	if s.scanCancel != nil {
-		s.scanCancel() // 如果已有任务在运行，则取消
-	}
-	s.scanCtx, s.scanCancel = context.WithCancel(context.Background())
-	s.paused.Store(false) // 重置暂停状态
-	s.mu.Unlock()
-
-	go s.runScan(config)
+	s.startScanTask(func() { s.runScan(config) })
 }
 
 func (s *InfoService) waitIfPaused() bool {
@@ -121,7 +114,7 @@ func (s *InfoService) runScan(config ScanConfig) {
 			timeout = time.Duration(config.Timeout) * time.Millisecond
 		}
 
-		s.portScan(targetIPs, ports, config.Concurrency, timeout)
+		s.portScan(targetIPs, ports, config.Concurrency, timeout, config.HarvestWellKnown)
 	}
 
 	if s.waitIfPaused() {
@@ -212,7 +205,7 @@ func (s *InfoService) pingHost(ip string, timeout time.Duration) bool {
 	return pinger.Statistics().PacketsRecv > 0
 }
 
-func (s *InfoService) portScan(ips []string, ports []int, concurrency int, timeout time.Duration) {
+func (s *InfoService) portScan(ips []string, ports []int, concurrency int, timeout time.Duration, harvestWellKnown bool) {
 	sem := make(chan struct{}, concurrency)
 	var wg sync.WaitGroup
 
@@ -259,6 +252,18 @@ func (s *InfoService) portScan(ips []string, ports []int, concurrency int, timeo
 						serviceName = "https"
 					} else if p == 22 {
 						serviceName = "ssh"
+					} else if p == 445 {
+						serviceName = "smb"
+					}
+
+					// 元数据在当前工作线程内收集，受并发线程数限制；网络请求不能放进写库队列
+					var wellKnown *WellKnownResult
+					webURL := ""
+					if serviceName == "http" || serviceName == "https" {
+						webURL = fmt.Sprintf("%s://%s:%d", serviceName, ipAddr, p)
+						if harvestWellKnown {
+							wellKnown = HarvestWellKnown(s.scanCtx, webURL, nil, timeout)
+						}
 					}
 
 					// Save Asset & Port asynchronously
@@ -273,17 +278,26 @@ func (s *InfoService) portScan(ips []string, ports []int, concurrency int, timeo
 						portID, _ := s.dbManager.UpsertAssetPort(assetID, p, "tcp", serviceName, "", "", banner, "open")
 
 						// If HTTP/HTTPS, create Web Service placeholder
-						if serviceName == "http" || serviceName == "https" {
-							protocol := "http"
-							if serviceName == "https" {
-								protocol = "https"
+						if webURL != "" {
+							webServiceID, err := s.dbManager.UpsertWebService(assetID, portID, webURL, "", "", banner)
+							if err == nil && wellKnown != nil {
+								recordWellKnown(s.dbManager, webServiceID, wellKnown)
 							}
-							url := fmt.Sprintf("%s://%s:%d", protocol, ipAddr, p)
-							s.dbManager.UpsertWebService(assetID, portID, url, "", "", banner)
 						}
 					}
 
 					s.saveResult(ipAddr, "PortScan", info)
+					if wellKnown != nil && len(wellKnown.found()) > 0 {
+						s.emitLog(fmt.Sprintf("[元数据] %s %s", webURL, wellKnown.Summary()))
+						s.saveResult(webURL, "WellKnown", wellKnown.Summary())
+					}
+
+					// Windows 主机：匿名搜集主机名、域和系统版本
+					if p == 445 {
+						if hostInfo := GatherSMBInfo(s.scanCtx, ipAddr, timeout); hostInfo != nil {
+							s.recordSMBInfo(hostInfo)
+						}
+					}
 				}
 			}(ip, port)
 		}
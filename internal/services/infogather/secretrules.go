@@ -0,0 +1,524 @@
+package infogather
+
+import (
+	"JAttack/internal/config"
+	"JAttack/internal/pkg/logger"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultSecretRules 内置规则包，用户规则按 id 覆盖其中的同名规则
+//
+//go:embed secret_rules.yaml
+var defaultSecretRules []byte
+
+// secretRulesFile 用户自定义规则保存的文件名，位于配置目录下
+const secretRulesFile = "secret_rules.yaml"
+
+// maxSecretDisplay 结果列表中展示的值的最大字节数
+const maxSecretDisplay = 100
+
+// defaultSecretContext 结果两侧默认保留的上下文字节数，maxSecretContext 为上限
+const (
+	defaultSecretContext = 80
+	maxSecretContext     = 2000
+)
+
+var secretSeverities = map[string]bool{"critical": true, "high": true, "medium": true, "low": true, "info": true}
+
+// SecretRule 敏感信息检测规则，字段含义见 secret_rules.yaml 文件头
+type SecretRule struct {
+	ID        string   `json:"id" yaml:"id"`
+	Name      string   `json:"name" yaml:"name"`
+	Regex     string   `json:"regex" yaml:"regex"`
+	Group     int      `json:"group" yaml:"group,omitempty"`
+	Severity  string   `json:"severity" yaml:"severity,omitempty"`
+	Keywords  []string `json:"keywords" yaml:"keywords,omitempty"`
+	Entropy   float64  `json:"entropy" yaml:"entropy,omitempty"`
+	Allowlist []string `json:"allowlist" yaml:"allowlist,omitempty"`
+	Disabled  bool     `json:"disabled" yaml:"disabled,omitempty"`
+	Builtin   bool     `json:"builtin" yaml:"-"` // 与内置规则同 id（删除时改为禁用）
+}
+
+// secretRuleSet YAML / JSON 规则文件的顶层结构
+type secretRuleSet struct {
+	Rules []SecretRule `json:"rules" yaml:"rules"`
+}
+
+// SecretFinding 一条敏感信息命中结果
+type SecretFinding struct {
+	RuleID   string  `json:"rule_id"`
+	Name     string  `json:"name"`
+	Severity string  `json:"severity"`
+	Value    string  `json:"value"`
+	Entropy  float64 `json:"entropy"` // 捕获值的香农熵（bit/字符）
+	Source   string  `json:"source,omitempty"`
+
+	Offset  int    `json:"offset"`  // 值在来源内容中的字节偏移
+	Line    int    `json:"line"`    // 从 1 开始的行号
+	Column  int    `json:"column"`  // 从 1 开始的列号（按字符计）
+	Context string `json:"context"` // 值附近的代码片段
+
+	Validation       string `json:"validation,omitempty"` // 在线验证结论：valid / invalid / unknown
+	ValidationDetail string `json:"validation_detail,omitempty"`
+}
+
+// String 返回 "类型: 值" 形式的展示文本，与 SensitiveInfo 中的条目一致
+func (f SecretFinding) String() string {
+	val := f.Value
+	if len(val) > maxSecretDisplay {
+		// 在字符边界处截断，避免切开多字节字符
+		cut := maxSecretDisplay
+		for cut > 0 && !utf8.RuneStart(val[cut]) {
+			cut--
+		}
+		val = val[:cut] + "..."
+	}
+	return fmt.Sprintf("%s: %s", f.Name, val)
+}
+
+// compiledSecretRule 编译后的规则
+type compiledSecretRule struct {
+	SecretRule
+	re       *regexp.Regexp
+	keywords []string
+	allow    []*regexp.Regexp
+}
+
+// secretEngine 当前生效的规则，首次使用时加载，规则变更后重新加载
+var secretEngine struct {
+	mu     sync.RWMutex
+	loaded bool
+	rules  []*compiledSecretRule
+}
+
+// compile 校验并编译规则
+func (r SecretRule) compile() (*compiledSecretRule, error) {
+	if strings.TrimSpace(r.ID) == "" {
+		return nil, errors.New("规则 id 不能为空")
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return nil, fmt.Errorf("规则 %s 缺少 name", r.ID)
+	}
+	re, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("规则 %s 正则无效: %v", r.ID, err)
+	}
+	if r.Group > re.NumSubexp() {
+		return nil, fmt.Errorf("规则 %s 的捕获组 %d 超出正则中的分组数 %d", r.ID, r.Group, re.NumSubexp())
+	}
+	if r.Severity == "" {
+		r.Severity = "medium"
+	}
+	r.Severity = strings.ToLower(r.Severity)
+	if !secretSeverities[r.Severity] {
+		return nil, fmt.Errorf("规则 %s 的 severity 无效: %s", r.ID, r.Severity)
+	}
+
+	c := &compiledSecretRule{SecretRule: r, re: re}
+	for _, kw := range r.Keywords {
+		if kw = strings.ToLower(strings.TrimSpace(kw)); kw != "" {
+			c.keywords = append(c.keywords, kw)
+		}
+	}
+	for _, pattern := range r.Allowlist {
+		allow, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("规则 %s 的 allowlist 正则无效: %v", r.ID, err)
+		}
+		c.allow = append(c.allow, allow)
+	}
+	return c, nil
+}
+
+// parseSecretRules 解析 YAML 或 JSON 规则文件，既支持 {rules: [...]} 也支持直接的规则数组
+func parseSecretRules(data []byte) ([]SecretRule, error) {
+	var set secretRuleSet
+	if err := yaml.Unmarshal(data, &set); err == nil && len(set.Rules) > 0 {
+		return set.Rules, nil
+	}
+	var rules []SecretRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析规则文件失败: %v", err)
+	}
+	return rules, nil
+}
+
+// secretRulesPath 用户规则文件路径
+func secretRulesPath() string {
+	return filepath.Join(config.ConfigDir, secretRulesFile)
+}
+
+// readUserSecretRules 读取用户规则，文件不存在时返回空
+func readUserSecretRules() ([]SecretRule, error) {
+	data, err := os.ReadFile(secretRulesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseSecretRules(data)
+}
+
+func writeUserSecretRules(rules []SecretRule) error {
+	data, err := yaml.Marshal(secretRuleSet{Rules: rules})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.ConfigDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(secretRulesPath(), data, 0644)
+}
+
+// mergedSecretRules 内置规则叠加用户规则：同 id 覆盖，新 id 追加在后
+func mergedSecretRules() ([]SecretRule, error) {
+	builtin, err := parseSecretRules(defaultSecretRules)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]SecretRule, 0, len(builtin))
+	index := make(map[string]int)
+	for _, r := range builtin {
+		r.Builtin = true
+		index[r.ID] = len(rules)
+		rules = append(rules, r)
+	}
+
+	user, err := readUserSecretRules()
+	if err != nil {
+		return rules, err
+	}
+	for _, r := range user {
+		if i, ok := index[r.ID]; ok {
+			r.Builtin = true
+			rules[i] = r
+			continue
+		}
+		index[r.ID] = len(rules)
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// reloadSecretRules 重新加载并编译规则，无效的规则记录日志后跳过
+func reloadSecretRules() error {
+	rules, err := mergedSecretRules()
+	if err != nil {
+		logger.Warn("读取自定义敏感信息规则失败，仅使用可解析的规则", "路径", secretRulesPath(), "错误", err)
+	}
+
+	var compiled []*compiledSecretRule
+	for _, r := range rules {
+		if r.Disabled {
+			continue
+		}
+		c, err := r.compile()
+		if err != nil {
+			logger.Warn("跳过无效的敏感信息规则", "错误", err)
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	secretEngine.mu.Lock()
+	secretEngine.rules = compiled
+	secretEngine.loaded = true
+	secretEngine.mu.Unlock()
+	return err
+}
+
+// activeSecretRules 返回当前生效的规则
+func activeSecretRules() []*compiledSecretRule {
+	secretEngine.mu.RLock()
+	loaded, rules := secretEngine.loaded, secretEngine.rules
+	secretEngine.mu.RUnlock()
+	if !loaded {
+		reloadSecretRules()
+		secretEngine.mu.RLock()
+		rules = secretEngine.rules
+		secretEngine.mu.RUnlock()
+	}
+	return rules
+}
+
+// scanSecrets 使用给定规则检测内容中的敏感信息，contextSize 为值两侧保留的上下文字节数
+func scanSecrets(content string, rules []*compiledSecretRule, contextSize int) []SecretFinding {
+	var findings []SecretFinding
+	var lower string
+	var lines []int
+	for _, rule := range rules {
+		if len(rule.keywords) > 0 {
+			if lower == "" {
+				lower = strings.ToLower(content)
+			}
+			hit := false
+			for _, kw := range rule.keywords {
+				if strings.Contains(lower, kw) {
+					hit = true
+					break
+				}
+			}
+			if !hit {
+				continue
+			}
+		}
+
+		group := rule.Group
+		if group == 0 && rule.re.NumSubexp() > 0 {
+			group = 1
+		} else if group < 0 {
+			group = 0
+		}
+
+		for _, m := range rule.re.FindAllStringSubmatchIndex(content, -1) {
+			start, end := m[2*group], m[2*group+1]
+			if start < 0 || start == end {
+				continue
+			}
+			val := content[start:end]
+			if rule.allowed(val) {
+				continue
+			}
+			entropy := shannonEntropy(val)
+			if rule.Entropy > 0 && entropy < rule.Entropy {
+				continue
+			}
+			if lines == nil {
+				lines = lineStarts(content)
+			}
+			line, col := position(content, lines, start)
+			findings = append(findings, SecretFinding{
+				RuleID:   rule.ID,
+				Name:     rule.Name,
+				Severity: rule.Severity,
+				Value:    val,
+				Entropy:  math.Round(entropy*100) / 100,
+				Offset:   start,
+				Line:     line,
+				Column:   col,
+				Context:  snippet(content, start, end, contextSize),
+			})
+		}
+	}
+	return findings
+}
+
+// lineStarts 返回每一行起始位置的字节偏移
+func lineStarts(content string) []int {
+	starts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// position 将字节偏移转换为从 1 开始的行号与列号
+func position(content string, lines []int, offset int) (int, int) {
+	line := sort.Search(len(lines), func(i int) bool { return lines[i] > offset }) - 1
+	return line + 1, utf8.RuneCountInString(content[lines[line]:offset]) + 1
+}
+
+// snippet 截取 [start, end) 两侧各 size 字节的内容，边界对齐到完整的 UTF-8 字符
+func snippet(content string, start, end, size int) string {
+	if size <= 0 {
+		size = defaultSecretContext
+	}
+	if size > maxSecretContext {
+		size = maxSecretContext
+	}
+	from, to := max(start-size, 0), min(end+size, len(content))
+	for from > 0 && !utf8.RuneStart(content[from]) {
+		from--
+	}
+	for to < len(content) && !utf8.RuneStart(content[to]) {
+		to++
+	}
+	return content[from:to]
+}
+
+func (r *compiledSecretRule) allowed(val string) bool {
+	for _, allow := range r.allow {
+		if allow.MatchString(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy 计算字符串按字节统计的香农熵（bit/字符）
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	n := float64(len(s))
+	entropy := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// uniqueSecretFindings 按来源、规则与值去重
+func uniqueSecretFindings(findings []SecretFinding) []SecretFinding {
+	seen := make(map[string]bool)
+	list := []SecretFinding{}
+	for _, f := range findings {
+		key := f.Source + "\x00" + f.RuleID + "\x00" + f.Value
+		if !seen[key] {
+			seen[key] = true
+			list = append(list, f)
+		}
+	}
+	return list
+}
+
+// GetSecretRules 返回内置与自定义合并后的全部规则（包括已禁用的）
+func (s *JSFinderService) GetSecretRules() ([]SecretRule, error) {
+	return mergedSecretRules()
+}
+
+// GetSecretRulesPath 返回自定义规则文件路径，便于用户直接编辑
+func (s *JSFinderService) GetSecretRulesPath() string {
+	return secretRulesPath()
+}
+
+// SaveSecretRule 新增或修改一条规则；修改内置规则时保存为同 id 的覆盖规则
+func (s *JSFinderService) SaveSecretRule(rule SecretRule) error {
+	if _, err := rule.compile(); err != nil {
+		return err
+	}
+	return s.updateUserSecretRules(func(rules []SecretRule) []SecretRule {
+		for i := range rules {
+			if rules[i].ID == rule.ID {
+				rules[i] = rule
+				return rules
+			}
+		}
+		return append(rules, rule)
+	})
+}
+
+// DeleteSecretRule 删除自定义规则；内置规则无法删除，改为禁用
+func (s *JSFinderService) DeleteSecretRule(id string) error {
+	builtin, err := parseSecretRules(defaultSecretRules)
+	if err != nil {
+		return err
+	}
+	var base *SecretRule
+	for i := range builtin {
+		if builtin[i].ID == id {
+			base = &builtin[i]
+			break
+		}
+	}
+
+	return s.updateUserSecretRules(func(rules []SecretRule) []SecretRule {
+		kept := rules[:0]
+		for _, r := range rules {
+			if r.ID != id {
+				kept = append(kept, r)
+			}
+		}
+		if base != nil {
+			disabled := *base
+			disabled.Disabled = true
+			kept = append(kept, disabled)
+		}
+		return kept
+	})
+}
+
+// ImportSecretRules 从 YAML / JSON 文件导入规则，与已有自定义规则按 id 合并，返回导入数量
+func (s *JSFinderService) ImportSecretRules(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	imported, err := parseSecretRules(data)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range imported {
+		if _, err := r.compile(); err != nil {
+			return 0, err
+		}
+	}
+
+	err = s.updateUserSecretRules(func(rules []SecretRule) []SecretRule {
+		index := make(map[string]int)
+		for i, r := range rules {
+			index[r.ID] = i
+		}
+		for _, r := range imported {
+			if i, ok := index[r.ID]; ok {
+				rules[i] = r
+				continue
+			}
+			index[r.ID] = len(rules)
+			rules = append(rules, r)
+		}
+		return rules
+	})
+	if err != nil {
+		return 0, err
+	}
+	logger.Info("导入敏感信息规则", "路径", path, "数量", len(imported))
+	return len(imported), nil
+}
+
+// ResetSecretRules 删除全部自定义规则，恢复内置规则包
+func (s *JSFinderService) ResetSecretRules() error {
+	if err := os.Remove(secretRulesPath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return reloadSecretRules()
+}
+
+// TestSecretRule 使用样本内容测试规则，便于编辑规则时调试
+func (s *JSFinderService) TestSecretRule(rule SecretRule, sample string) ([]SecretFinding, error) {
+	c, err := rule.compile()
+	if err != nil {
+		return nil, err
+	}
+	findings := scanSecrets(sample, []*compiledSecretRule{c}, defaultSecretContext)
+	if findings == nil {
+		findings = []SecretFinding{}
+	}
+	return findings, nil
+}
+
+// updateUserSecretRules 读取、修改并写回用户规则文件，随后重新加载规则
+func (s *JSFinderService) updateUserSecretRules(update func([]SecretRule) []SecretRule) error {
+	secretEngine.mu.Lock()
+	rules, err := readUserSecretRules()
+	if err == nil {
+		err = writeUserSecretRules(update(rules))
+	}
+	secretEngine.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return reloadSecretRules()
+}
@@ -0,0 +1,673 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	secretValid   = "valid"
+	secretInvalid = "invalid"
+	secretUnknown = "unknown"
+)
+
+// maxSecretPairs 配对验证 AccessKey 与 Secret 时最多尝试的组合数
+const maxSecretPairs = 5
+
+// SecretValidationConfig 密钥在线验证配置
+type SecretValidationConfig struct {
+	// Endpoints 按验证器名称覆盖接口地址，便于指向本地替身服务，例如 {"github": "http://127.0.0.1:8080"}
+	// Webhook 类验证器会用该地址替换原 Webhook 的 scheme 与 host
+	Endpoints map[string]string `json:"endpoints"`
+	BearerURL string            `json:"bearer_url"` // 通用 Bearer 验证请求的地址，为空时不验证
+	Timeout   int               `json:"timeout"`    // 超时时间(ms)
+	Threads   int               `json:"threads"`
+}
+
+// SecretValidation 单条结果的验证结论
+type SecretValidation struct {
+	Status string `json:"status"` // valid / invalid / unknown
+	Detail string `json:"detail"`
+}
+
+// SecretValidator 密钥验证器，按规则 id 认领需要验证的结果
+type SecretValidator interface {
+	Name() string            // SecretValidationConfig.Endpoints 中使用的名称
+	DefaultEndpoint() string // 默认接口地址，Webhook 类验证器为空表示直接请求原地址
+	Supports(ruleID string) bool
+	Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation
+}
+
+// secretValidators 已注册的验证器
+var secretValidators = []SecretValidator{
+	awsValidator{},
+	aliyunValidator{},
+	githubValidator{},
+	slackValidator{},
+	slackWebhookValidator{},
+	dingTalkValidator{},
+	weComValidator{},
+	bearerValidator{},
+}
+
+func validatorFor(ruleID string) SecretValidator {
+	for _, v := range secretValidators {
+		if v.Supports(ruleID) {
+			return v
+		}
+	}
+	return nil
+}
+
+// validationContext 一次验证任务共享的 HTTP 客户端、配置及同批结果
+type validationContext struct {
+	client  *http.Client
+	config  SecretValidationConfig
+	related []SecretFinding // 同批结果，用于配对 AccessKey 与 Secret
+}
+
+func newValidationContext(cfg SecretValidationConfig, related []SecretFinding) *validationContext {
+	timeout := 10 * time.Second
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Millisecond
+	}
+	return &validationContext{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config:  cfg,
+		related: related,
+	}
+}
+
+// endpoint 返回验证器使用的接口地址
+func (vc *validationContext) endpoint(v SecretValidator) string {
+	if e := strings.TrimRight(vc.config.Endpoints[v.Name()], "/"); e != "" {
+		return e
+	}
+	return v.DefaultEndpoint()
+}
+
+// webhookURL 配置了替身地址时替换 Webhook 的 scheme 与 host
+func (vc *validationContext) webhookURL(v SecretValidator, raw string) string {
+	override := vc.endpoint(v)
+	if override == "" {
+		return raw
+	}
+	u, err := url.Parse(raw)
+	o, err2 := url.Parse(override)
+	if err != nil || err2 != nil {
+		return raw
+	}
+	u.Scheme, u.Host = o.Scheme, o.Host
+	return u.String()
+}
+
+// pairs 返回同批结果中指定规则命中的值，用于配对验证
+func (vc *validationContext) pairs(ruleID string) []string {
+	var values []string
+	for _, f := range vc.related {
+		if f.RuleID == ruleID {
+			values = append(values, f.Value)
+		}
+	}
+	values = unique(values)
+	if len(values) > maxSecretPairs {
+		values = values[:maxSecretPairs]
+	}
+	return values
+}
+
+// do 发送请求并读取响应（最多 64KB）
+func (vc *validationContext) do(req *http.Request) (int, []byte, error) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", defaultUserAgent)
+	}
+	resp, err := vc.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, body, err
+}
+
+// postJSON 发送 JSON 请求体，常用于 Webhook 探测
+func (vc *validationContext) postJSON(ctx context.Context, target, payload string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", target, strings.NewReader(payload))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return vc.do(req)
+}
+
+// validateSecrets 并发验证一批结果，返回与输入一一对应的结论；相同规则与值只验证一次
+// ctx 取消后未完成验证的结果 Status 为空
+func validateSecrets(ctx context.Context, findings []SecretFinding, cfg SecretValidationConfig) []SecretValidation {
+	threads := cfg.Threads
+	if threads <= 0 {
+		threads = 5
+	}
+	vc := newValidationContext(cfg, findings)
+	results := make([]SecretValidation, len(findings))
+
+	groups := make(map[string][]int)
+	var keys []string
+	for i, f := range findings {
+		key := f.RuleID + "\x00" + f.Value
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, threads)
+	for _, key := range keys {
+		indexes := groups[key]
+		f := findings[indexes[0]]
+		v := validatorFor(f.RuleID)
+		if v == nil {
+			for _, i := range indexes {
+				results[i] = SecretValidation{Status: secretUnknown, Detail: "没有对应的验证器"}
+			}
+			continue
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r := v.Validate(ctx, vc, f)
+			if ctx.Err() != nil {
+				return // 已停止，请求失败不代表密钥无效
+			}
+			logger.Debug("验证敏感信息", "验证器", v.Name(), "类型", f.Name, "结果", r.Status, "详情", r.Detail)
+			for _, i := range indexes {
+				results[i] = r
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// ValidateSensitiveResults 在线验证 Web 服务下保存的全部敏感信息，并将结论写回数据库
+func (s *JSFinderService) ValidateSensitiveResults(webServiceID int64, cfg SecretValidationConfig) ([]db.SensitiveResult, error) {
+	if s.dbManager == nil {
+		return nil, fmt.Errorf("数据库未初始化")
+	}
+	rows, err := s.dbManager.GetWebSensitiveResults(webServiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 早期结果没有记录 rule_id，按规则名称找回
+	ruleIDs := make(map[string]string)
+	if rules, err := mergedSecretRules(); err == nil {
+		for _, r := range rules {
+			ruleIDs[r.Name] = r.ID
+		}
+	}
+	findings := make([]SecretFinding, len(rows))
+	for i, r := range rows {
+		ruleID := r.RuleID
+		if ruleID == "" {
+			ruleID = ruleIDs[r.InfoType]
+		}
+		findings[i] = SecretFinding{RuleID: ruleID, Name: r.InfoType, Value: r.Content, Source: r.SourceFile}
+	}
+
+	s.mu.Lock()
+	if s.validateCancel != nil {
+		s.validateCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.validateCancel = cancel
+	s.mu.Unlock()
+	defer cancel()
+
+	logger.Info("开始验证敏感信息", "webServiceID", webServiceID, "数量", len(rows))
+	validations := validateSecrets(ctx, findings, cfg)
+	for i, r := range rows {
+		if validations[i].Status == "" {
+			continue // 验证已停止，保留原有结论
+		}
+		if err := s.dbManager.UpdateSensitiveValidation(r.ID, validations[i].Status, validations[i].Detail); err != nil {
+			logger.Warn("保存验证结果失败", "id", r.ID, "错误", err)
+		}
+	}
+	return s.dbManager.GetWebSensitiveResults(webServiceID)
+}
+
+// StopValidateSensitiveResults 停止正在进行的在线验证，已完成的结论仍会写回数据库
+func (s *JSFinderService) StopValidateSensitiveResults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.validateCancel != nil {
+		s.validateCancel()
+		s.validateCancel = nil
+		logger.Info("敏感信息验证已停止")
+	}
+}
+
+// --- AWS ---
+
+var (
+	awsArnRegex  = regexp.MustCompile(`<Arn>([^<]+)</Arn>`)
+	awsCodeRegex = regexp.MustCompile(`<Code>([^<]+)</Code>`)
+)
+
+// awsValidator 调用 STS GetCallerIdentity，该接口不需要任何权限
+type awsValidator struct{}
+
+func (awsValidator) Name() string            { return "aws" }
+func (awsValidator) DefaultEndpoint() string { return "https://sts.amazonaws.com" }
+func (awsValidator) Supports(ruleID string) bool {
+	return ruleID == "aws-access-key" || ruleID == "aws-secret"
+}
+
+func (v awsValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	return validatePairs(f, "aws-access-key", "aws-secret", vc, func(key, secret string) SecretValidation {
+		return v.callerIdentity(ctx, vc, key, secret)
+	})
+}
+
+func (v awsValidator) callerIdentity(ctx context.Context, vc *validationContext, key, secret string) SecretValidation {
+	body := "Action=GetCallerIdentity&Version=2011-06-15"
+	req, err := http.NewRequestWithContext(ctx, "POST", vc.endpoint(v)+"/", strings.NewReader(body))
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signAWSRequest(req, []byte(body), key, secret, "us-east-1", "sts", time.Now())
+
+	status, resp, err := vc.do(req)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	if status == http.StatusOK {
+		detail := "GetCallerIdentity 成功"
+		if m := awsArnRegex.FindSubmatch(resp); m != nil {
+			detail = string(m[1])
+		}
+		return SecretValidation{Status: secretValid, Detail: detail}
+	}
+
+	code := ""
+	if m := awsCodeRegex.FindSubmatch(resp); m != nil {
+		code = string(m[1])
+	}
+	switch code {
+	case "InvalidClientTokenId":
+		return SecretValidation{Status: secretInvalid, Detail: "AccessKey 不存在"}
+	case "SignatureDoesNotMatch":
+		return SecretValidation{Status: secretInvalid, Detail: "AccessKey 存在，Secret 不匹配"}
+	}
+	return SecretValidation{Status: secretUnknown, Detail: fmt.Sprintf("HTTP %d %s", status, code)}
+}
+
+// signAWSRequest 按 Signature Version 4 为请求签名
+func signAWSRequest(req *http.Request, body []byte, key, secret, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	signedHeaders := "content-type;host;x-amz-date"
+	canonicalHeaders := "content-type:" + req.Header.Get("Content-Type") + "\nhost:" + req.URL.Host + "\nx-amz-date:" + amzDate + "\n"
+	canonical := strings.Join([]string{req.Method, path, req.URL.RawQuery, canonicalHeaders, signedHeaders, sha256Hex(body)}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	k := hmacSHA256([]byte("AWS4"+secret), date)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+	k = hmacSHA256(k, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(k, toSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", key, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// validatePairs 将 AccessKey 与同批结果中的 Secret 配对验证（或反之）
+// 找不到配对的 Secret 时使用占位 Secret 请求一次，只能区分 AccessKey 是否存在
+func validatePairs(f SecretFinding, keyRule, secretRule string, vc *validationContext, check func(key, secret string) SecretValidation) SecretValidation {
+	var keys, secrets []string
+	if f.RuleID == keyRule {
+		keys, secrets = []string{f.Value}, vc.pairs(secretRule)
+	} else {
+		keys, secrets = vc.pairs(keyRule), []string{f.Value}
+	}
+	if len(keys) == 0 {
+		return SecretValidation{Status: secretUnknown, Detail: "缺少配对的 AccessKey"}
+	}
+
+	if len(secrets) == 0 {
+		r := check(keys[0], strings.Repeat("0", 40))
+		if r.Status == secretInvalid && strings.Contains(r.Detail, "Secret 不匹配") {
+			return SecretValidation{Status: secretUnknown, Detail: "AccessKey 存在，缺少配对的 Secret"}
+		}
+		return r
+	}
+
+	last := SecretValidation{Status: secretUnknown}
+	tried := 0
+	for _, key := range keys {
+		for _, secret := range secrets {
+			if tried >= maxSecretPairs {
+				return last
+			}
+			tried++
+			r := check(key, secret)
+			if r.Status == secretValid {
+				return r
+			}
+			if r.Status == secretInvalid || last.Detail == "" {
+				last = r
+			}
+		}
+	}
+	return last
+}
+
+// --- Aliyun ---
+
+// aliyunValidator 调用 STS GetCallerIdentity（RPC 风格签名）
+type aliyunValidator struct{}
+
+func (aliyunValidator) Name() string            { return "aliyun" }
+func (aliyunValidator) DefaultEndpoint() string { return "https://sts.aliyuncs.com" }
+func (aliyunValidator) Supports(ruleID string) bool {
+	return ruleID == "aliyun-access-key" || ruleID == "aliyun-secret"
+}
+
+func (v aliyunValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	return validatePairs(f, "aliyun-access-key", "aliyun-secret", vc, func(key, secret string) SecretValidation {
+		return v.callerIdentity(ctx, vc, key, secret)
+	})
+}
+
+func (v aliyunValidator) callerIdentity(ctx context.Context, vc *validationContext, key, secret string) SecretValidation {
+	params := url.Values{
+		"Action":           {"GetCallerIdentity"},
+		"Version":          {"2015-04-01"},
+		"Format":           {"JSON"},
+		"AccessKeyId":      {key},
+		"SignatureMethod":  {"HMAC-SHA1"},
+		"SignatureVersion": {"1.0"},
+		"SignatureNonce":   {randomToken()},
+		"Timestamp":        {time.Now().UTC().Format("2006-01-02T15:04:05Z")},
+	}
+	query := aliyunEscape(params.Encode())
+	toSign := "GET&%2F&" + aliyunEscape(url.QueryEscape(query))
+	mac := hmac.New(sha1.New, []byte(secret+"&"))
+	mac.Write([]byte(toSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	target := vc.endpoint(v) + "/?" + query + "&Signature=" + aliyunEscape(url.QueryEscape(signature))
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	status, body, err := vc.do(req)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+
+	var resp struct {
+		Arn     string `json:"Arn"`
+		Code    string `json:"Code"`
+		Message string `json:"Message"`
+	}
+	json.Unmarshal(body, &resp)
+	switch {
+	case status == http.StatusOK && resp.Arn != "":
+		return SecretValidation{Status: secretValid, Detail: resp.Arn}
+	case strings.HasPrefix(resp.Code, "InvalidAccessKeyId"):
+		return SecretValidation{Status: secretInvalid, Detail: "AccessKey 不存在或已禁用"}
+	case resp.Code == "SignatureDoesNotMatch":
+		return SecretValidation{Status: secretInvalid, Detail: "AccessKey 存在，Secret 不匹配"}
+	}
+	return SecretValidation{Status: secretUnknown, Detail: fmt.Sprintf("HTTP %d %s", status, resp.Code)}
+}
+
+// aliyunEscape 将 url.QueryEscape 的结果转换为阿里云要求的 RFC 3986 编码
+func aliyunEscape(s string) string {
+	return strings.NewReplacer("+", "%20", "*", "%2A", "%7E", "~").Replace(s)
+}
+
+// --- GitHub ---
+
+type githubValidator struct{}
+
+func (githubValidator) Name() string                { return "github" }
+func (githubValidator) DefaultEndpoint() string     { return "https://api.github.com" }
+func (githubValidator) Supports(ruleID string) bool { return ruleID == "github-token" }
+
+func (v githubValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	req, err := http.NewRequestWithContext(ctx, "GET", vc.endpoint(v)+"/user", nil)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	req.Header.Set("Authorization", "token "+f.Value)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	status, body, err := vc.do(req)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	switch status {
+	case http.StatusOK:
+		var user struct {
+			Login string `json:"login"`
+		}
+		json.Unmarshal(body, &user)
+		return SecretValidation{Status: secretValid, Detail: "login: " + user.Login}
+	case http.StatusUnauthorized:
+		return SecretValidation{Status: secretInvalid, Detail: "Bad credentials"}
+	}
+	return SecretValidation{Status: secretUnknown, Detail: fmt.Sprintf("HTTP %d", status)}
+}
+
+// --- Slack ---
+
+type slackValidator struct{}
+
+func (slackValidator) Name() string                { return "slack" }
+func (slackValidator) DefaultEndpoint() string     { return "https://slack.com" }
+func (slackValidator) Supports(ruleID string) bool { return ruleID == "slack-token" }
+
+func (v slackValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	req, err := http.NewRequestWithContext(ctx, "POST", vc.endpoint(v)+"/api/auth.test", nil)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	req.Header.Set("Authorization", "Bearer "+f.Value)
+	_, body, err := vc.do(req)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+
+	var resp struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		Team  string `json:"team"`
+		User  string `json:"user"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: "响应不是 JSON"}
+	}
+	if resp.OK {
+		return SecretValidation{Status: secretValid, Detail: fmt.Sprintf("team: %s, user: %s", resp.Team, resp.User)}
+	}
+	switch resp.Error {
+	case "invalid_auth", "not_authed", "account_inactive", "token_revoked", "token_expired":
+		return SecretValidation{Status: secretInvalid, Detail: resp.Error}
+	}
+	return SecretValidation{Status: secretUnknown, Detail: resp.Error}
+}
+
+// slackWebhookValidator 发送空消息体：有效的 Webhook 返回 400 invalid_payload / no_text，不会真正发出消息
+type slackWebhookValidator struct{}
+
+func (slackWebhookValidator) Name() string                { return "slack-webhook" }
+func (slackWebhookValidator) DefaultEndpoint() string     { return "" }
+func (slackWebhookValidator) Supports(ruleID string) bool { return ruleID == "slack-webhook" }
+
+func (v slackWebhookValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	status, body, err := vc.postJSON(ctx, vc.webhookURL(v, f.Value), "{}")
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	msg := strings.TrimSpace(string(body))
+	switch {
+	case status == http.StatusBadRequest && (msg == "invalid_payload" || msg == "no_text"):
+		return SecretValidation{Status: secretValid, Detail: msg}
+	case status == http.StatusForbidden || status == http.StatusNotFound || status == http.StatusGone:
+		return SecretValidation{Status: secretInvalid, Detail: msg}
+	}
+	return SecretValidation{Status: secretUnknown, Detail: fmt.Sprintf("HTTP %d %s", status, msg)}
+}
+
+// --- DingTalk / WeCom ---
+
+// webhookErr 钉钉与企业微信机器人接口的错误响应
+type webhookErr struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// dingTalkValidator 发送空消息体：token 不存在时返回 300001 / 300005，否则返回参数错误，不会真正发出消息
+type dingTalkValidator struct{}
+
+func (dingTalkValidator) Name() string                { return "dingtalk" }
+func (dingTalkValidator) DefaultEndpoint() string     { return "" }
+func (dingTalkValidator) Supports(ruleID string) bool { return ruleID == "dingtalk-webhook" }
+
+func (v dingTalkValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	return validateRobotWebhook(ctx, vc, vc.webhookURL(v, f.Value), map[int]bool{300001: true, 300005: true}, map[int]bool{130101: true})
+}
+
+// weComValidator 发送空消息体：key 无效时返回 93000，否则返回消息格式错误，不会真正发出消息
+type weComValidator struct{}
+
+func (weComValidator) Name() string                { return "wecom" }
+func (weComValidator) DefaultEndpoint() string     { return "" }
+func (weComValidator) Supports(ruleID string) bool { return ruleID == "wecom-webhook" }
+
+func (v weComValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	return validateRobotWebhook(ctx, vc, vc.webhookURL(v, f.Value), map[int]bool{93000: true}, map[int]bool{45009: true})
+}
+
+// validateRobotWebhook invalidCodes 表示地址无效，unknownCodes（如限流）无法判断，其余错误码说明地址有效
+func validateRobotWebhook(ctx context.Context, vc *validationContext, target string, invalidCodes, unknownCodes map[int]bool) SecretValidation {
+	status, body, err := vc.postJSON(ctx, target, "{}")
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	var resp webhookErr
+	if status != http.StatusOK || json.Unmarshal(body, &resp) != nil {
+		return SecretValidation{Status: secretUnknown, Detail: fmt.Sprintf("HTTP %d", status)}
+	}
+	detail := fmt.Sprintf("errcode %d: %s", resp.ErrCode, resp.ErrMsg)
+	switch {
+	case invalidCodes[resp.ErrCode]:
+		return SecretValidation{Status: secretInvalid, Detail: detail}
+	case unknownCodes[resp.ErrCode]:
+		return SecretValidation{Status: secretUnknown, Detail: detail}
+	}
+	return SecretValidation{Status: secretValid, Detail: detail}
+}
+
+// --- Generic Bearer ---
+
+// bearerValidator 对比携带与不携带令牌时指定地址的响应：仅携带令牌时通过认证视为有效
+type bearerValidator struct{}
+
+func (bearerValidator) Name() string            { return "bearer" }
+func (bearerValidator) DefaultEndpoint() string { return "" }
+func (bearerValidator) Supports(ruleID string) bool {
+	return ruleID == "bearer-token" || ruleID == "jwt" || ruleID == "generic-api-key"
+}
+
+func (v bearerValidator) Validate(ctx context.Context, vc *validationContext, f SecretFinding) SecretValidation {
+	target := vc.config.BearerURL
+	if target == "" {
+		target = vc.endpoint(v)
+	}
+	if target == "" {
+		return SecretValidation{Status: secretUnknown, Detail: "未配置 Bearer 验证地址"}
+	}
+
+	send := func(token string) (int, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+		if err != nil {
+			return 0, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		status, _, err := vc.do(req)
+		return status, err
+	}
+
+	baseline, err := send("")
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+	status, err := send(f.Value)
+	if err != nil {
+		return SecretValidation{Status: secretUnknown, Detail: err.Error()}
+	}
+
+	detail := fmt.Sprintf("HTTP %d -> %d", baseline, status)
+	denied := func(code int) bool { return code == http.StatusUnauthorized || code == http.StatusForbidden }
+	switch {
+	case denied(baseline) && status >= 200 && status < 300:
+		return SecretValidation{Status: secretValid, Detail: detail}
+	case denied(status):
+		return SecretValidation{Status: secretInvalid, Detail: detail}
+	}
+	return SecretValidation{Status: secretUnknown, Detail: detail}
+}
@@ -25,9 +25,12 @@ type InfoService struct {
 	bfService  *BruteForceService
 	scanCtx    context.Context
 	scanCancel context.CancelFunc
+	scanDone   chan struct{} // 当前扫描任务结束时关闭
 	paused     atomic.Bool
-	mu         sync.Mutex // 保护 scanCtx/scanCancel
+	mu         sync.Mutex // 保护 scanCtx/scanCancel/scanDone/detectCancel
 	dbQueue    chan func()
+
+	detectCancel context.CancelFunc // 单独发起的 CDN/WAF 识别，StopScan 时一并取消
 }
 
 func NewInfoService(dbManager *db.Manager, bfService *BruteForceService) *InfoService {
@@ -51,10 +54,59 @@ func (s *InfoService) Startup(ctx context.Context) {
 	logger.Info("信息搜集服务已启动")
 }
 
+// startScanTask 取消正在运行的扫描任务，并在新的扫描上下文中启动 run
+func (s *InfoService) startScanTask(run func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startScanTaskLocked(run)
+}
+
+func (s *InfoService) startScanTaskLocked(run func()) {
+	if s.scanCancel != nil {
+		s.scanCancel() // 如果已有任务在运行，则取消
+	}
+	s.scanCtx, s.scanCancel = context.WithCancel(context.Background())
+	s.paused.Store(false) // 重置暂停状态
+	done := make(chan struct{})
+	s.scanDone = done
+
+	go func() {
+		defer close(done)
+		run()
+	}()
+}
+
+// QueueDirScan 在当前扫描任务结束后启动目录扫描，不打断用户正在运行的任务
+// 等待期间用户又启动了新任务时继续排在其后
+func (s *InfoService) QueueDirScan(config DirScanConfig) {
+	go func() {
+		for {
+			s.mu.Lock()
+			done := s.scanDone
+			select {
+			case <-done:
+				done = nil
+			default:
+			}
+			if done == nil {
+				s.startScanTaskLocked(func() { s.runDirScan(config) })
+				s.mu.Unlock()
+				return
+			}
+			s.mu.Unlock()
+			<-done
+		}
+	}()
+}
+
 // StopScan 停止当前扫描任务
 func (s *InfoService) StopScan() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.detectCancel != nil {
+		s.detectCancel()
+		s.detectCancel = nil
+	}
 	if s.scanCancel != nil {
 		s.scanCancel()
 		logger.Info("扫描任务已停止")
@@ -0,0 +1,496 @@
+package infogather
+
+import (
+	"JAttack/internal/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// SMBInfoConfig SMB/NetBIOS 主机信息搜集配置
+type SMBInfoConfig struct {
+	Target      string `json:"target"`      // IP / CIDR / 域名，逗号分隔
+	Concurrency int    `json:"concurrency"` // 并发数
+	Timeout     int    `json:"timeout"`     // 超时时间（毫秒）
+}
+
+// SMBHostInfo 通过 SMB 协商 / NTLM Challenge / NetBIOS 名称查询获得的主机信息
+type SMBHostInfo struct {
+	IP              string `json:"ip"`
+	NetBIOSName     string `json:"netbios_name"`
+	NetBIOSDomain   string `json:"netbios_domain"`
+	Hostname        string `json:"hostname"`
+	DNSDomain       string `json:"dns_domain"`
+	DNSForest       string `json:"dns_forest"`
+	OS              string `json:"os"`
+	OSBuild         string `json:"os_build"`
+	SMBDialect      string `json:"smb_dialect"`
+	SigningEnabled  bool   `json:"signing_enabled"`
+	SigningRequired bool   `json:"signing_required"`
+	MAC             string `json:"mac"`
+}
+
+// Signing SMB 签名状态描述
+func (h *SMBHostInfo) Signing() string {
+	switch {
+	case h.SigningRequired:
+		return "required"
+	case h.SigningEnabled:
+		return "enabled"
+	case h.SMBDialect != "":
+		return "disabled"
+	}
+	return ""
+}
+
+// Summary 用于日志和结果列表的单行描述
+func (h *SMBHostInfo) Summary() string {
+	var parts []string
+	if h.Hostname != "" {
+		parts = append(parts, "主机名: "+h.Hostname)
+	} else if h.NetBIOSName != "" {
+		parts = append(parts, "主机名: "+h.NetBIOSName)
+	}
+	if h.NetBIOSDomain != "" {
+		parts = append(parts, "域: "+h.NetBIOSDomain)
+	}
+	if h.DNSDomain != "" {
+		parts = append(parts, "DNS域: "+h.DNSDomain)
+	}
+	if h.OS != "" {
+		parts = append(parts, "系统: "+h.OS)
+	}
+	if h.SMBDialect != "" {
+		parts = append(parts, fmt.Sprintf("SMB %s 签名: %s", h.SMBDialect, h.Signing()))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// StartSMBInfoScan 启动 SMB/NetBIOS 主机信息搜集任务（无需认证）
+func (s *InfoService) StartSMBInfoScan(config SMBInfoConfig) {
+	s.startScanTask(func() { s.runSMBInfoScan(config) })
+}
+
+func (s *InfoService) runSMBInfoScan(config SMBInfoConfig) {
+	defer func() {
+		s.emitLog("SMB 信息搜集任务完成")
+		s.emitComplete()
+	}()
+
+	ips, err := parseTarget(config.Target)
+	if err != nil {
+		s.emitLog(fmt.Sprintf("目标解析失败: %v", err))
+		return
+	}
+
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 50
+	}
+	timeout := 3 * time.Second
+	if config.Timeout > 0 {
+		timeout = time.Duration(config.Timeout) * time.Millisecond
+	}
+
+	s.emitLog(fmt.Sprintf("开始 SMB/NetBIOS 信息搜集，目标数量: %d", len(ips)))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ip := range ips {
+		if s.waitIfPaused() {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+
+		if i%10 == 0 || i == len(ips)-1 {
+			s.emitProgress(float64(i+1) / float64(len(ips)) * 100)
+		}
+
+		go func(ipAddr string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if info := GatherSMBInfo(s.scanCtx, ipAddr, timeout); info != nil {
+				s.recordSMBInfo(info)
+			}
+		}(ip)
+	}
+	wg.Wait()
+}
+
+// recordSMBInfo 保存主机信息到资产库并推送到前端
+func (s *InfoService) recordSMBInfo(info *SMBHostInfo) {
+	s.emitLog(fmt.Sprintf("[SMB] %s %s", info.IP, info.Summary()))
+
+	s.dbQueue <- func() {
+		assetID, err := s.dbManager.UpsertAsset(info.IP, info.OS, true)
+		if err != nil {
+			logger.Error("保存主机失败", "IP", info.IP, "错误", err)
+			return
+		}
+		hostname := info.Hostname
+		if hostname == "" {
+			hostname = info.NetBIOSName
+		}
+		if err := s.dbManager.UpdateAssetHostInfo(assetID, hostname, info.NetBIOSDomain, info.DNSDomain, info.OSBuild, info.Signing()); err != nil {
+			logger.Error("保存主机信息失败", "IP", info.IP, "错误", err)
+		}
+	}
+
+	s.saveResult(info.IP, "SMBInfo", info.Summary())
+}
+
+// GatherSMBInfo 匿名收集主机信息，445 与 137/udp 均无响应时返回 nil
+// go-smb2 不暴露协商与 NTLM Challenge 细节，这里直接构造报文
+func GatherSMBInfo(ctx context.Context, ip string, timeout time.Duration) *SMBHostInfo {
+	info := &SMBHostInfo{IP: ip}
+	found := false
+
+	select {
+	case <-ctx.Done():
+		return nil
+	default:
+	}
+
+	if err := smb2Probe(ctx, ip, timeout, info); err == nil {
+		found = true
+	} else {
+		logger.Debug("SMB 探测失败", "IP", ip, "错误", err)
+	}
+
+	if err := netbiosProbe(ctx, ip, timeout, info); err == nil {
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return info
+}
+
+// probeConn 在 ctx 取消时中断阻塞的读写
+type probeConn struct {
+	net.Conn
+	stop func() bool
+}
+
+func (c *probeConn) Close() error {
+	c.stop()
+	return c.Conn.Close()
+}
+
+// dialProbe 按 ctx 建立连接，读写期限取 ctx 截止时间与 ioTimeout 中较早者
+func dialProbe(ctx context.Context, network, addr string, timeout, ioTimeout time.Duration) (net.Conn, error) {
+	conn, err := (&net.Dialer{Timeout: timeout}).DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(ioTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	return &probeConn{Conn: conn, stop: stop}, nil
+}
+
+// --- SMB2 Negotiate / NTLM Challenge ---
+
+var smb2Dialects = map[uint16]string{
+	0x0202: "2.0.2",
+	0x0210: "2.1",
+	0x0300: "3.0",
+	0x0302: "3.0.2",
+}
+
+func smb2Probe(ctx context.Context, ip string, timeout time.Duration, info *SMBHostInfo) error {
+	conn, err := dialProbe(ctx, "tcp", net.JoinHostPort(ip, "445"), timeout, timeout*2)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// 1. Negotiate
+	if err := writeNetBIOS(conn, buildSMB2Negotiate()); err != nil {
+		return err
+	}
+	resp, err := readNetBIOS(conn)
+	if err != nil {
+		return err
+	}
+	if len(resp) < 64+8 || !bytes.Equal(resp[:4], []byte{0xFE, 'S', 'M', 'B'}) {
+		return errors.New("invalid SMB2 negotiate response")
+	}
+	body := resp[64:]
+	securityMode := binary.LittleEndian.Uint16(body[2:4])
+	dialect := binary.LittleEndian.Uint16(body[4:6])
+	info.SigningEnabled = securityMode&0x01 != 0
+	info.SigningRequired = securityMode&0x02 != 0
+	info.SMBDialect = smb2Dialects[dialect]
+	if info.SMBDialect == "" {
+		info.SMBDialect = fmt.Sprintf("0x%04x", dialect)
+	}
+
+	// 2. Session Setup (NTLMSSP_NEGOTIATE)，服务端返回的 Challenge 中携带主机信息
+	if err := writeNetBIOS(conn, buildSMB2SessionSetup()); err != nil {
+		return err
+	}
+	resp, err = readNetBIOS(conn)
+	if err != nil {
+		return err
+	}
+	idx := bytes.Index(resp, []byte("NTLMSSP\x00"))
+	if idx < 0 {
+		return nil // 协商成功，但服务端未返回 NTLM Challenge
+	}
+	parseNTLMChallenge(resp[idx:], info)
+	return nil
+}
+
+func smb2Header(command uint16, messageID uint64) []byte {
+	h := make([]byte, 64)
+	copy(h[0:4], []byte{0xFE, 'S', 'M', 'B'})
+	binary.LittleEndian.PutUint16(h[4:6], 64) // StructureSize
+	binary.LittleEndian.PutUint16(h[12:14], command)
+	binary.LittleEndian.PutUint16(h[14:16], 1) // CreditRequest
+	binary.LittleEndian.PutUint64(h[24:32], messageID)
+	return h
+}
+
+func buildSMB2Negotiate() []byte {
+	dialects := []uint16{0x0202, 0x0210, 0x0300, 0x0302}
+	body := make([]byte, 36+2*len(dialects))
+	binary.LittleEndian.PutUint16(body[0:2], 36) // StructureSize
+	binary.LittleEndian.PutUint16(body[2:4], uint16(len(dialects)))
+	binary.LittleEndian.PutUint16(body[4:6], 0x01) // SecurityMode: signing enabled
+	rand.Read(body[12:28])                         // ClientGuid
+	for i, d := range dialects {
+		binary.LittleEndian.PutUint16(body[36+2*i:], d)
+	}
+	return append(smb2Header(0x0000, 0), body...)
+}
+
+func buildSMB2SessionSetup() []byte {
+	ntlm := make([]byte, 40)
+	copy(ntlm[0:8], "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(ntlm[8:12], 1)           // NEGOTIATE_MESSAGE
+	binary.LittleEndian.PutUint32(ntlm[12:16], 0xe2088297) // NegotiateFlags (含 NEGOTIATE_VERSION / TARGET_INFO)
+	// Version: 10.0 build 19041, NTLM revision 15
+	ntlm[32], ntlm[33] = 10, 0
+	binary.LittleEndian.PutUint16(ntlm[34:36], 19041)
+	ntlm[39] = 0x0F
+
+	body := make([]byte, 24)
+	binary.LittleEndian.PutUint16(body[0:2], 25) // StructureSize
+	body[3] = 0x01                               // SecurityMode: signing enabled
+	binary.LittleEndian.PutUint16(body[12:14], 64+24)
+	binary.LittleEndian.PutUint16(body[14:16], uint16(len(ntlm)))
+	body = append(body, ntlm...)
+	return append(smb2Header(0x0001, 1), body...)
+}
+
+func writeNetBIOS(conn net.Conn, payload []byte) error {
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, uint32(len(payload))&0x00FFFFFF)
+	_, err := conn.Write(append(header, payload...))
+	return err
+}
+
+func readNetBIOS(conn net.Conn) ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header) & 0x00FFFFFF
+	if length > 1<<20 {
+		return nil, errors.New("SMB response too large")
+	}
+	buf := make([]byte, length)
+	_, err := io.ReadFull(conn, buf)
+	return buf, err
+}
+
+// parseNTLMChallenge 解析 NTLM CHALLENGE_MESSAGE 中的 TargetInfo (AV_PAIR) 与 Version
+func parseNTLMChallenge(msg []byte, info *SMBHostInfo) {
+	if len(msg) < 48 || binary.LittleEndian.Uint32(msg[8:12]) != 2 {
+		return
+	}
+
+	flags := binary.LittleEndian.Uint32(msg[20:24])
+	if flags&0x02000000 != 0 && len(msg) >= 56 { // NTLMSSP_NEGOTIATE_VERSION
+		major, minor := msg[48], msg[49]
+		build := binary.LittleEndian.Uint16(msg[50:52])
+		info.OSBuild = fmt.Sprintf("%d.%d.%d", major, minor, build)
+		info.OS = windowsVersionName(major, minor, build)
+	}
+
+	infoLen := int(binary.LittleEndian.Uint16(msg[40:42]))
+	infoOff := int(binary.LittleEndian.Uint32(msg[44:48]))
+	if infoOff <= 0 || infoOff+infoLen > len(msg) {
+		return
+	}
+	avPairs := msg[infoOff : infoOff+infoLen]
+	for len(avPairs) >= 4 {
+		avID := binary.LittleEndian.Uint16(avPairs[0:2])
+		avLen := int(binary.LittleEndian.Uint16(avPairs[2:4]))
+		if avID == 0 || 4+avLen > len(avPairs) { // MsvAvEOL
+			break
+		}
+		value := avPairs[4 : 4+avLen]
+		switch avID {
+		case 1:
+			info.NetBIOSName = decodeUTF16LE(value)
+		case 2:
+			info.NetBIOSDomain = decodeUTF16LE(value)
+		case 3:
+			info.Hostname = decodeUTF16LE(value)
+		case 4:
+			info.DNSDomain = decodeUTF16LE(value)
+		case 5:
+			info.DNSForest = decodeUTF16LE(value)
+		}
+		avPairs = avPairs[4+avLen:]
+	}
+}
+
+func decodeUTF16LE(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
+
+// windowsVersionName 将 NTLM Version 字段映射为可读的系统版本
+func windowsVersionName(major, minor byte, build uint16) string {
+	name := ""
+	switch {
+	case major == 5 && minor == 0:
+		name = "Windows 2000"
+	case major == 5 && minor == 1:
+		name = "Windows XP"
+	case major == 5 && minor == 2:
+		name = "Windows Server 2003"
+	case major == 6 && minor == 0:
+		name = "Windows Vista / Server 2008"
+	case major == 6 && minor == 1:
+		name = "Windows 7 / Server 2008 R2"
+	case major == 6 && minor == 2:
+		name = "Windows 8 / Server 2012"
+	case major == 6 && minor == 3:
+		name = "Windows 8.1 / Server 2012 R2"
+	case major == 10 && build >= 22000:
+		name = "Windows 11 / Server 2022+"
+	case major == 10 && build >= 20348:
+		name = "Windows Server 2022"
+	case major == 10 && build == 17763:
+		name = "Windows 10 / Server 2019"
+	case major == 10 && build == 14393:
+		name = "Windows 10 / Server 2016"
+	case major == 10:
+		name = "Windows 10"
+	default:
+		name = "Windows"
+	}
+	return fmt.Sprintf("%s (Build %d)", name, build)
+}
+
+// --- NetBIOS Node Status (NBSTAT) ---
+
+func netbiosProbe(ctx context.Context, ip string, timeout time.Duration, info *SMBHostInfo) error {
+	conn, err := dialProbe(ctx, "udp", net.JoinHostPort(ip, "137"), timeout, timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write(buildNBSTATQuery()); err != nil {
+		return err
+	}
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return err
+	}
+	return parseNBSTATResponse(buf[:n], info)
+}
+
+func buildNBSTATQuery() []byte {
+	pkt := make([]byte, 12, 50)
+	binary.BigEndian.PutUint16(pkt[0:2], uint16(time.Now().UnixNano()))
+	binary.BigEndian.PutUint16(pkt[4:6], 1) // QDCOUNT
+
+	// "*" 补齐到 16 字节后做一级编码
+	name := make([]byte, 16)
+	name[0] = '*'
+	pkt = append(pkt, 0x20)
+	for _, c := range name {
+		pkt = append(pkt, 'A'+(c>>4), 'A'+(c&0x0F))
+	}
+	pkt = append(pkt, 0x00)
+	pkt = append(pkt, 0x00, 0x21, 0x00, 0x01) // Type NBSTAT, Class IN
+	return pkt
+}
+
+func parseNBSTATResponse(resp []byte, info *SMBHostInfo) error {
+	if len(resp) < 12 || binary.BigEndian.Uint16(resp[6:8]) == 0 { // ANCOUNT
+		return errors.New("empty NBSTAT response")
+	}
+
+	// 跳过 RR_NAME
+	off := 12
+	for off < len(resp) {
+		l := int(resp[off])
+		if l == 0 {
+			off++
+			break
+		}
+		if l&0xC0 == 0xC0 {
+			off += 2
+			break
+		}
+		off += 1 + l
+	}
+	off += 10 // TYPE, CLASS, TTL, RDLENGTH
+	if off >= len(resp) {
+		return errors.New("truncated NBSTAT response")
+	}
+
+	count := int(resp[off])
+	off++
+	parsed := 0
+	for ; parsed < count && off+18 <= len(resp); parsed++ {
+		entry := resp[off : off+18]
+		off += 18
+
+		name := strings.TrimSpace(string(entry[:15]))
+		suffix := entry[15]
+		group := binary.BigEndian.Uint16(entry[16:18])&0x8000 != 0
+
+		switch {
+		case suffix == 0x00 && !group && info.NetBIOSName == "":
+			info.NetBIOSName = name
+		case suffix == 0x00 && group && info.NetBIOSDomain == "":
+			info.NetBIOSDomain = name
+		}
+	}
+	// 名称表不完整时 off 不在统计信息开头，不读取 MAC
+	if parsed == count && off+6 <= len(resp) {
+		mac := resp[off : off+6]
+		if !bytes.Equal(mac, make([]byte, 6)) {
+			info.MAC = net.HardwareAddr(mac).String()
+		}
+	}
+	return nil
+}
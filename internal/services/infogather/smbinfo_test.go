@@ -0,0 +1,278 @@
+package infogather
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// ntlmChallenge 构造 CHALLENGE_MESSAGE，version 为空时不设置 NTLMSSP_NEGOTIATE_VERSION
+func ntlmChallenge(version []byte, pairs map[uint16]string) []byte {
+	msg := make([]byte, 56)
+	copy(msg, "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(msg[8:12], 2)
+	if version != nil {
+		binary.LittleEndian.PutUint32(msg[20:24], 0x02000000)
+		copy(msg[48:56], version)
+	}
+
+	var info []byte
+	for id := uint16(1); id <= 5; id++ {
+		v, ok := pairs[id]
+		if !ok {
+			continue
+		}
+		value := utf16LE(v)
+		info = binary.LittleEndian.AppendUint16(info, id)
+		info = binary.LittleEndian.AppendUint16(info, uint16(len(value)))
+		info = append(info, value...)
+	}
+	info = append(info, 0, 0, 0, 0) // MsvAvEOL
+
+	binary.LittleEndian.PutUint16(msg[40:42], uint16(len(info)))
+	binary.LittleEndian.PutUint32(msg[44:48], uint32(len(msg)))
+	return append(msg, info...)
+}
+
+func utf16LE(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return b
+}
+
+func TestParseNTLMChallenge(t *testing.T) {
+	pairs := map[uint16]string{1: "DC01", 2: "CORP", 3: "dc01.corp.local", 4: "corp.local", 5: "corp.local"}
+
+	tests := []struct {
+		name string
+		msg  []byte
+		want SMBHostInfo
+	}{
+		{
+			name: "完整的 TargetInfo 与 Version",
+			msg:  ntlmChallenge([]byte{10, 0, 0x63, 0x45, 0, 0, 0, 15}, pairs),
+			want: SMBHostInfo{
+				NetBIOSName: "DC01", NetBIOSDomain: "CORP", Hostname: "dc01.corp.local", DNSDomain: "corp.local", DNSForest: "corp.local",
+				OS: "Windows 10 / Server 2019 (Build 17763)", OSBuild: "10.0.17763",
+			},
+		},
+		{
+			name: "未协商 Version",
+			msg:  ntlmChallenge(nil, map[uint16]string{1: "WS", 2: "WORKGROUP"}),
+			want: SMBHostInfo{NetBIOSName: "WS", NetBIOSDomain: "WORKGROUP"},
+		},
+		{
+			name: "非 ASCII 名称",
+			msg:  ntlmChallenge(nil, map[uint16]string{3: "服务器"}),
+			want: SMBHostInfo{Hostname: "服务器"},
+		},
+		{
+			name: "不是 CHALLENGE_MESSAGE",
+			msg: func() []byte {
+				m := ntlmChallenge(nil, pairs)
+				binary.LittleEndian.PutUint32(m[8:12], 1)
+				return m
+			}(),
+		},
+		{
+			name: "TargetInfo 越界",
+			msg: func() []byte {
+				m := ntlmChallenge(nil, pairs)
+				binary.LittleEndian.PutUint16(m[40:42], 0xFFFF)
+				return m
+			}(),
+		},
+		{
+			name: "AV_PAIR 长度越界",
+			msg: func() []byte {
+				m := ntlmChallenge(nil, map[uint16]string{1: "DC01"})
+				binary.LittleEndian.PutUint16(m[58:60], 0x100)
+				return m
+			}(),
+		},
+		{name: "报文过短", msg: []byte("NTLMSSP\x00\x02\x00\x00\x00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SMBHostInfo
+			parseNTLMChallenge(tt.msg, &got)
+			if got != tt.want {
+				t.Errorf("parseNTLMChallenge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowsVersionName(t *testing.T) {
+	tests := []struct {
+		major, minor byte
+		build        uint16
+		want         string
+	}{
+		{6, 1, 7601, "Windows 7 / Server 2008 R2 (Build 7601)"},
+		{6, 3, 9600, "Windows 8.1 / Server 2012 R2 (Build 9600)"},
+		{10, 0, 14393, "Windows 10 / Server 2016 (Build 14393)"},
+		{10, 0, 20348, "Windows Server 2022 (Build 20348)"},
+		{10, 0, 22631, "Windows 11 / Server 2022+ (Build 22631)"},
+		{10, 0, 19045, "Windows 10 (Build 19045)"},
+		{4, 0, 1381, "Windows (Build 1381)"},
+	}
+	for _, tt := range tests {
+		if got := windowsVersionName(tt.major, tt.minor, tt.build); got != tt.want {
+			t.Errorf("windowsVersionName(%d, %d, %d) = %q, want %q", tt.major, tt.minor, tt.build, got, tt.want)
+		}
+	}
+}
+
+type nbName struct {
+	name   string
+	suffix byte
+	group  bool
+}
+
+// nbstatResponse 构造 NBSTAT 应答，MAC 位于名称表之后
+func nbstatResponse(mac []byte, names ...nbName) []byte {
+	resp := make([]byte, 12)
+	binary.BigEndian.PutUint16(resp[6:8], 1) // ANCOUNT
+	resp = append(resp, 0x20)
+	for i := 0; i < 32; i++ {
+		resp = append(resp, 'A')
+	}
+	resp = append(resp, 0x00)
+	resp = append(resp, 0x00, 0x21, 0x00, 0x01, 0, 0, 0, 0, 0, 0) // TYPE, CLASS, TTL, RDLENGTH
+
+	resp = append(resp, byte(len(names)))
+	for _, n := range names {
+		entry := make([]byte, 18)
+		copy(entry, fmt.Sprintf("%-15s", n.name))
+		entry[15] = n.suffix
+		if n.group {
+			entry[16] = 0x80
+		}
+		resp = append(resp, entry...)
+	}
+	return append(resp, mac...)
+}
+
+func TestParseNBSTATResponse(t *testing.T) {
+	mac := []byte{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03}
+	tests := []struct {
+		name    string
+		resp    []byte
+		want    SMBHostInfo
+		wantErr bool
+	}{
+		{
+			name: "主机名、工作组与 MAC",
+			resp: nbstatResponse(mac,
+				nbName{"WS01", 0x00, false},
+				nbName{"WS01", 0x20, false},
+				nbName{"WORKGROUP", 0x00, true},
+				nbName{"WORKGROUP", 0x1e, true},
+			),
+			want: SMBHostInfo{NetBIOSName: "WS01", NetBIOSDomain: "WORKGROUP", MAC: "00:15:5d:01:02:03"},
+		},
+		{
+			name: "全零 MAC 被忽略",
+			resp: nbstatResponse(make([]byte, 6), nbName{"SAMBA", 0x00, false}),
+			want: SMBHostInfo{NetBIOSName: "SAMBA"},
+		},
+		{
+			name: "名称表截断时不把名称当作 MAC",
+			resp: nbstatResponse(mac, nbName{"WS01", 0x00, false}, nbName{"WORKGROUP", 0x00, true})[:70],
+		},
+		{
+			name:    "没有应答记录",
+			resp:    make([]byte, 12),
+			wantErr: true,
+		},
+		{
+			name:    "RR 头部截断",
+			resp:    nbstatResponse(nil)[:50],
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SMBHostInfo
+			err := parseNBSTATResponse(tt.resp, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNBSTATResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseNBSTATResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialProbeCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		// 接受连接但从不应答，模拟卡住的 445 端口
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				c.Close()
+			}
+		}()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+
+	tests := []struct {
+		name string
+		ctx  func() (context.Context, context.CancelFunc)
+	}{
+		{"取消", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+			return ctx, cancel
+		}},
+		{"截止时间早于读写超时", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 50*time.Millisecond)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			conn, err := dialProbe(ctx, "tcp", ln.Addr().String(), 5*time.Second, 10*time.Second)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			start := time.Now()
+			if _, err := readNetBIOS(conn); err == nil {
+				t.Fatal("expected the read to fail")
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("read returned after %v", elapsed)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := dialProbe(ctx, "tcp", ln.Addr().String(), 5*time.Second, 10*time.Second); err == nil {
+		t.Error("dialProbe() with a cancelled context succeeded")
+	}
+}
@@ -0,0 +1,241 @@
+package infogather
+
+import (
+	"JAttack/internal/pkg/logger"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxSourceMapSize source map 体积通常远大于脚本本身，单独放宽读取上限
+const maxSourceMapSize = 50 * 1024 * 1024
+
+// maxSourceFetches sourcesContent 缺失时最多单独下载的源文件数
+const maxSourceFetches = 100
+
+// sourceMappingRegex 匹配脚本末尾的 //# sourceMappingURL= 注释（兼容旧的 //@ 写法与 CSS 风格注释）
+var sourceMappingRegex = regexp.MustCompile(`(?m)(?://|/\*)[#@]\s*sourceMappingURL\s*=\s*(\S+?)\s*(?:\*/)?\s*$`)
+
+// sourceMap Source Map v3，支持带 sections 的索引格式
+type sourceMap struct {
+	Version        int       `json:"version"`
+	File           string    `json:"file"`
+	SourceRoot     string    `json:"sourceRoot"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+	Sections       []struct {
+		URL string     `json:"url"`
+		Map *sourceMap `json:"map"`
+	} `json:"sections"`
+}
+
+// recoveredSource 从 source map 还原的单个源文件
+type recoveredSource struct {
+	Path    string // 源文件在 map 中的名称，例如 webpack:///src/api/user.js
+	Content string
+}
+
+// sourceMapURL 返回脚本对应的 source map 地址：优先使用 sourceMappingURL 注释，否则猜测 <file>.js.map
+// 第二个返回值表示地址来自注释（而非猜测）
+func sourceMapURL(jsURL, content string) (string, bool) {
+	tail := content
+	if len(tail) > 4096 {
+		tail = tail[len(tail)-4096:]
+	}
+	if m := sourceMappingRegex.FindAllStringSubmatch(tail, -1); len(m) > 0 {
+		ref := m[len(m)-1][1]
+		if strings.HasPrefix(ref, "data:") {
+			return ref, true
+		}
+		if full, err := resolveURL(jsURL, ref); err == nil {
+			return full, true
+		}
+	}
+
+	u, err := url.Parse(jsURL)
+	if err != nil {
+		return "", false
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return u.String() + ".map", false
+}
+
+// decodeDataURL 解析内联的 data:application/json;base64,... source map
+func decodeDataURL(ref string) ([]byte, error) {
+	meta, data, ok := strings.Cut(strings.TrimPrefix(ref, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("data URL 格式无效")
+	}
+	if strings.HasSuffix(meta, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	s, err := url.PathUnescape(data)
+	return []byte(s), err
+}
+
+// parseSourceMap 解析 source map，sections 中的子 map 会被展开
+func parseSourceMap(data []byte) (*sourceMap, error) {
+	data = []byte(strings.TrimPrefix(string(data), ")]}'"))
+	var sm sourceMap
+	if err := json.Unmarshal(data, &sm); err != nil {
+		return nil, err
+	}
+	if len(sm.Sources) == 0 && len(sm.Sections) == 0 {
+		return nil, fmt.Errorf("不是有效的 source map")
+	}
+	return &sm, nil
+}
+
+// recoverSourceMap 下载并解析脚本的 source map，返回 map 地址与还原出的源文件
+func (s *JSFinderService) recoverSourceMap(ctx context.Context, jsURL, content string, timeout time.Duration) (string, []recoveredSource) {
+	mapURL, declared := sourceMapURL(jsURL, content)
+	if mapURL == "" {
+		return "", nil
+	}
+
+	var data []byte
+	if strings.HasPrefix(mapURL, "data:") {
+		decoded, err := decodeDataURL(mapURL)
+		if err != nil {
+			return "", nil
+		}
+		data = decoded
+		mapURL = jsURL + " (inline)"
+	} else {
+		body, err := s.fetchLimit(ctx, mapURL, timeout, maxSourceMapSize)
+		if err != nil {
+			if declared {
+				logger.Debug("下载 source map 失败", "url", mapURL, "错误", err)
+			}
+			return "", nil
+		}
+		data = []byte(body)
+	}
+
+	sm, err := parseSourceMap(data)
+	if err != nil {
+		return "", nil
+	}
+
+	base := mapURL
+	if strings.HasSuffix(base, " (inline)") {
+		base = jsURL
+	}
+	// map 内容不可信，只下载与 map 同一主机的源文件，避免被引向其他主机
+	mapHost := ""
+	if u, err := url.Parse(base); err == nil {
+		mapHost = u.Host
+	}
+
+	var sources []recoveredSource
+	fetched := 0
+	var collect func(m *sourceMap, base string)
+	collect = func(m *sourceMap, base string) {
+		for _, section := range m.Sections {
+			if section.Map != nil {
+				collect(section.Map, base)
+			}
+		}
+		for i, name := range m.Sources {
+			if i < len(m.SourcesContent) && m.SourcesContent[i] != nil {
+				sources = append(sources, recoveredSource{Path: name, Content: *m.SourcesContent[i]})
+				continue
+			}
+			// 缺少 sourcesContent 时尝试按 sourceRoot 下载原文件，webpack:// 等虚拟路径无法下载
+			if fetched >= maxSourceFetches || strings.Contains(name, "://") && !strings.HasPrefix(name, "http") {
+				continue
+			}
+			full, err := resolveURL(base, m.SourceRoot+name)
+			if err != nil || !strings.HasPrefix(full, "http") {
+				continue
+			}
+			if u, err := url.Parse(full); err != nil || !strings.EqualFold(u.Host, mapHost) {
+				logger.Debug("跳过其他主机上的源文件", "url", full)
+				continue
+			}
+			fetched++
+			if body, err := s.fetch(ctx, full, timeout); err == nil {
+				sources = append(sources, recoveredSource{Path: name, Content: body})
+			}
+		}
+	}
+	collect(sm, base)
+	return mapURL, sources
+}
+
+// saveSourceTree 将还原的源文件按原始目录结构写入 <项目数据目录>/sourcemaps/<host>/，返回写入的根目录
+// 单个文件写入失败（例如同名的文件与目录冲突）时跳过该文件
+func (s *JSFinderService) saveSourceTree(jsURL string, sources []recoveredSource) (string, error) {
+	if s.dbManager == nil || s.dbManager.DataDir() == "" {
+		return "", nil
+	}
+	u, err := url.Parse(jsURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("URL 无效: %s", jsURL)
+	}
+	root := filepath.Join(s.dbManager.DataDir(), "sourcemaps", sanitizePathPart(u.Host))
+
+	for _, src := range sources {
+		rel := sourceFilePath(src.Path)
+		if rel == "" {
+			continue
+		}
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			logger.Warn("创建源文件目录失败", "路径", path, "错误", err)
+			continue
+		}
+		if err := os.WriteFile(path, []byte(src.Content), 0644); err != nil {
+			logger.Warn("写入源文件失败", "路径", path, "错误", err)
+		}
+	}
+	return root, nil
+}
+
+// sourceFilePath 将 map 中的源文件名转换为安全的相对路径
+// webpack:///./src/api.js -> src/api.js；webpack://app/~/lodash/index.js -> app/node_modules/lodash/index.js
+func sourceFilePath(name string) string {
+	if _, after, ok := strings.Cut(name, "://"); ok {
+		name = after
+	}
+	name, _, _ = strings.Cut(name, "?")
+	name = strings.ReplaceAll(name, "\\", "/")
+
+	var parts []string
+	for _, part := range strings.Split(name, "/") {
+		switch part {
+		case "", ".", "..":
+			continue
+		case "~":
+			part = "node_modules"
+		}
+		parts = append(parts, sanitizePathPart(part))
+	}
+	return filepath.Join(parts...)
+}
+
+// sanitizePathPart 替换文件名中在常见文件系统上不合法的字符
+func sanitizePathPart(part string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 {
+			return '_'
+		}
+		return r
+	}, part)
+}
+
+// isThirdPartySource 第三方依赖中的常量与示例地址噪音较多，不参与分析
+func isThirdPartySource(name string) bool {
+	return strings.Contains(name, "node_modules/") || strings.Contains(name, "/~/") || strings.Contains(name, "(webpack)")
+}
@@ -0,0 +1,193 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSourceFilePath(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"webpack:///./src/api/user.js", "src/api/user.js"},
+		{"webpack://app/~/lodash/index.js", "app/node_modules/lodash/index.js"},
+		{"webpack:///src/views/Home.vue?5a3f", "src/views/Home.vue"},
+		{"../../../etc/passwd", "etc/passwd"},
+		{"/abs/../../secret.js", "abs/secret.js"},
+		{`src\windows\..\path.ts`, "src/windows/path.ts"},
+		{"webpack:///src/a:b*c?.js", "src/a_b_c"},
+		{"src/con\x01trol<x>|.js", "src/con_trol_x__.js"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := sourceFilePath(tt.name); got != filepath.FromSlash(tt.want) {
+			t.Errorf("sourceFilePath(%q) = %q, want %q", tt.name, got, filepath.FromSlash(tt.want))
+		}
+	}
+}
+
+func TestSourceMapURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsURL       string
+		content     string
+		want        string
+		fromComment bool
+	}{
+		{
+			name:        "相对地址注释",
+			jsURL:       "https://example.com/static/js/app.js",
+			content:     "var a=1;\n//# sourceMappingURL=app.js.map",
+			want:        "https://example.com/static/js/app.js.map",
+			fromComment: true,
+		},
+		{
+			name:        "旧写法与 CSS 风格注释",
+			jsURL:       "https://example.com/app.js",
+			content:     "var a=1;\n/*@ sourceMappingURL=/maps/app.map */",
+			want:        "https://example.com/maps/app.map",
+			fromComment: true,
+		},
+		{
+			name:        "内联 data URL",
+			jsURL:       "https://example.com/app.js",
+			content:     "x()\n//# sourceMappingURL=data:application/json;base64,e30=",
+			want:        "data:application/json;base64,e30=",
+			fromComment: true,
+		},
+		{
+			name:    "无注释时猜测 .map 并去掉查询串",
+			jsURL:   "https://example.com/app.js?v=3",
+			content: "var a=1;",
+			want:    "https://example.com/app.js.map",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fromComment := sourceMapURL(tt.jsURL, tt.content)
+			if got != tt.want || fromComment != tt.fromComment {
+				t.Errorf("sourceMapURL() = %q, %v, want %q, %v", got, fromComment, tt.want, tt.fromComment)
+			}
+		})
+	}
+}
+
+func TestParseSourceMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		sources int
+		wantErr bool
+	}{
+		{"普通 map", `{"version":3,"sources":["a.js","b.js"],"sourcesContent":["1",null]}`, 2, false},
+		{"XSSI 前缀", `)]}'{"version":3,"sources":["a.js"]}`, 1, false},
+		{"索引格式", `{"version":3,"sections":[{"offset":{"line":0,"column":0},"map":{"version":3,"sources":["a.js"]}}]}`, 0, false},
+		{"没有源文件", `{"version":3,"mappings":""}`, 0, true},
+		{"不是 JSON", `<html></html>`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm, err := parseSourceMap([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSourceMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && len(sm.Sources) != tt.sources {
+				t.Errorf("len(Sources) = %d, want %d", len(sm.Sources), tt.sources)
+			}
+		})
+	}
+}
+
+func TestDecodeDataURL(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{"data:application/json;base64,eyJhIjoxfQ==", `{"a":1}`, false},
+		{"data:application/json;charset=utf-8,%7B%22a%22%3A1%7D", `{"a":1}`, false},
+		{"data:application/json;base64", "", true},
+	}
+	for _, tt := range tests {
+		got, err := decodeDataURL(tt.ref)
+		if (err != nil) != tt.wantErr || string(got) != tt.want {
+			t.Errorf("decodeDataURL(%q) = %q, %v, want %q, wantErr %v", tt.ref, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSaveSourceTree(t *testing.T) {
+	dir := t.TempDir()
+	m := db.NewManager()
+	m.SetPath(filepath.Join(dir, "project.db"))
+	s := NewJSFinderService(m)
+
+	root, err := s.saveSourceTree("https://app.example.com/static/app.js", []recoveredSource{
+		{Path: "webpack:///src/api", Content: "file"},
+		{Path: "webpack:///src/api/index.js", Content: "conflicts with the file above"},
+		{Path: "webpack:///src/main.js", Content: "main"},
+		{Path: "webpack:///../../outside.js", Content: "outside"},
+	})
+	if err != nil {
+		t.Fatalf("saveSourceTree() error = %v", err)
+	}
+	if want := filepath.Join(dir, "project_data", "sourcemaps", "app.example.com"); root != want {
+		t.Errorf("root = %q, want %q", root, want)
+	}
+
+	want := map[string]string{"src/api": "file", "src/main.js": "main", "outside.js": "outside"}
+	for rel, content := range want {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil || string(data) != content {
+			t.Errorf("%s = %q, %v, want %q", rel, data, err, content)
+		}
+	}
+}
+
+func TestRecoverSourceMapSameHost(t *testing.T) {
+	var offHost int32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&offHost, 1)
+		fmt.Fprint(w, "leaked")
+	}))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/static/app.js.map":
+			fmt.Fprintf(w, `{"version":3,"sources":["inline.js","../src/local.js","%s/secret.js"],"sourcesContent":["inline"]}`, other.URL)
+		case "/src/local.js":
+			fmt.Fprint(w, "local")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewJSFinderService(nil)
+	mapURL, sources := s.recoverSourceMap(context.Background(), srv.URL+"/static/app.js", "var a=1;", time.Second)
+	if mapURL != srv.URL+"/static/app.js.map" {
+		t.Errorf("mapURL = %q", mapURL)
+	}
+	var got []string
+	for _, src := range sources {
+		got = append(got, src.Path+"="+src.Content)
+	}
+	sort.Strings(got)
+	if fmt.Sprint(got) != "[../src/local.js=local inline.js=inline]" {
+		t.Errorf("sources = %q", got)
+	}
+	if n := atomic.LoadInt32(&offHost); n != 0 {
+		t.Errorf("fetched %d sources from another host", n)
+	}
+}
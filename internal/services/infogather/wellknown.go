@@ -0,0 +1,430 @@
+package infogather
+
+import (
+	"JAttack/internal/db"
+	"JAttack/internal/pkg/logger"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// wellKnownFiles 站点元数据文件及其在 web_urls 中的来源标记
+var wellKnownFiles = []struct {
+	Path   string
+	Source string
+}{
+	{"/robots.txt", "robots"},
+	{"/sitemap.xml", "sitemap"},
+	{"/sitemap_index.xml", "sitemap"},
+	{"/.well-known/security.txt", "security.txt"},
+	{"/security.txt", "security.txt"},
+	{"/crossdomain.xml", "crossdomain"},
+	{"/clientaccesspolicy.xml", "crossdomain"},
+	{"/humans.txt", "well-known"},
+	{"/.well-known/openid-configuration", "well-known"},
+	{"/.well-known/oauth-authorization-server", "well-known"},
+	{"/.well-known/assetlinks.json", "well-known"},
+	{"/.well-known/apple-app-site-association", "well-known"},
+	{"/.well-known/change-password", "well-known"},
+}
+
+const (
+	maxSitemaps    = 50    // 最多解析的 sitemap 文件数（含嵌套索引）
+	maxSitemapURLs = 10000 // 最多收集的 sitemap URL 数
+)
+
+// WellKnownFile 单个元数据文件的请求结果
+type WellKnownFile struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+	Status int    `json:"status"`
+	Size   int    `json:"size"`
+	Found  bool   `json:"found"` // 状态码为 200 且内容格式符合预期
+}
+
+// WellKnownURL 从元数据文件中解析出的路径或 URL
+type WellKnownURL struct {
+	URL    string `json:"url"`
+	Source string `json:"source"`
+}
+
+// WellKnownResult robots.txt / sitemap.xml / security.txt 等元数据的收集结果
+type WellKnownResult struct {
+	Target   string          `json:"target"`
+	Files    []WellKnownFile `json:"files"`
+	URLs     []WellKnownURL  `json:"urls"`     // Disallow / Allow 路径、sitemap URL 等，均为完整 URL
+	Contacts []string        `json:"contacts"` // security.txt 中的 Contact / Policy 等字段
+	Domains  []string        `json:"domains"`  // crossdomain.xml / clientaccesspolicy.xml 允许的域
+}
+
+// Directories 返回与 Target 同源的 URL 所在目录，用作目录扫描种子
+func (r *WellKnownResult) Directories() []string {
+	base, err := url.Parse(r.Target)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, item := range r.URLs {
+		u, err := url.Parse(item.URL)
+		if err != nil || u.Host != base.Host || u.Scheme != base.Scheme {
+			continue
+		}
+		dir := u.Path
+		if !strings.HasSuffix(dir, "/") {
+			dir = path.Dir(dir)
+		}
+		dir = strings.TrimRight(dir, "/")
+		if dir == "" || dir == "." || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		dirs = append(dirs, u.Scheme+"://"+u.Host+dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// Scripts 返回其中的 JS 文件 URL
+func (r *WellKnownResult) Scripts() []string {
+	var list []string
+	for _, item := range r.URLs {
+		if u, err := url.Parse(item.URL); err == nil && strings.HasSuffix(strings.ToLower(u.Path), ".js") {
+			list = append(list, item.URL)
+		}
+	}
+	return list
+}
+
+// HarvestWellKnown 收集并解析目标站点的元数据文件
+func HarvestWellKnown(ctx context.Context, target string, opts *HTTPRequestOptions, timeout time.Duration) *WellKnownResult {
+	target = normalizeDirTarget(target)
+	if opts == nil {
+		opts = &HTTPRequestOptions{}
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	h := &wellKnownHarvester{
+		ctx:  ctx,
+		opts: opts,
+		client: &http.Client{
+			Timeout: timeout,
+			Jar:     opts.newCookieJar(target),
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 3 {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+		result:   &WellKnownResult{Target: target, Files: []WellKnownFile{}, URLs: []WellKnownURL{}, Contacts: []string{}, Domains: []string{}},
+		seenURLs: make(map[string]bool),
+		sitemaps: make(map[string]bool),
+	}
+
+	for _, f := range wellKnownFiles {
+		if ctx.Err() != nil {
+			break
+		}
+		h.harvest(target+f.Path, f.Source)
+	}
+	return h.result
+}
+
+type wellKnownHarvester struct {
+	ctx      context.Context
+	opts     *HTTPRequestOptions
+	client   *http.Client
+	result   *WellKnownResult
+	seenURLs map[string]bool
+	sitemaps map[string]bool
+}
+
+func (h *wellKnownHarvester) get(fileURL string) (int, []byte, string, error) {
+	req, err := h.opts.newRequest(h.ctx, "GET", fileURL, "")
+	if err != nil {
+		return 0, nil, "", err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, nil, "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 10*1024*1024))
+
+	// 压缩的 sitemap（.xml.gz）
+	if len(body) > 2 && body[0] == 0x1f && body[1] == 0x8b {
+		if zr, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if plain, err := io.ReadAll(io.LimitReader(zr, 50*1024*1024)); err == nil {
+				body = plain
+			}
+		}
+	}
+	return resp.StatusCode, body, resp.Header.Get("Content-Type"), nil
+}
+
+func (h *wellKnownHarvester) harvest(fileURL, source string) {
+	if source == "sitemap" && h.sitemaps[fileURL] {
+		return
+	}
+	status, body, contentType, err := h.get(fileURL)
+	if err != nil {
+		return
+	}
+
+	file := WellKnownFile{URL: fileURL, Source: source, Status: status, Size: len(body)}
+	defer func() { h.result.Files = append(h.result.Files, file) }()
+	if status != 200 || len(body) == 0 {
+		return
+	}
+
+	text := string(body)
+	lower := strings.ToLower(text)
+	isHTML := strings.Contains(strings.ToLower(contentType), "html") || strings.Contains(lower, "<html")
+
+	switch source {
+	case "robots":
+		if isHTML || !(strings.Contains(lower, "user-agent") || strings.Contains(lower, "disallow") || strings.Contains(lower, "sitemap")) {
+			return
+		}
+		file.Found = true
+		h.parseRobots(fileURL, text)
+	case "sitemap":
+		file.Found = h.parseSitemap(fileURL, body)
+	case "security.txt":
+		if isHTML || !strings.Contains(lower, "contact:") {
+			return
+		}
+		file.Found = true
+		h.parseSecurityTxt(text)
+	case "crossdomain":
+		if !strings.Contains(lower, "cross-domain-policy") && !strings.Contains(lower, "access-policy") {
+			return
+		}
+		file.Found = true
+		h.parseCrossDomain(text)
+	default:
+		if isHTML {
+			return
+		}
+		file.Found = true
+		if strings.Contains(strings.ToLower(contentType), "json") || strings.HasPrefix(strings.TrimSpace(text), "{") {
+			h.parseJSONURLs(fileURL, body)
+		}
+	}
+}
+
+func (h *wellKnownHarvester) addURL(base, ref, source string) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return
+	}
+	full, err := resolveURL(base, ref)
+	if err != nil || h.seenURLs[full] {
+		return
+	}
+	h.seenURLs[full] = true
+	h.result.URLs = append(h.result.URLs, WellKnownURL{URL: full, Source: source})
+}
+
+// parseRobots 解析 Disallow / Allow 路径与 Sitemap 声明，通配符之后的部分会被截断
+func (h *wellKnownHarvester) parseRobots(fileURL, text string) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "disallow", "allow":
+			if idx := strings.IndexAny(value, "*$"); idx >= 0 {
+				value = value[:idx]
+			}
+			if value != "" && value != "/" {
+				h.addURL(fileURL, value, "robots")
+			}
+		case "sitemap":
+			if full, err := resolveURL(fileURL, value); err == nil && !h.sitemaps[full] {
+				h.harvest(full, "sitemap")
+			}
+		}
+	}
+}
+
+type sitemapDoc struct {
+	XMLName  xml.Name `xml:""`
+	URLs     []string `xml:"url>loc"`
+	Sitemaps []string `xml:"sitemap>loc"`
+}
+
+// parseSitemap 解析 urlset 与 sitemapindex，嵌套索引递归处理
+func (h *wellKnownHarvester) parseSitemap(fileURL string, body []byte) bool {
+	if h.sitemaps[fileURL] || len(h.sitemaps) >= maxSitemaps {
+		return false
+	}
+	h.sitemaps[fileURL] = true
+
+	var doc sitemapDoc
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return false
+	}
+	if doc.XMLName.Local != "urlset" && doc.XMLName.Local != "sitemapindex" {
+		return false
+	}
+
+	for _, loc := range doc.URLs {
+		if len(h.result.URLs) >= maxSitemapURLs {
+			break
+		}
+		h.addURL(fileURL, loc, "sitemap")
+	}
+	for _, loc := range doc.Sitemaps {
+		if h.ctx.Err() != nil {
+			break
+		}
+		if full, err := resolveURL(fileURL, strings.TrimSpace(loc)); err == nil && !h.sitemaps[full] {
+			h.harvest(full, "sitemap")
+		}
+	}
+	return true
+}
+
+func (h *wellKnownHarvester) parseSecurityTxt(text string) {
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "contact", "policy", "hiring", "acknowledgments", "encryption", "canonical":
+			h.result.Contacts = append(h.result.Contacts, strings.TrimSpace(key)+": "+strings.TrimSpace(value))
+		}
+	}
+}
+
+var crossDomainRegex = regexp.MustCompile(`(?i)<(?:allow-access-from|allow-http-request-headers-from|domain)\s[^>]*(?:domain|uri)\s*=\s*["']([^"']+)["']`)
+
+func (h *wellKnownHarvester) parseCrossDomain(text string) {
+	for _, m := range crossDomainRegex.FindAllStringSubmatch(text, -1) {
+		h.result.Domains = append(h.result.Domains, m[1])
+	}
+	h.result.Domains = unique(h.result.Domains)
+}
+
+// parseJSONURLs 提取 openid-configuration 等 JSON 文件中指向本站的 URL
+func (h *wellKnownHarvester) parseJSONURLs(fileURL string, body []byte) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return
+	}
+	base, _ := url.Parse(fileURL)
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case []interface{}:
+			for _, item := range val {
+				walk(item)
+			}
+		case string:
+			if u, err := url.Parse(val); err == nil && base != nil && u.Host == base.Host && strings.HasPrefix(u.Scheme, "http") {
+				h.addURL(fileURL, val, "well-known")
+			}
+		}
+	}
+	walk(v)
+}
+
+// recordWellKnown 将收集结果写入资产库：URL 写入 web_urls，元信息写入信息搜集记录
+func recordWellKnown(dbManager *db.Manager, webServiceID int64, result *WellKnownResult) {
+	if dbManager == nil || webServiceID <= 0 {
+		return
+	}
+	for _, f := range result.Files {
+		if f.Found {
+			dbManager.UpsertWebURL(webServiceID, f.URL, f.Source, f.Status, "")
+		}
+	}
+	for _, u := range result.URLs {
+		if err := dbManager.UpsertWebURL(webServiceID, u.URL, u.Source, 0, ""); err != nil {
+			logger.Warn("保存 URL 失败", "url", u.URL, "错误", err)
+		}
+	}
+}
+
+func (r *WellKnownResult) found() []string {
+	var found []string
+	for _, f := range r.Files {
+		if f.Found {
+			found = append(found, strings.TrimPrefix(f.URL, r.Target))
+		}
+	}
+	return found
+}
+
+// Summary 元数据收集结果的简要描述
+func (r *WellKnownResult) Summary() string {
+	parts := []string{fmt.Sprintf("元数据文件: %s", strings.Join(r.found(), ", ")), fmt.Sprintf("路径 %d 个", len(r.URLs))}
+	if len(r.Contacts) > 0 {
+		parts = append(parts, "security.txt: "+strings.Join(r.Contacts, "; "))
+	}
+	if len(r.Domains) > 0 {
+		parts = append(parts, "跨域策略允许: "+strings.Join(r.Domains, ", "))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// HarvestWellKnown 收集单个站点的 robots.txt、sitemap.xml、security.txt 等元数据并写入资产库
+func (s *InfoService) HarvestWellKnown(target string) *WellKnownResult {
+	target = normalizeDirTarget(target)
+	var webServiceID int64
+	if s.dbManager != nil {
+		var err error
+		_, webServiceID, err = ensureWebService(s.dbManager, target)
+		if err != nil {
+			logger.Warn("关联 WebService 失败", "目标", target, "错误", err)
+		}
+	}
+	return s.harvestAndSaveWellKnown(context.Background(), target, webServiceID, nil, 10*time.Second)
+}
+
+func (s *InfoService) harvestAndSaveWellKnown(ctx context.Context, target string, webServiceID int64, opts *HTTPRequestOptions, timeout time.Duration) *WellKnownResult {
+	result := HarvestWellKnown(ctx, target, opts, timeout)
+	recordWellKnown(s.dbManager, webServiceID, result)
+
+	if len(result.found()) > 0 {
+		s.emitLog(fmt.Sprintf("[元数据] %s %s", target, result.Summary()))
+		s.saveResult(target, "WellKnown", result.Summary())
+	}
+	return result
+}
@@ -30,6 +30,7 @@ func (s *SettingsService) InitDatabase(path string) error {
 		return err
 	}
 	s.dbManager.SetDB(database)
+	s.dbManager.SetPath(path)
 	logger.Info("数据库初始化成功")
 	
 	// 可选: 如果需要下次自动加载，可以在此处保存路径到配置文件
@@ -76,6 +76,11 @@ func main() {
 	logService := logs.NewLogService(logDir)
 	jsFinderService := infogather.NewJSFinderService(dbManager)
 	assetService := infogather.NewAssetService(dbManager)
+	crawlerService := infogather.NewCrawlerService(dbManager, infoService, jsFinderService)
+	repeaterService := infogather.NewRepeaterService(dbManager)
+	paramMinerService := infogather.NewParamMinerService(dbManager)
+	apiDocService := infogather.NewAPIDocService(dbManager)
+	graphQLService := infogather.NewGraphQLService(dbManager)
 
 	// 尝试自动初始化数据库
 	if _, err := os.Stat(defaultDBPath); err == nil || os.IsNotExist(err) {
@@ -108,6 +113,11 @@ func main() {
 			logService.Startup(ctx)
 			jsFinderService.Startup(ctx)
 			assetService.Startup(ctx)
+			crawlerService.Startup(ctx)
+			repeaterService.Startup(ctx)
+			paramMinerService.Startup(ctx)
+			apiDocService.Startup(ctx)
+			graphQLService.Startup(ctx)
 			logger.Info("服务启动完成")
 		},
 		Bind: []interface{}{
@@ -120,6 +130,11 @@ func main() {
 			logService,
 			jsFinderService,
 			assetService,
+			crawlerService,
+			repeaterService,
+			paramMinerService,
+			apiDocService,
+			graphQLService,
 		},
 	})
 